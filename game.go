@@ -1,29 +1,219 @@
 package main
 
 import (
+	"context"
+	"crypto/subtle"
 	abts "dnd-helper/src/abilities"
+	"dnd-helper/src/campaign"
 	char "dnd-helper/src/character"
 	cond "dnd-helper/src/condition"
+	"dnd-helper/src/dice"
 	inv "dnd-helper/src/inventory"
+	"dnd-helper/src/party"
+	"dnd-helper/src/rules"
+	"dnd-helper/src/store"
+	"dnd-helper/src/validation"
 	"encoding/json"
+	"errors"
+	"flag"
 	"fmt"
-	"log"
+	"io"
+	"log/slog"
+	"math/rand"
 	"net/http"
+	"os"
+	"os/signal"
 	"runtime/debug"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
 	"time"
 )
 
-func mockSendDbRequest(data any) error {
-	// Simulate sending data to a database
-	log.Printf("Mock sending data to DB: %v", data)
-	return nil
+// shutdownTimeout bounds how long a shutdown waits for in-flight requests
+const shutdownTimeout = 15 * time.Second
+
+// maxNPCsPerRequest caps the count of /npc/generate
+const maxNPCsPerRequest = 50
+
+// defaultHistoryLimit is how many entries /characters/{name}/history returns
+// without a limit
+const defaultHistoryLimit = 50
+
+// maxCreateBodyBytes caps the body of /create-character
+const maxCreateBodyBytes = 1 << 20
+
+// decodeStrictJSON decodes the body into v, rejecting unknown fields and
+// bodies over maxBytes. It answers 413 for oversized bodies and 400 for
+// anything else that fails to decode. Handlers must stop when it returns false
+func decodeStrictJSON(w http.ResponseWriter, r *http.Request, maxBytes int64, v any) bool {
+	decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBytes))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(v); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			http.Error(w, fmt.Sprintf("Request body exceeds %d bytes", tooLarge.Limit), http.StatusRequestEntityTooLarge)
+			return false
+		}
+		http.Error(w, fmt.Sprintf("Invalid JSON: %v", err), http.StatusBadRequest)
+		return false
+	}
+	return true
+}
+
+// writeStoreError reports a failed character lookup: 404 when the character
+// does not exist, 500 when the store itself failed
+func writeStoreError(w http.ResponseWriter, ref string, err error) {
+	if errors.Is(err, store.ErrNotFound) {
+		http.Error(w, fmt.Sprintf("Character %s not found", ref), http.StatusNotFound)
+		return
+	}
+	slog.Error("Character store failed", "character", ref, "error", err)
+	http.Error(w, "Character store unavailable", http.StatusInternalServerError)
+}
+
+// sourceConditions names the custom conditions file in /readyz
+const sourceConditions = "conditions"
+
+// restoreAttempts is how often a character is saved again to undo a change
+// whose save failed, so a single failed write does not leave it half done
+const restoreAttempts = 3
+
+// restoreCharacter saves original back after a failed action, retrying up
+// to restoreAttempts times
+func restoreCharacter(characters store.CharacterStore, action string, original *char.Character) {
+	var err error
+	for range restoreAttempts {
+		if err = characters.Save(*original); err == nil {
+			return
+		}
+	}
+	slog.Error("Error restoring character after a failed "+action, "character", original.GetName(), "attempts", restoreAttempts, "error", err)
+}
+
+// removeCharacter deletes a character that did not exist before a failed
+// action, retrying up to restoreAttempts times. ErrNotFound means the write
+// never landed, so there is nothing to undo
+func removeCharacter(characters store.CharacterStore, action string, created *char.Character) {
+	var err error
+	for range restoreAttempts {
+		if err = characters.Delete(created.GetID()); err == nil || errors.Is(err, store.ErrNotFound) {
+			return
+		}
+	}
+	slog.Error("Error removing character after a failed "+action, "character", created.GetName(), "attempts", restoreAttempts, "error", err)
+}
+
+// writeCampaignError reports a failed campaign operation: 404 for unknown
+// campaigns, 409 for taken names, 400 otherwise
+func writeCampaignError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, campaign.ErrNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, campaign.ErrExists):
+		http.Error(w, err.Error(), http.StatusConflict)
+	default:
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	}
+}
+
+// writePartyError reports a failed party operation: 404 for unknown parties,
+// 409 for taken names and characters already in a party, 400 otherwise
+func writePartyError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, party.ErrNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, party.ErrExists), errors.Is(err, party.ErrAlreadyInParty):
+		http.Error(w, err.Error(), http.StatusConflict)
+	default:
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		slog.Error("Error encoding response", "error", err)
+	}
+}
+
+// newRequestRand returns a random source for a single request, since
+// *rand.Rand must not be shared between concurrent handlers
+func newRequestRand() *rand.Rand {
+	return rand.New(rand.NewSource(time.Now().UnixNano()))
+}
+
+// updatedAbilities applies new scores to a copy of abs. Scores left out keep
+// their current value and the whole set is redistributed at once, so the
+// order of the changes does not matter
+func updatedAbilities(abs abts.Abilities, scores map[string]int) (abts.Abilities, error) {
+	merged := abs.GetAllAbilities()
+	for name, value := range scores {
+		merged[name] = value
+	}
+	if err := abs.Redistribute(merged); err != nil {
+		return abts.Abilities{}, err
+	}
+	if err := abs.ValidateAbilities(); err != nil {
+		return abts.Abilities{}, err
+	}
+	return abs, nil
+}
+
+// withLegacyQuantity encodes the roster for /get-chars with every item also
+// carrying the misspelled "qantity" key older clients read.
+// Deprecated: drop the alias one release after "quantity" was introduced
+func withLegacyQuantity(roster []char.Character) ([]map[string]json.RawMessage, error) {
+	// Only the keys on the way to the items are decoded, everything else
+	// stays raw so ordered objects like the abilities keep their key order
+	docs := make([]map[string]json.RawMessage, 0, len(roster))
+	for i := range roster {
+		data, err := json.Marshal(&roster[i])
+		if err != nil {
+			return nil, err
+		}
+		var doc map[string]json.RawMessage
+		if err := json.Unmarshal(data, &doc); err != nil {
+			return nil, err
+		}
+		var inventory map[string]json.RawMessage
+		if err := json.Unmarshal(doc["inventory"], &inventory); err == nil {
+			var items []map[string]json.RawMessage
+			if err := json.Unmarshal(inventory["items"], &items); err == nil && items != nil {
+				for _, item := range items {
+					item["qantity"] = item["quantity"]
+				}
+				if inventory["items"], err = json.Marshal(items); err != nil {
+					return nil, err
+				}
+				if doc["inventory"], err = json.Marshal(inventory); err != nil {
+					return nil, err
+				}
+			}
+		}
+		docs = append(docs, doc)
+	}
+	return docs, nil
+}
+
+// quietPaths are polled by orchestrators and left out of the request log
+var quietPaths = map[string]bool{
+	"/healthz": true,
+	"/readyz":  true,
 }
 
 func withRequestLogging(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if quietPaths[r.URL.Path] {
+			next.ServeHTTP(w, r)
+			return
+		}
 		start := time.Now()
 		defer func() {
-			log.Printf("%s %s in %s", r.Method, r.URL.Path, time.Since(start))
+			slog.Info("Request", "method", r.Method, "path", r.URL.Path, "duration", time.Since(start))
 		}()
 		next.ServeHTTP(w, r)
 	})
@@ -33,7 +223,7 @@ func withRecovery(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		defer func() {
 			if x := recover(); x != nil {
-				log.Printf("panic: %v\n%s", x, debug.Stack())
+				slog.Error("Panic", "panic", x, "stack", string(debug.Stack()))
 				http.Error(w, "internal error", http.StatusInternalServerError)
 			}
 		}()
@@ -41,10 +231,187 @@ func withRecovery(next http.Handler) http.Handler {
 	})
 }
 
+// corsMethods and corsHeaders are what cross-origin requests may use
+const (
+	corsMethods = "GET, POST, PUT, DELETE, OPTIONS"
+	corsHeaders = "Content-Type, X-API-Key"
+)
+
+// withCORS lets browser clients on allowedOrigins call the API. "*" allows
+// every origin, otherwise allowedOrigins is a comma-separated list and only
+// a listed Origin is echoed back. Preflight requests are answered with 204
+// without reaching next
+func withCORS(allowedOrigins string, next http.Handler) http.Handler {
+	allowed := map[string]bool{}
+	for _, origin := range strings.Split(allowedOrigins, ",") {
+		if origin = strings.TrimSpace(origin); origin != "" {
+			allowed[origin] = true
+		}
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header := w.Header()
+		if allowed["*"] {
+			header.Set("Access-Control-Allow-Origin", "*")
+		} else {
+			header.Add("Vary", "Origin")
+			if origin := r.Header.Get("Origin"); allowed[origin] {
+				header.Set("Access-Control-Allow-Origin", origin)
+			}
+		}
+		header.Set("Access-Control-Expose-Headers", "ETag")
+
+		if r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != "" {
+			header.Set("Access-Control-Allow-Methods", corsMethods)
+			header.Set("Access-Control-Allow-Headers", corsHeaders)
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// publicPaths are served without an API key, so orchestrators can probe them
+var publicPaths = map[string]bool{
+	"/healthz": true,
+	"/readyz":  true,
+}
+
+// withAuth answers 401 unless the X-API-Key header matches apiKey. Paths in
+// publicPaths are exempt
+func withAuth(apiKey string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if publicPaths[r.URL.Path] {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if subtle.ConstantTimeCompare([]byte(r.Header.Get("X-API-Key")), []byte(apiKey)) != 1 {
+			http.Error(w, "Missing or invalid API key", http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// parseFaultConfig reads the failures to inject into the stores. failEvery
+// is a number of writes and latency a duration, empty means none
+func parseFaultConfig(failEvery, latency string, partial bool) (store.FaultConfig, error) {
+	config := store.FaultConfig{Partial: partial}
+	if failEvery != "" {
+		n, err := strconv.Atoi(failEvery)
+		if err != nil || n < 0 {
+			return store.FaultConfig{}, fmt.Errorf("invalid fault fail-every %q, must be a number of writes, 0 to disable", failEvery)
+		}
+		config.FailEvery = n
+	}
+	if latency != "" {
+		d, err := time.ParseDuration(latency)
+		if err != nil || d < 0 {
+			return store.FaultConfig{}, fmt.Errorf("invalid fault latency %q, must be a duration like 200ms", latency)
+		}
+		config.Latency = d
+	}
+	return config, nil
+}
+
+// setupLogging logs text from level on to stderr. level is one of debug,
+// info, warn or error, empty means info
+func setupLogging(level string) error {
+	var minLevel slog.Level
+	if level != "" {
+		if err := minLevel.UnmarshalText([]byte(level)); err != nil {
+			return fmt.Errorf("invalid log level %q: %w", level, err)
+		}
+	}
+	slog.SetDefault(slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: minLevel})))
+	return nil
+}
+
 func main() {
-	var characters []char.Character
-	mux := http.NewServeMux()
-	handler := withRecovery(withRequestLogging(mux))
+	// The log level comes from -log-level, falling back to LOG_LEVEL
+	logLevel := flag.String("log-level", os.Getenv("LOG_LEVEL"), "minimum log level: debug, info, warn or error (default info)")
+	// Browser clients may call the API from -cors-origin, falling back to
+	// CORS_ORIGIN and then to every origin
+	corsOrigin := flag.String("cors-origin", os.Getenv("CORS_ORIGIN"), "comma-separated origins allowed to call the API from a browser (default *)")
+	// Requests must carry the API key in X-API-Key. The key is read from
+	// API_KEY only, so it never shows up in the process list
+	apiKey := os.Getenv("API_KEY")
+	// Each client IP may send -rate-burst requests at once and -rate-limit
+	// requests per second after that, falling back to RATE_BURST and RATE_LIMIT
+	rateLimit := flag.String("rate-limit", os.Getenv("RATE_LIMIT"), "requests per second allowed per client IP, 0 disables rate limiting (default 10)")
+	rateBurst := flag.String("rate-burst", os.Getenv("RATE_BURST"), "requests a client IP may send at once (default 20)")
+	// Only a proxy in front of the server can be trusted to set X-Forwarded-For
+	trustProxy := flag.Bool("trust-proxy", os.Getenv("TRUST_PROXY") == "true", "take the client IP from X-Forwarded-For, only behind a trusted proxy")
+	// Failures can be injected into the stores to test how clients cope with
+	// a failing disk. Never enable them in production
+	faultFailEvery := flag.String("fault-fail-every", os.Getenv("FAULT_FAIL_EVERY"), "fail every Nth store write, for testing only (default 0, never)")
+	faultLatency := flag.String("fault-latency", os.Getenv("FAULT_LATENCY"), "delay every store call, e.g. 200ms, for testing only")
+	faultPartial := flag.Bool("fault-partial", os.Getenv("FAULT_PARTIAL") == "true", "apply the failed store writes before failing them, for testing only")
+	flag.Parse()
+	if err := setupLogging(*logLevel); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	rate, burst, err := parseRateLimit(*rateLimit, *rateBurst)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+
+	faults, err := parseFaultConfig(*faultFailEvery, *faultLatency, *faultPartial)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+
+	memory := store.NewMemoryStore()
+	var characters store.CharacterStore = memory
+	var templates store.TemplateStore = memory
+	if faults.Enabled() {
+		faulty, err := store.NewFaultStore(memory, memory, faults)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(2)
+		}
+		characters, templates = faulty, faulty
+		slog.Warn("Injecting store failures", "failEvery", faults.FailEvery, "latency", faults.Latency, "partial", faults.Partial)
+	}
+
+	// Custom condition definitions are kept in a YAML file so they survive restarts
+	conditionsFile := os.Getenv("CONDITIONS_FILE")
+	if conditionsFile == "" {
+		conditionsFile = "conditions.yaml"
+	}
+	if err := cond.DefaultRegistry.LoadFile(conditionsFile); err != nil {
+		slog.Error("Failed to load custom conditions", "file", conditionsFile, "error", err)
+		os.Exit(1)
+	}
+	// Characters remember this many of their latest changes
+	if limit := os.Getenv("HISTORY_LIMIT"); limit != "" {
+		n, err := strconv.Atoi(limit)
+		if err != nil || n <= 0 {
+			slog.Error("HISTORY_LIMIT must be a positive number", "value", limit)
+			os.Exit(1)
+		}
+		char.HistoryLimit = n
+	}
+
+	mux := newAPI(characters, templates, conditionsFile)
+	if *corsOrigin == "" {
+		*corsOrigin = "*"
+	}
+	// CORS comes before auth, so preflights and 401s carry the CORS headers
+	var api http.Handler = mux
+	if apiKey != "" {
+		api = withAuth(apiKey, mux)
+	} else {
+		slog.Warn("API_KEY is not set, the API is open to every client")
+	}
+	handler := withRequestLogging(withCORS(*corsOrigin, api))
+	// Rate limiting comes first, so rejected clients cost as little as possible
+	if rate > 0 {
+		handler = withRateLimit(newRateLimiter(rate, burst, *trustProxy), handler)
+	}
+	handler = withRecovery(handler)
 
 	srv := &http.Server{
 		Addr:              ":8080",
@@ -55,200 +422,2311 @@ func main() {
 		MaxHeaderBytes:    1 << 20,
 	}
 
+	// Drain in-flight requests on SIGINT/SIGTERM instead of dropping them
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	serverErr := make(chan error, 1)
+	go func() {
+		slog.Info("Starting server", "addr", srv.Addr)
+		serverErr <- srv.ListenAndServe()
+	}()
+
+	select {
+	case err := <-serverErr:
+		slog.Error("Server failed to start", "error", err)
+		os.Exit(1)
+	case <-ctx.Done():
+	}
+	stop()
+
+	slog.Info("Shutting down, waiting for in-flight requests", "timeout", shutdownTimeout)
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		slog.Error("Server shutdown failed", "error", err)
+		os.Exit(1)
+	}
+	slog.Info("Server stopped")
+}
+
+// newAPI registers every endpoint on a new mux. characters is where every
+// handler loads and saves characters, templates holds the character
+// definitions saved with /templates and conditionsFile is where custom
+// condition definitions are persisted
+func newAPI(characters store.CharacterStore, templates store.TemplateStore, conditionsFile string) *http.ServeMux {
+	// persistence tracks every write, so /readyz can report a store that
+	// keeps failing
+	persistence := store.NewMonitor(characters, templates)
+	characters, templates = persistence, persistence
+	// rosterMu serializes the load-change-save sequences of the handlers, so
+	// concurrent requests never overwrite each other's changes. Handlers that
+	// only read take the read lock
+	var rosterMu sync.RWMutex
+	// campaigns hold the crit and fumble rules rolls are made with, the
+	// loot pile and the encounter log
+	campaigns := campaign.NewRegistry()
+	// parties groups characters by ID, each character in at most one party
+	parties := party.NewRegistry()
+	// legacyQuantityWarning warns about the deprecated /get-chars key once,
+	// later calls only log it at debug level
+	var legacyQuantityWarning sync.Once
+
+	// findCharacter loads a character by ID, falling back to the name for
+	// older clients. Names are not unique, so the first match wins. The
+	// result is a copy, so changes must be saved with characters.Save.
+	// Changes to it are attributed to the actor of ctx
+	findCharacter := func(ctx context.Context, ref string) (*char.Character, error) {
+		character, err := characters.Get(ref)
+		if err == nil {
+			character.SetActor(char.ActorFrom(ctx))
+			return &character, nil
+		}
+		if !errors.Is(err, store.ErrNotFound) {
+			return nil, err
+		}
+		roster, err := characters.List()
+		if err != nil {
+			return nil, err
+		}
+		for i := range roster {
+			if roster[i].GetName() == ref {
+				roster[i].SetActor(char.ActorFrom(ctx))
+				return &roster[i], nil
+			}
+		}
+		return nil, fmt.Errorf("%w: %s", store.ErrNotFound, ref)
+	}
+	// saveCharacter stores a changed character, answering 500 when the
+	// store fails. Handlers must stop when it returns false
+	saveCharacter := func(w http.ResponseWriter, character *char.Character) bool {
+		if err := characters.Save(*character); err != nil {
+			slog.Error("Error saving character", "character", character.GetName(), "error", err)
+			http.Error(w, "Character store unavailable", http.StatusInternalServerError)
+			return false
+		}
+		return true
+	}
+	// saveCharacters stores characters changed together, in order. When a
+	// save fails, that character and the ones saved before it are put back
+	// as in originals, since a failed write may still have landed, so the
+	// change is stored for all of them or for none. A nil original marks a
+	// new character, which is deleted instead. Handlers must stop when it
+	// returns false
+	saveCharacters := func(w http.ResponseWriter, action string, changed, originals []*char.Character) bool {
+		for i, character := range changed {
+			if saveCharacter(w, character) {
+				continue
+			}
+			for j, original := range originals[:i+1] {
+				if original == nil {
+					removeCharacter(characters, action, changed[j])
+					continue
+				}
+				restoreCharacter(characters, action, original)
+			}
+			return false
+		}
+		return true
+	}
+
+	// partyMembers loads the members of p. Members deleted from the store
+	// since they joined are skipped
+	partyMembers := func(p *party.Party) ([]char.Character, error) {
+		var members []char.Character
+		for _, id := range p.Members() {
+			character, err := characters.Get(id)
+			if errors.Is(err, store.ErrNotFound) {
+				slog.Warn("Party member no longer exists", "party", p.Name, "character", id)
+				continue
+			}
+			if err != nil {
+				return nil, err
+			}
+			members = append(members, character)
+		}
+		return members, nil
+	}
+
+	// logCheck records a skill check in the encounter log of a campaign
+	logCheck := func(campaignName, actor string, result rules.SkillCheckResult) {
+		entry := campaign.Entry{
+			Kind:      campaign.EntryCheck,
+			Actor:     actor,
+			Natural:   result.Natural,
+			Success:   result.Success,
+			Critical:  result.Critical,
+			CritRange: result.CritRange,
+			Fumble:    result.Fumble,
+		}
+		if err := campaigns.Record(campaignName, entry); err != nil {
+			slog.Error("Error recording skill check", "campaign", campaignName, "error", err)
+		}
+	}
+
+	// transferItem moves quantity units of item from one character to
+	// another and answers with both inventories. Both characters are
+	// copies, so nothing is saved if the transfer fails
+	transferItem := func(w http.ResponseWriter, r *http.Request, from, to, item string, quantity int) {
+		rosterMu.Lock()
+		defer rosterMu.Unlock()
+		source, err := findCharacter(r.Context(), from)
+		if err != nil {
+			writeStoreError(w, from, err)
+			return
+		}
+		destination, err := findCharacter(r.Context(), to)
+		if err != nil {
+			writeStoreError(w, to, err)
+			return
+		}
+		if source.Inventory().GetItem(item) == nil {
+			http.Error(w, fmt.Sprintf("Item %s not found in the inventory of %s", item, source.GetName()), http.StatusNotFound)
+			return
+		}
+
+		originals := []*char.Character{source.Clone(), destination.Clone()}
+		if err := source.GiveItem(destination, item, quantity); err != nil {
+			http.Error(w, fmt.Sprintf("Cannot transfer item: %v", err), http.StatusConflict)
+			return
+		}
+		// Put the items back if a save fails, so they are neither lost nor doubled
+		if !saveCharacters(w, "transfer", []*char.Character{source, destination}, originals) {
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"from": map[string]interface{}{"name": source.GetName(), "inventory": source.GetInventory()},
+			"to":   map[string]interface{}{"name": destination.GetName(), "inventory": destination.GetInventory()},
+		})
+	}
+
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		count, err := characters.Count()
+		if err != nil {
+			slog.Error("Health check failed", "error", err)
+			writeJSON(w, http.StatusServiceUnavailable, map[string]interface{}{"status": "unavailable"})
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"status":     "ok",
+			"characters": count,
+		})
+	})
+
+	// /readyz fails while a store keeps failing to persist, so a load
+	// balancer can stop sending writes that would be lost
+	mux.HandleFunc("/readyz", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		if err := persistence.Ready(); err != nil {
+			slog.Warn("Readiness check failed", "error", err)
+			writeJSON(w, http.StatusServiceUnavailable, map[string]interface{}{"status": "failing", "error": err.Error()})
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{"status": "ready"})
+	})
+
 	mux.HandleFunc("/create-character", func(w http.ResponseWriter, r *http.Request) {
 		if r.Method != http.MethodPost {
 			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 			return
 		}
 
-		// Define request structure matching character structure
-		type ItemDTO struct {
-			Name        string `json:"name"`
-			Quantity    int    `json:"quantity"`
-			Condition   string `json:"condition"`
-			Description string `json:"description"`
-			Abilities   *struct {
-				Strength     int `json:"strength"`
-				Luck         int `json:"luck"`
-				Charisma     int `json:"charisma"`
-				Agility      int `json:"agility"`
-				Perception   int `json:"perception"`
-				Intelligence int `json:"intelligence"`
-			} `json:"abilities,omitempty"`
+		var charReq []CreateCharacterRequest
+
+		// Parse JSON request body. Unknown fields are rejected so typos such
+		// as "strenght" do not silently fall back to zero
+		if !decodeStrictJSON(w, r, maxCreateBodyBytes, &charReq) {
+			return
+		}
+		defer r.Body.Close()
+
+		// Unknown catalog names are a mistake in the request rather than an
+		// invalid item, so they fail the request even with allowPartial
+		var unknownCatalog []string
+		for i, req := range charReq {
+			for j, itemDTO := range req.Inventory.Items {
+				if itemDTO.Catalog == "" {
+					continue
+				}
+				var unknown *inv.UnknownCatalogItemError
+				if _, err := inv.DefaultCatalog.Create(itemDTO.Catalog, 1); errors.As(err, &unknown) {
+					unknownCatalog = append(unknownCatalog, fmt.Sprintf("[%d].inventory.items[%d]: %v", i, j, err))
+				}
+			}
+		}
+		if len(unknownCatalog) > 0 {
+			http.Error(w, strings.Join(unknownCatalog, "\n"), http.StatusBadRequest)
+			return
+		}
+
+		// Validate the whole batch before creating anything. With allowPartial
+		// invalid items are skipped with a warning instead of failing the request
+		allowPartial := r.URL.Query().Get("allowPartial") == "true"
+		var drafts []characterDraft
+		var problems, warnings validation.ValidationErrors
+		for i, req := range charReq {
+			draft, reqProblems := validateCharacterRequest(fmt.Sprintf("[%d]", i), req)
+			problems = append(problems, reqProblems...)
+			if allowPartial {
+				warnings = append(warnings, draft.skipped...)
+			} else {
+				problems = append(problems, draft.skipped...)
+			}
+			drafts = append(drafts, draft)
+		}
+		if len(problems) > 0 {
+			writeJSON(w, http.StatusUnprocessableEntity, map[string]interface{}{
+				"fieldErrors": problems,
+			})
+			return
 		}
 
-		type CreateCharacterRequest struct {
-			Race      string `json:"race"`
-			Name      string `json:"name"`
-			Class     string `json:"class"`
-			Inventory struct {
-				Items []ItemDTO `json:"items"`
-			} `json:"inventory"`
-			Abilities struct {
-				Strength     int `json:"strength"`
-				Luck         int `json:"luck"`
-				Charisma     int `json:"charisma"`
-				Agility      int `json:"agility"`
-				Perception   int `json:"perception"`
-				Intelligence int `json:"intelligence"`
-			} `json:"abilities"`
-			Condition string `json:"condition"`
+		// Create each character from the validated drafts, all of them or none
+		created := make([]*char.Character, len(drafts))
+		for i, draft := range drafts {
+			created[i] = draft.build()
+		}
+		rosterMu.Lock()
+		saved := saveCharacters(w, "create", created, make([]*char.Character, len(created)))
+		rosterMu.Unlock()
+		if !saved {
+			return
 		}
 
-		var charReq []CreateCharacterRequest
+		// A single character keeps the original response shape
+		responseData := map[string]interface{}{
+			"message": "Characters created successfully",
+		}
+		if len(created) == 1 {
+			responseData["message"] = "Character created successfully"
+			responseData["character"] = created[0]
+			w.Header().Set("ETag", `"`+created[0].Hash()+`"`)
+		} else {
+			responseData["characters"] = created
+		}
+		if len(warnings) > 0 {
+			responseData["warnings"] = warnings
+		}
+		writeJSON(w, http.StatusCreated, responseData)
+	})
+
+	mux.HandleFunc("/update-character", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPut {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
 
-		// Parse JSON request body
-		decoder := json.NewDecoder(r.Body)
-		if err := decoder.Decode(&charReq); err != nil {
+		// Omitted fields are left unchanged. name selects the character,
+		// newName renames it
+		var updateReq struct {
+			Name      string         `json:"name"`
+			NewName   *string        `json:"newName,omitempty"`
+			Race      *string        `json:"race,omitempty"`
+			Class     *string        `json:"class,omitempty"`
+			Condition *string        `json:"condition,omitempty"`
+			Abilities map[string]int `json:"abilities,omitempty"`
+			// CountCompanionWeight counts the companions' inventories
+			// against the carry capacity
+			CountCompanionWeight *bool `json:"countCompanionWeight,omitempty"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&updateReq); err != nil {
 			http.Error(w, fmt.Sprintf("Invalid JSON: %v", err), http.StatusBadRequest)
 			return
 		}
 		defer r.Body.Close()
 
-		// Create each character from request data
-		for _, req := range charReq {
-			abilities, err := abts.NewAbilities(
-				req.Abilities.Strength,
-				req.Abilities.Luck,
-				req.Abilities.Charisma,
-				req.Abilities.Agility,
-				req.Abilities.Perception,
-				req.Abilities.Intelligence,
-			)
-			if err != nil {
-				http.Error(w, fmt.Sprintf("Invalid abilities: %v", err), http.StatusBadRequest)
+		rosterMu.Lock()
+		defer rosterMu.Unlock()
+		character, err := findCharacter(r.Context(), updateReq.Name)
+		if err != nil {
+			writeStoreError(w, updateReq.Name, err)
+			return
+		}
+
+		// character is a copy, so a failed update is never saved
+		if updateReq.NewName != nil {
+			if err := character.SetName(*updateReq.NewName); err != nil {
+				http.Error(w, fmt.Sprintf("Invalid name: %v", err), http.StatusBadRequest)
 				return
 			}
-
-			// Create inventory and add items
-			inventory := inv.NewInventory()
-			for _, itemDTO := range req.Inventory.Items {
-				var itemAbilities *abts.Abilities
-				if itemDTO.Abilities != nil {
-					itemAbs, err := abts.NewAbilities(
-						itemDTO.Abilities.Strength,
-						itemDTO.Abilities.Luck,
-						itemDTO.Abilities.Charisma,
-						itemDTO.Abilities.Agility,
-						itemDTO.Abilities.Perception,
-						itemDTO.Abilities.Intelligence,
-					)
-					if err != nil {
-						http.Error(w, fmt.Sprintf("Invalid item abilities: %v", err), http.StatusBadRequest)
-						return
-					}
-					itemAbilities = &itemAbs
-				}
-
-				item, err := inv.NewItem(
-					itemDTO.Name,
-					itemDTO.Quantity,
-					itemAbilities,
-					cond.NewCondition(itemDTO.Condition),
-					itemDTO.Description,
-				)
-				if err != nil {
-					http.Error(w, fmt.Sprintf("Invalid item: %v", err), http.StatusBadRequest)
-					return
-				}
-				inventory.AddItem(item)
-			}
-
-			// Create condition and character
-			condition := cond.NewCondition(req.Condition)
-			character := char.NewCharacter(req.Race, req.Name, req.Class, abilities, *inventory, condition)
-			characters = append(characters, *character)
-			// Get abilities and inventory
-			charAbilities := character.GetAbilities()
-			charInventory := character.GetInventory()
-
-			// Prepare inventory response (only public fields will serialize)
-			inventoryItems := []map[string]interface{}{}
-			for _, item := range charInventory.GetAllItems() {
-				itemData := map[string]interface{}{
-					"name": item.Name,
-				}
-				inventoryItems = append(inventoryItems, itemData)
-			}
-
-			// Prepare response data once
-			responseData := map[string]interface{}{
-				"message": "Character created successfully",
-				"character": map[string]interface{}{
-					"name":       character.GetName(),
-					"race":       character.GetRace(),
-					"class":      character.GetClass(),
-					"abilities":  charAbilities.GetAllAbilities(),
-					"manaPoints": character.GetManaPoints(),
-					"condition":  character.GetCondition().String(),
-					"inventory": map[string]interface{}{
-						"items": inventoryItems,
-					},
-				},
-			}
-
-			// Return success response
-			w.Header().Set("Content-Type", "application/json")
-			w.WriteHeader(http.StatusCreated)
-			json.NewEncoder(w).Encode(responseData)
-			// Mock sending character data to a database
-			charObj, err := json.MarshalIndent(responseData, "", "  ")
+		}
+		if updateReq.Race != nil {
+			if err := character.SetRace(*updateReq.Race); err != nil {
+				http.Error(w, fmt.Sprintf("Invalid race: %v", err), http.StatusBadRequest)
+				return
+			}
+		}
+		if updateReq.Class != nil {
+			if err := character.SetClass(*updateReq.Class); err != nil {
+				http.Error(w, fmt.Sprintf("Invalid class: %v", err), http.StatusBadRequest)
+				return
+			}
+		}
+		if updateReq.Condition != nil {
+			if err := character.SetCondition(cond.NewCondition(*updateReq.Condition)); err != nil {
+				http.Error(w, fmt.Sprintf("Invalid condition: %v", err), http.StatusBadRequest)
+				return
+			}
+		}
+		if updateReq.Abilities != nil {
+			abilities, err := updatedAbilities(character.GetAbilities(), updateReq.Abilities)
+			if err == nil {
+				err = character.SetAbilities(abilities)
+			}
 			if err != nil {
-				log.Printf("Error marshaling character data: %v", err)
+				http.Error(w, fmt.Sprintf("Invalid abilities: %v", err), http.StatusBadRequest)
 				return
 			}
-			mockSendDbRequest(string(charObj))
 		}
+
+		if updateReq.CountCompanionWeight != nil {
+			character.SetCountCompanionWeight(*updateReq.CountCompanionWeight)
+		}
+
+		if !saveCharacter(w, character) {
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"message":   "Character updated successfully",
+			"character": character,
+		})
 	})
 
-	mux.HandleFunc("/get-chars", func(w http.ResponseWriter, r *http.Request) {
-		if r.Method != http.MethodGet {
+	mux.HandleFunc("/add-items", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
 			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 			return
 		}
 
-		var responseData []map[string]interface{}
+		var itemReqs []ItemDTO
+		if !decodeStrictJSON(w, r, maxCreateBodyBytes, &itemReqs) {
+			return
+		}
+		defer r.Body.Close()
 
-		for _, character := range characters {
-			// Get character data
-			charAbilities := character.GetAbilities()
-			charInventory := character.GetInventory()
+		rosterMu.Lock()
+		defer rosterMu.Unlock()
+		name := r.URL.Query().Get("name")
+		character, err := findCharacter(r.Context(), name)
+		if err != nil {
+			writeStoreError(w, name, err)
+			return
+		}
 
-			// Prepare inventory items
-			for _, item := range charInventory.GetAllItems() {
-				// inventoryItems = append(inventoryItems, itemData)
-				// Add character data to response
-				responseData = append(responseData, map[string]interface{}{
-					"name":       character.GetName(),
-					"race":       character.GetRace(),
-					"class":      character.GetClass(),
-					"abilities":  charAbilities.GetAllAbilities(),
-					"manaPoints": character.GetManaPoints(),
-					"condition":  character.GetCondition().String(),
-					"inventory": map[string]interface{}{
-						"items": map[string]interface{}{
-							"name":        item.Name,
-							"qantity":     item.GetQuantity(),
-							"condition":   item.GetCondition().String(),
-							"description": item.GetDescription(),
-						},
-					},
-				})
+		// Items are added one by one and stack as usual. An invalid item or
+		// one over the carry capacity is reported and skipped, the items
+		// added before it stay
+		added := []int{}
+		var problems validation.ValidationErrors
+		for i, itemDTO := range itemReqs {
+			path := fmt.Sprintf("[%d]", i)
+			item, itemProblems := validateItemDTO(path, itemDTO)
+			if len(itemProblems) > 0 {
+				problems = append(problems, itemProblems...)
+				continue
+			}
+			if err := character.AddItem(item); err != nil {
+				problems.Add(path, "item %s: %v", itemDTO.Name, err)
+				continue
 			}
+			added = append(added, i)
+		}
+		if len(added) == 0 && len(problems) > 0 {
+			writeJSON(w, http.StatusUnprocessableEntity, map[string]interface{}{
+				"fieldErrors": problems,
+			})
+			return
+		}
+
+		if !saveCharacter(w, character) {
+			return
+		}
+		responseData := map[string]interface{}{
+			"added":     added,
+			"inventory": character.GetInventory(),
+		}
+		if len(problems) > 0 {
+			responseData["fieldErrors"] = problems
+		}
+		writeJSON(w, http.StatusOK, responseData)
+	})
 
+	mux.HandleFunc("/transfer-item", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
 		}
 
-		log.Printf("Returning %d characters", len(characters))
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusOK)
-		json.NewEncoder(w).Encode(map[string]interface{}{
-			"count":      len(characters),
-			"characters": responseData,
-		})
+		// from and to are character IDs or names
+		var transferReq struct {
+			From     string `json:"from"`
+			To       string `json:"to"`
+			Item     string `json:"item"`
+			Quantity int    `json:"quantity"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&transferReq); err != nil {
+			http.Error(w, fmt.Sprintf("Invalid JSON: %v", err), http.StatusBadRequest)
+			return
+		}
+		defer r.Body.Close()
+
+		transferItem(w, r, transferReq.From, transferReq.To, transferReq.Item, transferReq.Quantity)
 	})
-	log.Println("Starting server")
-	log.Println("Listen on port 8080")
-	if err := srv.ListenAndServe(); err != nil {
-		log.Fatalf("Server failed to start: %v", err)
+
+	mux.HandleFunc("/get-chars", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		rosterMu.RLock()
+		defer rosterMu.RUnlock()
+
+		roster, err := characters.List()
+		if err != nil {
+			writeStoreError(w, "roster", err)
+			return
+		}
+
+		// Companions are nested in their owners. ?includeCompanions=true
+		// lists them at the top level as well
+		query := r.URL.Query()
+		if query.Get("includeCompanions") == "true" {
+			for i := range roster {
+				for _, companion := range roster[i].Companions() {
+					roster = append(roster, *companion)
+				}
+			}
+		}
+
+		// ?class= and ?race= keep only matching characters, ignoring case
+		if query.Has("class") || query.Has("race") {
+			filtered := roster[:0]
+			for _, character := range roster {
+				if query.Has("class") && !strings.EqualFold(character.GetClass(), query.Get("class")) {
+					continue
+				}
+				if query.Has("race") && !strings.EqualFold(character.GetRace(), query.Get("race")) {
+					continue
+				}
+				filtered = append(filtered, character)
+			}
+			roster = filtered
+		}
+
+		// ?sort=power lists the strongest characters first
+		if query.Get("sort") == "power" {
+			sort.SliceStable(roster, func(i, j int) bool {
+				a, b := roster[i].GetAbilities(), roster[j].GetAbilities()
+				return a.PowerRating() > b.PowerRating()
+			})
+		}
+
+		docs, err := withLegacyQuantity(roster)
+		if err != nil {
+			slog.Error("Error encoding roster", "error", err)
+			http.Error(w, "Error encoding characters", http.StatusInternalServerError)
+			return
+		}
+		warned := false
+		legacyQuantityWarning.Do(func() {
+			slog.Warn(`/get-chars item key "qantity" is deprecated and will be removed, read "quantity" instead`)
+			warned = true
+		})
+		if !warned {
+			slog.Debug(`/get-chars served the deprecated item key "qantity"`)
+		}
+
+		slog.Debug("Returning characters", "count", len(roster))
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"count":      len(roster),
+			"characters": docs,
+		})
+	})
+	mux.HandleFunc("/search-chars", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		// ?minStrength=7&minIntelligence=6 keeps characters whose effective
+		// abilities meet every given threshold
+		minimums := map[string]int{}
+		for _, ability := range []string{"strength", "luck", "charisma", "agility", "perception", "intelligence"} {
+			param := "min" + strings.ToUpper(ability[:1]) + ability[1:]
+			raw := r.URL.Query().Get(param)
+			if raw == "" {
+				continue
+			}
+			n, err := strconv.Atoi(raw)
+			if err != nil {
+				http.Error(w, fmt.Sprintf("Invalid %s %q: must be a number", param, raw), http.StatusBadRequest)
+				return
+			}
+			minimums[ability] = n
+		}
+
+		rosterMu.RLock()
+		defer rosterMu.RUnlock()
+		roster, err := characters.List()
+		if err != nil {
+			writeStoreError(w, "roster", err)
+			return
+		}
+
+		matches := []*char.Character{}
+		for i := range roster {
+			effective := roster[i].GetEffectiveAbilities()
+			scores := effective.GetAllAbilities()
+			matched := true
+			for ability, minimum := range minimums {
+				if scores[ability] < minimum {
+					matched = false
+					break
+				}
+			}
+			if matched {
+				matches = append(matches, &roster[i])
+			}
+		}
+
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"count":      len(matches),
+			"characters": matches,
+		})
+	})
+
+	mux.HandleFunc("/contest", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		var contestReq struct {
+			CharacterA string `json:"characterA"`
+			AbilityA   string `json:"abilityA"`
+			CharacterB string `json:"characterB"`
+			AbilityB   string `json:"abilityB"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&contestReq); err != nil {
+			http.Error(w, fmt.Sprintf("Invalid JSON: %v", err), http.StatusBadRequest)
+			return
+		}
+		defer r.Body.Close()
+
+		rosterMu.RLock()
+		defer rosterMu.RUnlock()
+		characterA, err := findCharacter(r.Context(), contestReq.CharacterA)
+		if err != nil {
+			writeStoreError(w, contestReq.CharacterA, err)
+			return
+		}
+		characterB, err := findCharacter(r.Context(), contestReq.CharacterB)
+		if err != nil {
+			writeStoreError(w, contestReq.CharacterB, err)
+			return
+		}
+
+		// Equipment, buffs and effects count, like in skill checks
+		abilitiesA := characterA.GetEffectiveAbilities()
+		abilitiesB := characterB.GetEffectiveAbilities()
+		result, err := abts.Contest(&abilitiesA, contestReq.AbilityA, &abilitiesB, contestReq.AbilityB, newRequestRand())
+		if err != nil {
+			http.Error(w, fmt.Sprintf("Invalid contest: %v", err), http.StatusBadRequest)
+			return
+		}
+
+		winner := characterA.GetName()
+		if result.Winner == abts.SideB {
+			winner = characterB.GetName()
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"characterA": characterA.GetName(),
+			"characterB": characterB.GetName(),
+			"winner":     winner,
+			"result":     result,
+		})
+	})
+
+	mux.HandleFunc("/roll", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		// An unescaped "+" in the query string decodes to a space, so
+		// "?n=2d6+3" arrives as "2d6 3"
+		notation := strings.ReplaceAll(r.URL.Query().Get("n"), " ", "+")
+		if notation == "" {
+			http.Error(w, "Missing dice notation, e.g. ?n=2d6+3", http.StatusBadRequest)
+			return
+		}
+		expr, err := dice.Parse(notation)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("Invalid dice: %v", err), http.StatusBadRequest)
+			return
+		}
+
+		total, rolls := expr.Roll(newRequestRand())
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"notation": expr.String(),
+			"total":    total,
+			"rolls":    rolls,
+			"modifier": expr.Modifier,
+		})
+	})
+
+	mux.HandleFunc("/skill-check", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		var checkReq struct {
+			Character string `json:"character"`
+			Ability   string `json:"ability"`
+			DC        int    `json:"dc"`
+			// Campaign picks the crit and fumble rules, "" means the default one
+			Campaign string `json:"campaign,omitempty"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&checkReq); err != nil {
+			http.Error(w, fmt.Sprintf("Invalid JSON: %v", err), http.StatusBadRequest)
+			return
+		}
+		defer r.Body.Close()
+
+		current, err := campaigns.Get(checkReq.Campaign)
+		if err != nil {
+			writeCampaignError(w, err)
+			return
+		}
+		rosterMu.RLock()
+		defer rosterMu.RUnlock()
+		character, err := findCharacter(r.Context(), checkReq.Character)
+		if err != nil {
+			writeStoreError(w, checkReq.Character, err)
+			return
+		}
+
+		// Checks use the effective abilities, so equipped items count, and
+		// the proficiency bonus of the character's class. A fumbled check
+		// only rolls and logs its outcome, the outcomes apply to attacks
+		result, err := character.SkillCheck(current.Ruleset, checkReq.Ability, checkReq.DC, newRequestRand())
+		if err != nil {
+			http.Error(w, fmt.Sprintf("Invalid skill check: %v", err), http.StatusBadRequest)
+			return
+		}
+		logCheck(current.Name, character.GetName(), result)
+
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"character": character.GetName(),
+			"result":    result,
+		})
+	})
+
+	mux.HandleFunc("/characters/{name}/xp", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		var xpReq struct {
+			Amount int `json:"amount"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&xpReq); err != nil {
+			http.Error(w, fmt.Sprintf("Invalid JSON: %v", err), http.StatusBadRequest)
+			return
+		}
+		defer r.Body.Close()
+
+		rosterMu.Lock()
+		defer rosterMu.Unlock()
+		character, err := findCharacter(r.Context(), r.PathValue("name"))
+		if err != nil {
+			writeStoreError(w, r.PathValue("name"), err)
+			return
+		}
+
+		levelBefore := character.GetLevel()
+		leveledUp, err := character.AddXP(xpReq.Amount)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("Invalid experience: %v", err), http.StatusBadRequest)
+			return
+		}
+
+		if !saveCharacter(w, character) {
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"level":         character.GetLevel(),
+			"experience":    character.GetExperience(),
+			"xpToNextLevel": character.ExperienceToNextLevel(),
+			"leveledUp":     leveledUp,
+			"grantedPoints": (character.GetLevel() - levelBefore) * char.PointsPerLevel,
+		})
+	})
+
+	mux.HandleFunc("/characters/{name}/mana", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		// A negative delta spends mana, a positive one restores it
+		var manaReq struct {
+			Delta int `json:"delta"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&manaReq); err != nil {
+			http.Error(w, fmt.Sprintf("Invalid JSON: %v", err), http.StatusBadRequest)
+			return
+		}
+		defer r.Body.Close()
+
+		rosterMu.Lock()
+		defer rosterMu.Unlock()
+		character, err := findCharacter(r.Context(), r.PathValue("name"))
+		if err != nil {
+			writeStoreError(w, r.PathValue("name"), err)
+			return
+		}
+
+		if manaReq.Delta < 0 {
+			if err := character.SpendMana(-manaReq.Delta); err != nil {
+				http.Error(w, fmt.Sprintf("Cannot spend mana: %v", err), http.StatusConflict)
+				return
+			}
+		} else {
+			character.RestoreMana(manaReq.Delta)
+		}
+
+		if !saveCharacter(w, character) {
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"manaPoints":    character.GetManaPoints(),
+			"maxManaPoints": character.GetMaxManaPoints(),
+		})
+	})
+
+	mux.HandleFunc("/characters/{name}/gold", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		// A negative delta spends gold, a positive one adds it
+		var goldReq struct {
+			Delta int `json:"delta"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&goldReq); err != nil {
+			http.Error(w, fmt.Sprintf("Invalid JSON: %v", err), http.StatusBadRequest)
+			return
+		}
+		defer r.Body.Close()
+
+		rosterMu.Lock()
+		defer rosterMu.Unlock()
+		character, err := findCharacter(r.Context(), r.PathValue("name"))
+		if err != nil {
+			writeStoreError(w, r.PathValue("name"), err)
+			return
+		}
+
+		if goldReq.Delta < 0 {
+			if err := character.SpendGold(-goldReq.Delta); err != nil {
+				http.Error(w, fmt.Sprintf("Cannot spend gold: %v", err), http.StatusConflict)
+				return
+			}
+		} else if err := character.AddGold(goldReq.Delta); err != nil {
+			http.Error(w, fmt.Sprintf("Cannot add gold: %v", err), http.StatusBadRequest)
+			return
+		}
+
+		if !saveCharacter(w, character) {
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"gold": character.GetGold(),
+		})
+	})
+
+	mux.HandleFunc("/characters/{name}/rest", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		var restReq struct {
+			Type char.RestKind `json:"type"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&restReq); err != nil {
+			http.Error(w, fmt.Sprintf("Invalid JSON: %v", err), http.StatusBadRequest)
+			return
+		}
+		defer r.Body.Close()
+
+		rosterMu.Lock()
+		defer rosterMu.Unlock()
+		character, err := findCharacter(r.Context(), r.PathValue("name"))
+		if err != nil {
+			writeStoreError(w, r.PathValue("name"), err)
+			return
+		}
+
+		summary, err := character.Rest(restReq.Type)
+		var deadErr *char.DeadError
+		if errors.As(err, &deadErr) {
+			http.Error(w, fmt.Sprintf("Cannot rest: %v", err), http.StatusConflict)
+			return
+		}
+		if err != nil {
+			http.Error(w, fmt.Sprintf("Cannot rest: %v", err), http.StatusBadRequest)
+			return
+		}
+		if !saveCharacter(w, character) {
+			return
+		}
+		writeJSON(w, http.StatusOK, summary)
+	})
+
+	// GET lists the companions of a character, POST creates one from a
+	// character request
+	mux.HandleFunc("/characters/{name}/companions", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodPost {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		if r.Method == http.MethodGet {
+			rosterMu.RLock()
+			defer rosterMu.RUnlock()
+			owner, err := findCharacter(r.Context(), r.PathValue("name"))
+			if err != nil {
+				writeStoreError(w, r.PathValue("name"), err)
+				return
+			}
+			writeJSON(w, http.StatusOK, map[string]interface{}{
+				"companions": owner.Companions(),
+			})
+			return
+		}
+
+		// A companion is created from the same request as a character
+		var companionReq CreateCharacterRequest
+		if !decodeStrictJSON(w, r, maxCreateBodyBytes, &companionReq) {
+			return
+		}
+		defer r.Body.Close()
+		draft, problems := validateCharacterRequest("companion", companionReq)
+		problems = append(problems, draft.skipped...)
+		if len(problems) > 0 {
+			writeJSON(w, http.StatusUnprocessableEntity, map[string]interface{}{
+				"fieldErrors": problems,
+			})
+			return
+		}
+
+		rosterMu.Lock()
+		defer rosterMu.Unlock()
+		owner, err := findCharacter(r.Context(), r.PathValue("name"))
+		if err != nil {
+			writeStoreError(w, r.PathValue("name"), err)
+			return
+		}
+		if err := owner.AddCompanion(draft.build()); err != nil {
+			http.Error(w, fmt.Sprintf("Cannot add companion: %v", err), http.StatusConflict)
+			return
+		}
+		if !saveCharacter(w, owner) {
+			return
+		}
+		writeJSON(w, http.StatusCreated, map[string]interface{}{
+			"message":   "Companion added successfully",
+			"character": owner,
+		})
+	})
+
+	mux.HandleFunc("/characters/{name}/companions/{companion}", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodDelete {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		rosterMu.Lock()
+		defer rosterMu.Unlock()
+		owner, err := findCharacter(r.Context(), r.PathValue("name"))
+		if err != nil {
+			writeStoreError(w, r.PathValue("name"), err)
+			return
+		}
+		if _, err := owner.RemoveCompanion(r.PathValue("companion")); err != nil {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		if !saveCharacter(w, owner) {
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"message":   "Companion removed successfully",
+			"character": owner,
+		})
+	})
+
+	mux.HandleFunc("/characters/{name}/clone", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		var cloneReq struct {
+			Name string `json:"name"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&cloneReq); err != nil {
+			http.Error(w, fmt.Sprintf("Invalid JSON: %v", err), http.StatusBadRequest)
+			return
+		}
+		defer r.Body.Close()
+
+		rosterMu.Lock()
+		defer rosterMu.Unlock()
+		original, err := findCharacter(r.Context(), r.PathValue("name"))
+		if err != nil {
+			writeStoreError(w, r.PathValue("name"), err)
+			return
+		}
+		if _, err := findCharacter(r.Context(), cloneReq.Name); err == nil {
+			http.Error(w, fmt.Sprintf("Character %s already exists", cloneReq.Name), http.StatusConflict)
+			return
+		} else if !errors.Is(err, store.ErrNotFound) {
+			writeStoreError(w, cloneReq.Name, err)
+			return
+		}
+
+		clone, err := original.CloneAs(cloneReq.Name)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("Invalid name: %v", err), http.StatusBadRequest)
+			return
+		}
+		if !saveCharacter(w, clone) {
+			return
+		}
+		w.Header().Set("ETag", `"`+clone.Hash()+`"`)
+		writeJSON(w, http.StatusCreated, map[string]interface{}{
+			"message":   "Character cloned successfully",
+			"character": clone,
+		})
+	})
+
+	// POST learns a spell, DELETE forgets one
+	mux.HandleFunc("/characters/{name}/spells", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost && r.Method != http.MethodDelete {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		var spellReq struct {
+			Spell string `json:"spell"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&spellReq); err != nil {
+			http.Error(w, fmt.Sprintf("Invalid JSON: %v", err), http.StatusBadRequest)
+			return
+		}
+		defer r.Body.Close()
+
+		rosterMu.Lock()
+		defer rosterMu.Unlock()
+		character, err := findCharacter(r.Context(), r.PathValue("name"))
+		if err != nil {
+			writeStoreError(w, r.PathValue("name"), err)
+			return
+		}
+
+		if r.Method == http.MethodPost {
+			err = character.LearnSpell(spellReq.Spell)
+		} else {
+			err = character.ForgetSpell(spellReq.Spell)
+		}
+		if err != nil {
+			http.Error(w, fmt.Sprintf("Cannot change spellbook: %v", err), http.StatusBadRequest)
+			return
+		}
+		if !saveCharacter(w, character) {
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"spellbook": character.GetSpellbook(),
+		})
+	})
+
+	mux.HandleFunc("/characters/{name}/cast", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		// Without a target the spell is cast on the caster
+		var castReq struct {
+			Spell  string `json:"spell"`
+			Target string `json:"target,omitempty"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&castReq); err != nil {
+			http.Error(w, fmt.Sprintf("Invalid JSON: %v", err), http.StatusBadRequest)
+			return
+		}
+		defer r.Body.Close()
+
+		rosterMu.Lock()
+		defer rosterMu.Unlock()
+		caster, err := findCharacter(r.Context(), r.PathValue("name"))
+		if err != nil {
+			writeStoreError(w, r.PathValue("name"), err)
+			return
+		}
+		var target *char.Character
+		if castReq.Target != "" {
+			target, err = findCharacter(r.Context(), castReq.Target)
+			if err != nil {
+				writeStoreError(w, castReq.Target, err)
+				return
+			}
+			if target.GetID() == caster.GetID() {
+				target = nil
+			}
+		}
+
+		changed, originals := []*char.Character{caster}, []*char.Character{caster.Clone()}
+		if target != nil {
+			changed, originals = append(changed, target), append(originals, target.Clone())
+		}
+		result, err := caster.CastOn(castReq.Spell, target)
+		var manaErr *char.InsufficientManaError
+		var deadErr *char.DeadError
+		switch {
+		case errors.As(err, &manaErr), errors.As(err, &deadErr):
+			http.Error(w, fmt.Sprintf("Cannot cast: %v", err), http.StatusConflict)
+			return
+		case err != nil:
+			http.Error(w, fmt.Sprintf("Cannot cast: %v", err), http.StatusBadRequest)
+			return
+		}
+
+		// Give the mana back if a save fails, so it is not spent on a spell
+		// that never landed
+		if !saveCharacters(w, "cast", changed, originals) {
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"result":     result,
+			"manaPoints": caster.GetManaPoints(),
+		})
+	})
+
+	mux.HandleFunc("/characters/{name}/give", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		// target is the ID or name of the character receiving the items
+		var giveReq struct {
+			Target   string `json:"target"`
+			Item     string `json:"item"`
+			Quantity int    `json:"quantity"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&giveReq); err != nil {
+			http.Error(w, fmt.Sprintf("Invalid JSON: %v", err), http.StatusBadRequest)
+			return
+		}
+		defer r.Body.Close()
+
+		transferItem(w, r, r.PathValue("name"), giveReq.Target, giveReq.Item, giveReq.Quantity)
+	})
+
+	mux.HandleFunc("/characters/{name}/use-item", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		var useReq struct {
+			Item string `json:"item"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&useReq); err != nil {
+			http.Error(w, fmt.Sprintf("Invalid JSON: %v", err), http.StatusBadRequest)
+			return
+		}
+		defer r.Body.Close()
+
+		rosterMu.Lock()
+		defer rosterMu.Unlock()
+		character, err := findCharacter(r.Context(), r.PathValue("name"))
+		if err != nil {
+			writeStoreError(w, r.PathValue("name"), err)
+			return
+		}
+		if character.Inventory().GetItem(useReq.Item) == nil {
+			http.Error(w, fmt.Sprintf("Item %s not found in the inventory of %s", useReq.Item, character.GetName()), http.StatusNotFound)
+			return
+		}
+
+		result, err := character.UseItem(useReq.Item)
+		var outErr *char.OutOfItemError
+		var deadErr *char.DeadError
+		switch {
+		case errors.As(err, &outErr), errors.As(err, &deadErr):
+			http.Error(w, fmt.Sprintf("Cannot use item: %v", err), http.StatusConflict)
+			return
+		case err != nil:
+			http.Error(w, fmt.Sprintf("Cannot use item: %v", err), http.StatusBadRequest)
+			return
+		}
+
+		if !saveCharacter(w, character) {
+			return
+		}
+		writeJSON(w, http.StatusOK, result)
+	})
+
+	mux.HandleFunc("/characters/{name}/effects", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		var effect char.Effect
+		if err := json.NewDecoder(r.Body).Decode(&effect); err != nil {
+			http.Error(w, fmt.Sprintf("Invalid JSON: %v", err), http.StatusBadRequest)
+			return
+		}
+		defer r.Body.Close()
+
+		rosterMu.Lock()
+		defer rosterMu.Unlock()
+		character, err := findCharacter(r.Context(), r.PathValue("name"))
+		if err != nil {
+			writeStoreError(w, r.PathValue("name"), err)
+			return
+		}
+		if err := character.ApplyEffect(effect); err != nil {
+			http.Error(w, fmt.Sprintf("Invalid effect: %v", err), http.StatusBadRequest)
+			return
+		}
+		if !saveCharacter(w, character) {
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"effects":   character.GetEffects(),
+			"condition": character.GetCondition().String(),
+		})
+	})
+
+	mux.HandleFunc("/characters/{name}/sheet", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		format := r.URL.Query().Get("format")
+		if format == "" {
+			format = abts.FormatText
+		}
+
+		rosterMu.RLock()
+		defer rosterMu.RUnlock()
+		character, err := findCharacter(r.Context(), r.PathValue("name"))
+		if err != nil {
+			writeStoreError(w, r.PathValue("name"), err)
+			return
+		}
+
+		sheet, err := character.RenderSheet(format)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		contentType := "text/plain; charset=utf-8"
+		if format == abts.FormatMarkdown {
+			contentType = "text/markdown; charset=utf-8"
+		}
+		w.Header().Set("Content-Type", contentType)
+		io.WriteString(w, sheet)
+	})
+
+	mux.HandleFunc("/characters/{name}/history", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		limit := defaultHistoryLimit
+		if raw := r.URL.Query().Get("limit"); raw != "" {
+			n, err := strconv.Atoi(raw)
+			if err != nil || n <= 0 {
+				http.Error(w, fmt.Sprintf("Invalid limit %q: must be a positive number", raw), http.StatusBadRequest)
+				return
+			}
+			limit = n
+		}
+
+		rosterMu.RLock()
+		defer rosterMu.RUnlock()
+		character, err := findCharacter(r.Context(), r.PathValue("name"))
+		if err != nil {
+			writeStoreError(w, r.PathValue("name"), err)
+			return
+		}
+
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"history": character.GetHistory(limit),
+		})
+	})
+
+	// GET lists the relationships of a character. POST relates it to another
+	// character of the roster, given by ID or name, and DELETE removes a
+	// relationship. Both change the edges in both directions
+	mux.HandleFunc("/characters/{name}/relationships", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodPost && r.Method != http.MethodDelete {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		if r.Method == http.MethodGet {
+			rosterMu.RLock()
+			defer rosterMu.RUnlock()
+			character, err := findCharacter(r.Context(), r.PathValue("name"))
+			if err != nil {
+				writeStoreError(w, r.PathValue("name"), err)
+				return
+			}
+			writeJSON(w, http.StatusOK, map[string]interface{}{
+				"relationships": character.Relationships(),
+			})
+			return
+		}
+
+		var relationReq struct {
+			Target string `json:"target"`
+			Kind   string `json:"kind"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&relationReq); err != nil {
+			http.Error(w, fmt.Sprintf("Invalid JSON: %v", err), http.StatusBadRequest)
+			return
+		}
+		defer r.Body.Close()
+
+		rosterMu.Lock()
+		defer rosterMu.Unlock()
+		character, err := findCharacter(r.Context(), r.PathValue("name"))
+		if err != nil {
+			writeStoreError(w, r.PathValue("name"), err)
+			return
+		}
+		target, err := findCharacter(r.Context(), relationReq.Target)
+		if err != nil {
+			writeStoreError(w, relationReq.Target, err)
+			return
+		}
+		if target.GetID() == character.GetID() {
+			http.Error(w, "A character cannot have a relationship with themselves", http.StatusBadRequest)
+			return
+		}
+		originals := []*char.Character{target.Clone(), character.Clone()}
+
+		status, message := http.StatusCreated, "Relationship added successfully"
+		if r.Method == http.MethodPost {
+			err = char.Relate(character, target, relationReq.Kind)
+		} else {
+			var removed bool
+			removed, err = char.Unrelate(character, target, relationReq.Kind)
+			if err == nil && !removed {
+				http.Error(w, fmt.Sprintf("%s has no %s relationship with %s", character.GetName(), relationReq.Kind, target.GetName()), http.StatusNotFound)
+				return
+			}
+			status, message = http.StatusOK, "Relationship removed successfully"
+		}
+		if err != nil {
+			http.Error(w, fmt.Sprintf("Invalid relationship: %v", err), http.StatusBadRequest)
+			return
+		}
+
+		// Both are put back if either save fails, so the edge is never stored
+		// in one direction only
+		if !saveCharacters(w, "relationship change", []*char.Character{target, character}, originals) {
+			return
+		}
+		writeJSON(w, status, map[string]interface{}{
+			"message":       message,
+			"relationships": character.Relationships(),
+		})
+	})
+
+	// /relationships/graph returns the roster as nodes and its relationships
+	// as edges, the shape force-directed graph views expect
+	mux.HandleFunc("/relationships/graph", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		rosterMu.RLock()
+		defer rosterMu.RUnlock()
+		roster, err := characters.List()
+		if err != nil {
+			writeStoreError(w, "roster", err)
+			return
+		}
+
+		type node struct {
+			ID    string `json:"id"`
+			Name  string `json:"name"`
+			Race  string `json:"race"`
+			Class string `json:"class"`
+		}
+		type edge struct {
+			Source string `json:"source"`
+			Target string `json:"target"`
+			Kind   string `json:"kind"`
+		}
+		nodes := []node{}
+		edges := []edge{}
+		inRoster := map[string]bool{}
+		for _, character := range roster {
+			inRoster[character.GetID()] = true
+			nodes = append(nodes, node{ID: character.GetID(), Name: character.GetName(), Race: character.GetRace(), Class: character.GetClass()})
+		}
+		for _, character := range roster {
+			for _, relation := range character.Relationships() {
+				// Edges to deleted characters are removed on delete, skip any left over
+				if inRoster[relation.Target] {
+					edges = append(edges, edge{Source: character.GetID(), Target: relation.Target, Kind: relation.Kind})
+				}
+			}
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"nodes": nodes,
+			"edges": edges,
+		})
+	})
+
+	mux.HandleFunc("/characters/{name}/equip", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		// slot is optional, the first free slot the item fits is used
+		// without it. An occupied slot is an error unless "replace": true
+		// unequips its item. "unequip": true takes the item off instead
+		var equipReq struct {
+			Item    string `json:"item"`
+			Slot    string `json:"slot"`
+			Replace bool   `json:"replace"`
+			Unequip bool   `json:"unequip"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&equipReq); err != nil {
+			http.Error(w, fmt.Sprintf("Invalid JSON: %v", err), http.StatusBadRequest)
+			return
+		}
+		defer r.Body.Close()
+
+		rosterMu.Lock()
+		defer rosterMu.Unlock()
+		character, err := findCharacter(r.Context(), r.PathValue("name"))
+		if err != nil {
+			writeStoreError(w, r.PathValue("name"), err)
+			return
+		}
+
+		switch {
+		case equipReq.Unequip:
+			err = character.Unequip(equipReq.Item)
+		case equipReq.Replace:
+			err = character.ForceEquip(equipReq.Item, equipReq.Slot)
+		case equipReq.Slot != "":
+			err = character.EquipSlot(equipReq.Item, equipReq.Slot)
+		default:
+			err = character.Equip(equipReq.Item)
+		}
+		if err != nil {
+			http.Error(w, fmt.Sprintf("Cannot change equipment: %v", err), http.StatusConflict)
+			return
+		}
+
+		if !saveCharacter(w, character) {
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"message":   "Equipment updated",
+			"character": character,
+		})
+	})
+
+	// GET returns a character by ID, DELETE removes it along with the
+	// relationships other characters have with it and its party membership
+	mux.HandleFunc("/characters/{id}", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodDelete {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		if r.Method == http.MethodDelete {
+			rosterMu.Lock()
+			defer rosterMu.Unlock()
+			id := r.PathValue("id")
+			if err := characters.Delete(id); err != nil {
+				writeStoreError(w, id, err)
+				return
+			}
+			if left := parties.RemoveFromAll(id); len(left) > 0 {
+				slog.Info("Deleted character removed from parties", "character", id, "parties", left)
+			}
+			roster, err := characters.List()
+			if err != nil {
+				writeStoreError(w, "roster", err)
+				return
+			}
+			for i := range roster {
+				roster[i].SetActor(char.ActorFrom(r.Context()))
+				if roster[i].RemoveRelationshipsTo(id) > 0 && !saveCharacter(w, &roster[i]) {
+					return
+				}
+			}
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+
+		rosterMu.RLock()
+		defer rosterMu.RUnlock()
+		character, err := characters.Get(r.PathValue("id"))
+		if err != nil {
+			writeStoreError(w, r.PathValue("id"), err)
+			return
+		}
+
+		w.Header().Set("ETag", `"`+character.Hash()+`"`)
+		writeJSON(w, http.StatusOK, &character)
+	})
+
+	mux.HandleFunc("/characters/hashes", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		rosterMu.RLock()
+		defer rosterMu.RUnlock()
+		roster, err := characters.List()
+		if err != nil {
+			writeStoreError(w, "roster", err)
+			return
+		}
+		hashes := map[string]string{}
+		for _, character := range roster {
+			hashes[character.GetID()] = character.Hash()
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"hashes": hashes,
+		})
+	})
+
+	// /npc/generate creates count random NPCs, 1 by default. They join the
+	// roster unless ephemeral=true. A seed makes the NPCs reproducible
+	mux.HandleFunc("/npc/generate", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		query := r.URL.Query()
+		count := 1
+		if raw := query.Get("count"); raw != "" {
+			n, err := strconv.Atoi(raw)
+			if err != nil || n < 1 || n > maxNPCsPerRequest {
+				http.Error(w, fmt.Sprintf("count must be between 1 and %d", maxNPCsPerRequest), http.StatusBadRequest)
+				return
+			}
+			count = n
+		}
+		rng := newRequestRand()
+		if raw := query.Get("seed"); raw != "" {
+			seed, err := strconv.ParseInt(raw, 10, 64)
+			if err != nil {
+				http.Error(w, fmt.Sprintf("Invalid seed: %v", err), http.StatusBadRequest)
+				return
+			}
+			rng = rand.New(rand.NewSource(seed))
+		}
+		opts := char.NPCOptions{Race: query.Get("race"), Class: query.Get("class")}
+		if opts.Race != "" {
+			if _, ok := char.LookupRace(opts.Race); !ok {
+				http.Error(w, fmt.Sprintf("Unknown race %s", opts.Race), http.StatusBadRequest)
+				return
+			}
+		}
+		if opts.Class != "" {
+			if _, ok := char.LookupClass(opts.Class); !ok {
+				http.Error(w, fmt.Sprintf("Unknown class %s", opts.Class), http.StatusBadRequest)
+				return
+			}
+		}
+
+		npcs := make([]*char.Character, 0, count)
+		for i := 0; i < count; i++ {
+			npcs = append(npcs, char.NewRandomNPC(rng, opts))
+		}
+		if query.Get("ephemeral") != "true" {
+			rosterMu.Lock()
+			defer rosterMu.Unlock()
+			for _, npc := range npcs {
+				if !saveCharacter(w, npc) {
+					return
+				}
+			}
+		}
+		writeJSON(w, http.StatusCreated, map[string]interface{}{
+			"characters": npcs,
+		})
+	})
+
+	// /encounter/initiative rolls initiative for the listed characters and
+	// returns them in turn order. A seed makes the rolls reproducible
+	mux.HandleFunc("/encounter/initiative", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		var initiativeReq struct {
+			Characters []string `json:"characters"`
+			Seed       *int64   `json:"seed,omitempty"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&initiativeReq); err != nil {
+			http.Error(w, fmt.Sprintf("Invalid JSON: %v", err), http.StatusBadRequest)
+			return
+		}
+		defer r.Body.Close()
+
+		rng := newRequestRand()
+		if initiativeReq.Seed != nil {
+			rng = rand.New(rand.NewSource(*initiativeReq.Seed))
+		}
+
+		rosterMu.Lock()
+		defer rosterMu.Unlock()
+		type excludedCharacter struct {
+			Character string `json:"character"`
+			Note      string `json:"note"`
+		}
+		excluded := []excludedCharacter{}
+		var combatants []*char.Character
+		for _, ref := range initiativeReq.Characters {
+			character, err := findCharacter(r.Context(), ref)
+			if err != nil {
+				writeStoreError(w, ref, err)
+				return
+			}
+			conditions := character.GetConditions()
+			if conditions.Has(char.ConditionUnconscious) || conditions.Has(char.ConditionDead) {
+				excluded = append(excluded, excludedCharacter{character.GetName(), fmt.Sprintf("cannot act while %s", conditions.String())})
+				continue
+			}
+			combatants = append(combatants, character)
+		}
+
+		// Roll in request order, so a seed always gives the same rolls
+		for _, character := range combatants {
+			character.RollInitiative(rng)
+		}
+		char.SortByInitiative(combatants)
+
+		type initiativeEntry struct {
+			ID         string `json:"id"`
+			Character  string `json:"character"`
+			Initiative int    `json:"initiative"`
+		}
+		order := []initiativeEntry{}
+		for _, character := range combatants {
+			if !saveCharacter(w, character) {
+				return
+			}
+			order = append(order, initiativeEntry{character.GetID(), character.GetName(), character.GetInitiative()})
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"order":    order,
+			"excluded": excluded,
+		})
+	})
+
+	// /session/tick advances the round for the whole roster
+	mux.HandleFunc("/session/tick", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		rosterMu.Lock()
+		defer rosterMu.Unlock()
+		roster, err := characters.List()
+		if err != nil {
+			writeStoreError(w, "roster", err)
+			return
+		}
+
+		type expiredEffect struct {
+			ID        string `json:"id"`
+			Character string `json:"character"`
+			Effect    string `json:"effect"`
+		}
+		expired := []expiredEffect{}
+		for i := range roster {
+			character := &roster[i]
+			for _, effect := range character.TickRound() {
+				expired = append(expired, expiredEffect{character.GetID(), character.GetName(), effect.Name})
+			}
+			if !saveCharacter(w, character) {
+				return
+			}
+		}
+		slog.Info("Round advanced", "characters", len(roster), "expiredEffects", len(expired))
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"expired": expired,
+		})
+	})
+
+	// GET lists the parties, POST creates one. Members are character
+	// references, stored by ID
+	mux.HandleFunc("/parties", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			writeJSON(w, http.StatusOK, map[string]interface{}{
+				"parties": parties.List(),
+			})
+		case http.MethodPost:
+			var partyReq struct {
+				Name    string   `json:"name"`
+				Members []string `json:"members"`
+			}
+			if err := json.NewDecoder(r.Body).Decode(&partyReq); err != nil {
+				http.Error(w, fmt.Sprintf("Invalid JSON: %v", err), http.StatusBadRequest)
+				return
+			}
+			defer r.Body.Close()
+
+			rosterMu.RLock()
+			defer rosterMu.RUnlock()
+			var ids []string
+			for _, ref := range partyReq.Members {
+				character, err := findCharacter(r.Context(), ref)
+				if err != nil {
+					writeStoreError(w, ref, err)
+					return
+				}
+				ids = append(ids, character.GetID())
+			}
+			created, err := parties.Create(partyReq.Name, ids...)
+			if err != nil {
+				writePartyError(w, err)
+				return
+			}
+			writeJSON(w, http.StatusCreated, map[string]interface{}{
+				"party": created,
+			})
+		default:
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		}
+	})
+
+	mux.HandleFunc("/parties/{name}", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			found, err := parties.Get(r.PathValue("name"))
+			if err != nil {
+				writePartyError(w, err)
+				return
+			}
+			writeJSON(w, http.StatusOK, map[string]interface{}{
+				"party": found,
+			})
+		case http.MethodDelete:
+			if err := parties.Delete(r.PathValue("name")); err != nil {
+				writePartyError(w, err)
+				return
+			}
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		}
+	})
+
+	// POST adds a member, DELETE removes one
+	mux.HandleFunc("/parties/{name}/members", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost && r.Method != http.MethodDelete {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		var memberReq struct {
+			Character string `json:"character"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&memberReq); err != nil {
+			http.Error(w, fmt.Sprintf("Invalid JSON: %v", err), http.StatusBadRequest)
+			return
+		}
+		defer r.Body.Close()
+
+		rosterMu.RLock()
+		defer rosterMu.RUnlock()
+		character, err := findCharacter(r.Context(), memberReq.Character)
+		if err != nil {
+			writeStoreError(w, memberReq.Character, err)
+			return
+		}
+
+		var updated *party.Party
+		if r.Method == http.MethodPost {
+			updated, err = parties.AddMember(r.PathValue("name"), character.GetID())
+		} else {
+			updated, err = parties.RemoveMember(r.PathValue("name"), character.GetID())
+		}
+		if err != nil {
+			writePartyError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"party": updated,
+		})
+	})
+
+	mux.HandleFunc("/parties/{name}/summary", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		found, err := parties.Get(r.PathValue("name"))
+		if err != nil {
+			writePartyError(w, err)
+			return
+		}
+		rosterMu.RLock()
+		defer rosterMu.RUnlock()
+		members, err := partyMembers(found)
+		if err != nil {
+			writeStoreError(w, found.Name, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, party.Summarize(found, members))
+	})
+
+	mux.HandleFunc("/parties/{name}/check", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		var checkReq struct {
+			Ability string `json:"ability"`
+			DC      int    `json:"dc"`
+			// Campaign picks the crit and fumble rules, "" means the default one
+			Campaign string `json:"campaign,omitempty"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&checkReq); err != nil {
+			http.Error(w, fmt.Sprintf("Invalid JSON: %v", err), http.StatusBadRequest)
+			return
+		}
+		defer r.Body.Close()
+
+		found, err := parties.Get(r.PathValue("name"))
+		if err != nil {
+			writePartyError(w, err)
+			return
+		}
+		current, err := campaigns.Get(checkReq.Campaign)
+		if err != nil {
+			writeCampaignError(w, err)
+			return
+		}
+		rosterMu.RLock()
+		defer rosterMu.RUnlock()
+		members, err := partyMembers(found)
+		if err != nil {
+			writeStoreError(w, found.Name, err)
+			return
+		}
+		result, err := party.PartyCheck(members, current.Ruleset, checkReq.Ability, checkReq.DC, newRequestRand())
+		if err != nil {
+			http.Error(w, fmt.Sprintf("Invalid party check: %v", err), http.StatusBadRequest)
+			return
+		}
+		for _, check := range result.Checks {
+			logCheck(current.Name, check.Name, check.Result)
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"party":  found.Name,
+			"result": result,
+		})
+	})
+
+	mux.HandleFunc("/campaigns", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			writeJSON(w, http.StatusOK, map[string]interface{}{
+				"campaigns": campaigns.List(),
+			})
+		case http.MethodPost:
+			// A campaign without rules gets the default crit modifiers and fumble table
+			var campaignReq struct {
+				Name          string                `json:"name"`
+				CritModifiers []rules.CritModifier  `json:"critModifiers,omitempty"`
+				FumbleTable   []rules.FumbleOutcome `json:"fumbleTable,omitempty"`
+			}
+			if err := json.NewDecoder(r.Body).Decode(&campaignReq); err != nil {
+				http.Error(w, fmt.Sprintf("Invalid JSON: %v", err), http.StatusBadRequest)
+				return
+			}
+			defer r.Body.Close()
+
+			var ruleset *rules.Ruleset
+			if campaignReq.CritModifiers != nil || campaignReq.FumbleTable != nil {
+				var err error
+				if ruleset, err = rules.NewRuleset(campaignReq.CritModifiers, campaignReq.FumbleTable); err != nil {
+					http.Error(w, fmt.Sprintf("Invalid ruleset: %v", err), http.StatusBadRequest)
+					return
+				}
+			}
+			created, err := campaigns.Create(campaignReq.Name, ruleset)
+			if err != nil {
+				writeCampaignError(w, err)
+				return
+			}
+			writeJSON(w, http.StatusCreated, created)
+		default:
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		}
+	})
+
+	mux.HandleFunc("/campaigns/{name}", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		found, err := campaigns.Get(r.PathValue("name"))
+		if err != nil {
+			writeCampaignError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, found)
+	})
+
+	mux.HandleFunc("/campaigns/{name}/log", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		found, err := campaigns.Get(r.PathValue("name"))
+		if err != nil {
+			writeCampaignError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"campaign": found.Name,
+			"log":      found.Log,
+		})
+	})
+
+	// /campaigns/{name}/export returns everything needed to run the campaign
+	// elsewhere: its rules, loot and log, the roster and the custom
+	// conditions, which the characters may depend on
+	mux.HandleFunc("/campaigns/{name}/export", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		found, err := campaigns.Get(r.PathValue("name"))
+		if err != nil {
+			writeCampaignError(w, err)
+			return
+		}
+		rosterMu.RLock()
+		roster, err := characters.List()
+		rosterMu.RUnlock()
+		if err != nil {
+			writeStoreError(w, found.Name, err)
+			return
+		}
+		custom := cond.DefaultRegistry.Custom()
+		if custom == nil {
+			custom = []cond.Definition{}
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"campaign":   found,
+			"characters": roster,
+			"conditions": custom,
+		})
+	})
+
+	// /campaigns/{name}/attack resolves one attack with the rules of the
+	// campaign. A fumble is applied to the attacker: a dropped weapon goes to
+	// the loot pile of the campaign and a hit on an ally needs the ally in
+	// the request. A seed makes the rolls reproducible
+	mux.HandleFunc("/campaigns/{name}/attack", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		var attackReq struct {
+			Attacker string `json:"attacker"`
+			Defender string `json:"defender"`
+			Ally     string `json:"ally,omitempty"`
+			Seed     *int64 `json:"seed,omitempty"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&attackReq); err != nil {
+			http.Error(w, fmt.Sprintf("Invalid JSON: %v", err), http.StatusBadRequest)
+			return
+		}
+		defer r.Body.Close()
+
+		rng := newRequestRand()
+		if attackReq.Seed != nil {
+			rng = rand.New(rand.NewSource(*attackReq.Seed))
+		}
+
+		rosterMu.Lock()
+		defer rosterMu.Unlock()
+		current, err := campaigns.Get(r.PathValue("name"))
+		if err != nil {
+			writeCampaignError(w, err)
+			return
+		}
+		attacker, err := findCharacter(r.Context(), attackReq.Attacker)
+		if err != nil {
+			writeStoreError(w, attackReq.Attacker, err)
+			return
+		}
+		defender, err := findCharacter(r.Context(), attackReq.Defender)
+		if err != nil {
+			writeStoreError(w, attackReq.Defender, err)
+			return
+		}
+		if attacker.GetID() == defender.GetID() {
+			http.Error(w, fmt.Sprintf("%s cannot attack themselves", attacker.GetName()), http.StatusBadRequest)
+			return
+		}
+		var ally *char.Character
+		if attackReq.Ally != "" {
+			if ally, err = findCharacter(r.Context(), attackReq.Ally); err != nil {
+				writeStoreError(w, attackReq.Ally, err)
+				return
+			}
+			if ally.GetID() == attacker.GetID() || ally.GetID() == defender.GetID() {
+				http.Error(w, "The ally must be neither the attacker nor the defender", http.StatusBadRequest)
+				return
+			}
+		}
+
+		originalAttacker, originalDefender := attacker.Clone(), defender.Clone()
+		result, err := char.ResolveAttack(attacker, defender, current.Ruleset, rng)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("Invalid attack: %v", err), http.StatusBadRequest)
+			return
+		}
+		var fumble *char.FumbleResult
+		if result.Fumble != nil {
+			applied, err := attacker.ApplyFumble(*result.Fumble, &current.Loot, ally, rng)
+			if err != nil {
+				http.Error(w, fmt.Sprintf("Cannot apply fumble: %v", err), http.StatusConflict)
+				return
+			}
+			fumble = &applied
+		}
+
+		// Save in order and put the earlier characters back if a later save
+		// fails, so the attack is applied to all of them or to none
+		changed := []*char.Character{attacker, defender}
+		originals := []*char.Character{originalAttacker, originalDefender}
+		if fumble != nil && fumble.AllyDamage > 0 {
+			changed, originals = append(changed, ally), append(originals, ally.Clone())
+		}
+		if !saveCharacters(w, "attack", changed, originals) {
+			return
+		}
+
+		entry := campaign.Entry{
+			Kind:      campaign.EntryAttack,
+			Actor:     attacker.GetName(),
+			Target:    defender.GetName(),
+			Natural:   result.Natural,
+			Success:   result.Hit,
+			Critical:  result.Critical,
+			CritRange: result.CritRange,
+			Fumble:    result.Fumble,
+		}
+		if fumble != nil {
+			if fumble.DroppedItem != "" {
+				if err := campaigns.SetLoot(current.Name, current.Loot); err != nil {
+					slog.Error("Error storing loot", "campaign", current.Name, "error", err)
+				}
+				entry.Effect = fmt.Sprintf("%s dropped %s", attacker.GetName(), fumble.DroppedItem)
+			}
+			if fumble.AllyDamage > 0 {
+				entry.Effect = fmt.Sprintf("%s hit %s for %d", attacker.GetName(), fumble.Ally, fumble.AllyDamage)
+			}
+			if fumble.LostAction {
+				entry.Effect = fmt.Sprintf("%s loses the next action", attacker.GetName())
+			}
+		}
+		if err := campaigns.Record(current.Name, entry); err != nil {
+			slog.Error("Error recording attack", "campaign", current.Name, "error", err)
+		}
+
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"campaign": current.Name,
+			"attacker": attacker.GetName(),
+			"defender": defender.GetName(),
+			"result":   result,
+			"fumble":   fumble,
+		})
+	})
+
+	mux.HandleFunc("/templates", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodPost {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		if r.Method == http.MethodGet {
+			list, err := templates.ListTemplates()
+			if err != nil {
+				slog.Error("Error listing templates", "error", err)
+				http.Error(w, "Template store unavailable", http.StatusInternalServerError)
+				return
+			}
+			writeJSON(w, http.StatusOK, map[string]interface{}{
+				"templates": list,
+			})
+			return
+		}
+
+		var template store.Template
+		if !decodeStrictJSON(w, r, maxCreateBodyBytes, &template) {
+			return
+		}
+		defer r.Body.Close()
+		if template.Name == "" {
+			http.Error(w, "Template name cannot be empty", http.StatusBadRequest)
+			return
+		}
+		if _, problems := validateTemplate(template, ""); len(problems) > 0 {
+			writeJSON(w, http.StatusUnprocessableEntity, map[string]interface{}{
+				"fieldErrors": problems,
+			})
+			return
+		}
+
+		if _, err := templates.GetTemplate(template.Name); err == nil {
+			http.Error(w, fmt.Sprintf("Template %s already exists", template.Name), http.StatusConflict)
+			return
+		} else if !errors.Is(err, store.ErrTemplateNotFound) {
+			slog.Error("Error loading template", "template", template.Name, "error", err)
+			http.Error(w, "Template store unavailable", http.StatusInternalServerError)
+			return
+		}
+		if err := templates.SaveTemplate(template); err != nil {
+			slog.Error("Error saving template", "template", template.Name, "error", err)
+			http.Error(w, "Template store unavailable", http.StatusInternalServerError)
+			return
+		}
+		writeJSON(w, http.StatusCreated, map[string]interface{}{
+			"message":  "Template saved successfully",
+			"template": template,
+		})
+	})
+
+	// instantiateTemplate creates a character from the template in the path,
+	// served at POST /characters/from-template/{name} and, for older
+	// clients, POST /templates/{name}/characters
+	instantiateTemplate := func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		var instanceReq struct {
+			Name string `json:"name"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&instanceReq); err != nil {
+			http.Error(w, fmt.Sprintf("Invalid JSON: %v", err), http.StatusBadRequest)
+			return
+		}
+		defer r.Body.Close()
+		if instanceReq.Name == "" {
+			http.Error(w, "Character name cannot be empty", http.StatusBadRequest)
+			return
+		}
+
+		template, err := templates.GetTemplate(r.PathValue("name"))
+		if errors.Is(err, store.ErrTemplateNotFound) {
+			http.Error(w, fmt.Sprintf("Template %s not found", r.PathValue("name")), http.StatusNotFound)
+			return
+		}
+		if err != nil {
+			slog.Error("Error loading template", "template", r.PathValue("name"), "error", err)
+			http.Error(w, "Template store unavailable", http.StatusInternalServerError)
+			return
+		}
+
+		// The template is validated again, so one saved under looser rules
+		// fails here instead of creating an invalid character
+		draft, problems := validateTemplate(template, instanceReq.Name)
+		if len(problems) > 0 {
+			writeJSON(w, http.StatusUnprocessableEntity, map[string]interface{}{
+				"fieldErrors": problems,
+			})
+			return
+		}
+
+		rosterMu.Lock()
+		defer rosterMu.Unlock()
+		character := draft.build()
+		if !saveCharacter(w, character) {
+			return
+		}
+		w.Header().Set("ETag", `"`+character.Hash()+`"`)
+		writeJSON(w, http.StatusCreated, map[string]interface{}{
+			"message":   "Character created successfully",
+			"character": character,
+		})
 	}
+	mux.HandleFunc("/templates/{name}/characters", instantiateTemplate)
+
+	mux.HandleFunc("/items/catalog", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"items": inv.DefaultCatalog.Templates(),
+		})
+	})
+
+	mux.HandleFunc("/races", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"races": char.Races(),
+		})
+	})
+
+	// /classes lists the classes with the max mana each point of
+	// intelligence is worth, from the same formula characters use
+	mux.HandleFunc("/classes", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		classes := []char.Class{}
+		for _, class := range char.Classes() {
+			class.ManaPerIntelligence = char.ManaForClass(class.Name, 1)
+			classes = append(classes, class)
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"classes": classes,
+		})
+	})
+
+	mux.HandleFunc("/spells", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"spells": char.Spells(),
+		})
+	})
+
+	mux.HandleFunc("/conditions", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"conditions": cond.DefaultRegistry.List(),
+		})
+	})
+
+	mux.HandleFunc("/admin/conditions", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		defer r.Body.Close()
+
+		// Accept a list of definitions as JSON, or as YAML in the same format as the conditions file
+		var defs []cond.Definition
+		if strings.Contains(r.Header.Get("Content-Type"), "yaml") {
+			body, err := io.ReadAll(r.Body)
+			if err != nil {
+				http.Error(w, fmt.Sprintf("Invalid body: %v", err), http.StatusBadRequest)
+				return
+			}
+			if defs, err = cond.ParseYAML(body); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+		} else if err := json.NewDecoder(r.Body).Decode(&defs); err != nil {
+			http.Error(w, fmt.Sprintf("Invalid JSON: %v", err), http.StatusBadRequest)
+			return
+		}
+
+		// Conditions only take effect once saved, so a failed write registers nothing
+		err := cond.DefaultRegistry.RegisterAllAndSave(defs, conditionsFile)
+		var saveErr *cond.SaveError
+		if err == nil || errors.As(err, &saveErr) {
+			persistence.Record(sourceConditions, err)
+		}
+		if err != nil {
+			if saveErr != nil {
+				slog.Error("Error saving custom conditions", "file", conditionsFile, "error", err)
+				http.Error(w, "conditions could not be persisted", http.StatusInternalServerError)
+				return
+			}
+			http.Error(w, fmt.Sprintf("Invalid condition: %v", err), http.StatusBadRequest)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"message":    "Conditions registered successfully",
+			"conditions": defs,
+		})
+	})
+
+	// /characters/from-template/{name} overlaps the /characters/{name}/...
+	// routes, which one mux rejects as a conflict, so it is served in front
+	// of the others. A character named "from-template" is reached by ID
+	root := http.NewServeMux()
+	root.HandleFunc("/characters/from-template/{name}", instantiateTemplate)
+	root.Handle("/", mux)
+	return root
 }
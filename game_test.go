@@ -0,0 +1,694 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	abts "dnd-helper/src/abilities"
+	char "dnd-helper/src/character"
+	"dnd-helper/src/dice"
+	inv "dnd-helper/src/inventory"
+	"dnd-helper/src/store"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"math/rand"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+func TestMain(m *testing.M) {
+	slog.SetDefault(slog.New(slog.DiscardHandler))
+	os.Exit(m.Run())
+}
+
+// newTestServer serves a fresh API backed by an in-memory store
+func newTestServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	memory := store.NewMemoryStore()
+	srv := httptest.NewServer(newAPI(memory, memory, filepath.Join(t.TempDir(), "conditions.yaml")))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+// send makes a request with a JSON body, empty for none, and returns the
+// status and the response body. It does not fail the test, so goroutines
+// may call it
+func send(srv *httptest.Server, method, path, body string) (int, []byte, error) {
+	req, err := http.NewRequest(method, srv.URL+path, strings.NewReader(body))
+	if err != nil {
+		return 0, nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := srv.Client().Do(req)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	return resp.StatusCode, data, err
+}
+
+// mustSend is send for the test goroutine, failing the test unless the
+// response has the wanted status
+func mustSend(t *testing.T, srv *httptest.Server, method, path, body string, want int) []byte {
+	t.Helper()
+	status, data, err := send(srv, method, path, body)
+	if err != nil {
+		t.Fatalf("%s %s: %v", method, path, err)
+	}
+	if status != want {
+		t.Fatalf("%s %s: status %d, want %d: %s", method, path, status, want, data)
+	}
+	return data
+}
+
+// createCharacter creates one character from its JSON definition and
+// returns its ID
+func createCharacter(t *testing.T, srv *httptest.Server, definition string) string {
+	t.Helper()
+	data := mustSend(t, srv, http.MethodPost, "/create-character", "["+definition+"]", http.StatusCreated)
+	var resp struct {
+		Character struct {
+			ID string `json:"id"`
+		} `json:"character"`
+	}
+	if err := json.Unmarshal(data, &resp); err != nil || resp.Character.ID == "" {
+		t.Fatalf("unexpected create response %s: %v", data, err)
+	}
+	return resp.Character.ID
+}
+
+// checkAbilities decodes the abilities of a character document and checks
+// the pool never went negative and spent points plus the pool still add up
+// to the budget
+func checkAbilities(doc []byte) error {
+	var character struct {
+		Abilities abts.Abilities `json:"abilities"`
+	}
+	if err := json.Unmarshal(doc, &character); err != nil {
+		return err
+	}
+	abs := character.Abilities
+	if pool := abs.GetPointsPool(); pool < 0 {
+		return fmt.Errorf("points pool %d is negative", pool)
+	}
+	if spent, pool, budget := abs.SpentPoints(), abs.GetPointsPool(), abs.TotalBudget(); spent+pool != budget {
+		return fmt.Errorf("spent %d + pool %d != budget %d", spent, pool, budget)
+	}
+	return nil
+}
+
+// TestConcurrentAbilityUpdates hammers one character with concurrent ability
+// updates and reads. Run it with -race
+func TestConcurrentAbilityUpdates(t *testing.T) {
+	srv := newTestServer(t)
+	id := createCharacter(t, srv, `{"race":"human","name":"Hammer","class":"mage","condition":"Healthy",
+		"abilities":{"strength":8,"luck":7,"charisma":5,"agility":5,"perception":5,"intelligence":5}}`)
+
+	var accepted, rejected atomic.Int32
+	var wg sync.WaitGroup
+	for g := range 8 {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for i := range 20 {
+				// Every update moves points between strength and luck, and
+				// some of them overspend the budget and must be rejected
+				strength := 4 + (g*7+i)%7
+				body := fmt.Sprintf(`{"name":"Hammer","abilities":{"strength":%d,"luck":%d}}`, strength, 15-strength-(g+i)%2)
+				status, data, err := send(srv, http.MethodPut, "/update-character", body)
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				if status != http.StatusOK {
+					rejected.Add(1)
+					continue
+				}
+				accepted.Add(1)
+				var resp struct {
+					Character json.RawMessage `json:"character"`
+				}
+				if err := json.Unmarshal(data, &resp); err != nil {
+					t.Error(err)
+					return
+				}
+				if err := checkAbilities(resp.Character); err != nil {
+					t.Errorf("after update %s: %v", body, err)
+				}
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for range 20 {
+				status, data, err := send(srv, http.MethodGet, "/characters/"+id, "")
+				if err != nil || status != http.StatusOK {
+					t.Errorf("get: status %d: %v", status, err)
+					return
+				}
+				if err := checkAbilities(data); err != nil {
+					t.Errorf("while updating: %v", err)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	if accepted.Load() == 0 || rejected.Load() == 0 {
+		t.Fatalf("%d updates accepted and %d rejected, want some of both", accepted.Load(), rejected.Load())
+	}
+
+	if err := checkAbilities(mustSend(t, srv, http.MethodGet, "/characters/"+id, "", http.StatusOK)); err != nil {
+		t.Errorf("after updates: %v", err)
+	}
+}
+
+// testCharacter is the JSON definition of a default character named name
+func testCharacter(name string) string {
+	return fmt.Sprintf(`{"race":"human","name":%q,"class":"warrior","condition":"Healthy",
+		"abilities":{"strength":8,"luck":7,"charisma":5,"agility":5,"perception":5,"intelligence":5}}`, name)
+}
+
+func TestRelationshipsAreMirrored(t *testing.T) {
+	srv := newTestServer(t)
+	annID := createCharacter(t, srv, testCharacter("Ann"))
+	boID := createCharacter(t, srv, testCharacter("Bo"))
+
+	relationships := func(ref string) []struct{ Target, Kind string } {
+		t.Helper()
+		var resp struct {
+			Relationships []struct{ Target, Kind string } `json:"relationships"`
+		}
+		if err := json.Unmarshal(mustSend(t, srv, http.MethodGet, "/characters/"+ref+"/relationships", "", http.StatusOK), &resp); err != nil {
+			t.Fatal(err)
+		}
+		return resp.Relationships
+	}
+
+	mustSend(t, srv, http.MethodPost, "/characters/Ann/relationships", `{"target":"Bo","kind":"ally"}`, http.StatusCreated)
+	if got := relationships("Bo"); len(got) != 1 || got[0].Target != annID || got[0].Kind != "ally" {
+		t.Errorf("Bo: got %v, want ally of Ann", got)
+	}
+	if got := relationships("Ann"); len(got) != 1 || got[0].Target != boID {
+		t.Errorf("Ann: got %v, want ally of Bo", got)
+	}
+	mustSend(t, srv, http.MethodPost, "/characters/Bo/relationships", `{"target":"Ann","kind":"ally"}`, http.StatusBadRequest)
+
+	mustSend(t, srv, http.MethodDelete, "/characters/Bo/relationships", `{"target":"Ann","kind":"ally"}`, http.StatusOK)
+	if len(relationships("Ann")) != 0 || len(relationships("Bo")) != 0 {
+		t.Errorf("relationships left after DELETE: %v and %v", relationships("Ann"), relationships("Bo"))
+	}
+	mustSend(t, srv, http.MethodDelete, "/characters/Bo/relationships", `{"target":"Ann","kind":"ally"}`, http.StatusNotFound)
+}
+
+// warnCounter is a slog handler counting the warnings logged through it
+type warnCounter struct {
+	mu    sync.Mutex
+	warns []string
+}
+
+func (h *warnCounter) Enabled(context.Context, slog.Level) bool { return true }
+func (h *warnCounter) WithAttrs([]slog.Attr) slog.Handler       { return h }
+func (h *warnCounter) WithGroup(string) slog.Handler            { return h }
+func (h *warnCounter) Handle(_ context.Context, record slog.Record) error {
+	if record.Level == slog.LevelWarn {
+		h.mu.Lock()
+		h.warns = append(h.warns, record.Message)
+		h.mu.Unlock()
+	}
+	return nil
+}
+
+func TestGetCharsQuantityKeys(t *testing.T) {
+	counter := &warnCounter{}
+	slog.SetDefault(slog.New(counter))
+	t.Cleanup(func() { slog.SetDefault(slog.New(slog.DiscardHandler)) })
+
+	srv := newTestServer(t)
+	createCharacter(t, srv, testCharacter("Ann"))
+	mustSend(t, srv, http.MethodPost, "/add-items?name=Ann", `[{"name":"Rations","quantity":3,"condition":"Pristine","description":"Food"}]`, http.StatusOK)
+	for range 3 {
+		var resp struct {
+			Characters []struct {
+				Inventory struct {
+					Items []map[string]json.RawMessage `json:"items"`
+				} `json:"inventory"`
+			} `json:"characters"`
+		}
+		if err := json.Unmarshal(mustSend(t, srv, http.MethodGet, "/get-chars", "", http.StatusOK), &resp); err != nil {
+			t.Fatal(err)
+		}
+		if len(resp.Characters) != 1 || len(resp.Characters[0].Inventory.Items) == 0 {
+			t.Fatalf("unexpected roster %+v", resp)
+		}
+		for _, item := range resp.Characters[0].Inventory.Items {
+			quantity, ok := item["quantity"]
+			if !ok {
+				t.Fatalf("item %s has no quantity key", item["name"])
+			}
+			if legacy := item["qantity"]; string(legacy) != string(quantity) {
+				t.Errorf("item %s: qantity %s, want %s", item["name"], legacy, quantity)
+			}
+		}
+	}
+
+	deprecations := 0
+	for _, warning := range counter.warns {
+		if strings.Contains(warning, "deprecated") {
+			deprecations++
+		}
+	}
+	if deprecations != 1 {
+		t.Errorf("deprecation logged %d times, want once", deprecations)
+	}
+}
+
+func TestDeleteCharacterLeavesParty(t *testing.T) {
+	srv := newTestServer(t)
+	annID := createCharacter(t, srv, testCharacter("Ann"))
+	boID := createCharacter(t, srv, testCharacter("Bo"))
+	mustSend(t, srv, http.MethodPost, "/parties", `{"name":"Heroes","members":["Ann","Bo"]}`, http.StatusCreated)
+
+	mustSend(t, srv, http.MethodDelete, "/characters/"+annID, "", http.StatusNoContent)
+	var resp struct {
+		Party struct {
+			Members []string `json:"members"`
+		} `json:"party"`
+	}
+	if err := json.Unmarshal(mustSend(t, srv, http.MethodGet, "/parties/Heroes", "", http.StatusOK), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Party.Members) != 1 || resp.Party.Members[0] != boID {
+		t.Errorf("members after deleting Ann: got %v, want only %s", resp.Party.Members, boID)
+	}
+}
+
+// failingStore is a memory store whose saves of one character fail
+type failingStore struct {
+	*store.MemoryStore
+	failID atomic.Value
+}
+
+func (s *failingStore) Save(c char.Character) error {
+	if id, _ := s.failID.Load().(string); id == c.GetID() {
+		return errors.New("disk full")
+	}
+	return s.MemoryStore.Save(c)
+}
+
+func TestCastRestoresCasterWhenTargetSaveFails(t *testing.T) {
+	failing := &failingStore{MemoryStore: store.NewMemoryStore()}
+	srv := httptest.NewServer(newAPI(failing, failing.MemoryStore, filepath.Join(t.TempDir(), "conditions.yaml")))
+	t.Cleanup(srv.Close)
+	casterID := createCharacter(t, srv, strings.Replace(testCharacter("Ann"), "warrior", "mage", 1))
+	targetID := createCharacter(t, srv, testCharacter("Bo"))
+	mustSend(t, srv, http.MethodPost, "/characters/Ann/spells", `{"spell":"Magic Missile"}`, http.StatusOK)
+
+	manaOf := func() int {
+		var doc struct {
+			ManaPoints int `json:"manaPoints"`
+		}
+		if err := json.Unmarshal(mustSend(t, srv, http.MethodGet, "/characters/"+casterID, "", http.StatusOK), &doc); err != nil {
+			t.Fatal(err)
+		}
+		return doc.ManaPoints
+	}
+	before := manaOf()
+	failing.failID.Store(targetID)
+	mustSend(t, srv, http.MethodPost, "/characters/Ann/cast", `{"spell":"Magic Missile","target":"Bo"}`, http.StatusInternalServerError)
+	if after := manaOf(); after != before {
+		t.Errorf("mana went from %d to %d although the cast failed", before, after)
+	}
+}
+
+func TestContestUsesEffectiveAbilities(t *testing.T) {
+	srv := newTestServer(t)
+	annID := createCharacter(t, srv, testCharacter("Ann"))
+	createCharacter(t, srv, testCharacter("Bo"))
+	mustSend(t, srv, http.MethodPost, "/characters/Ann/effects", `{"name":"Giant's Might","duration":3,"abilityMods":{"strength":2}}`, http.StatusOK)
+
+	var doc struct {
+		Modifiers map[string]int `json:"modifiers"`
+	}
+	if err := json.Unmarshal(mustSend(t, srv, http.MethodGet, "/characters/"+annID, "", http.StatusOK), &doc); err != nil {
+		t.Fatal(err)
+	}
+	var resp struct {
+		Result abts.ContestResult `json:"result"`
+	}
+	body := `{"characterA":"Ann","abilityA":"strength","characterB":"Bo","abilityB":"strength"}`
+	if err := json.Unmarshal(mustSend(t, srv, http.MethodPost, "/contest", body, http.StatusOK), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if resp.Result.ModifierA != doc.Modifiers["strength"] {
+		t.Errorf("contest modifier %d, want the effective strength modifier %d", resp.Result.ModifierA, doc.Modifiers["strength"])
+	}
+	if resp.Result.ModifierA == resp.Result.ModifierB {
+		t.Errorf("the effect did not change the modifier, both sides have %d", resp.Result.ModifierA)
+	}
+}
+
+func TestClassesUseManaForClass(t *testing.T) {
+	srv := newTestServer(t)
+	var resp struct {
+		Classes []char.Class `json:"classes"`
+	}
+	if err := json.Unmarshal(mustSend(t, srv, http.MethodGet, "/classes", "", http.StatusOK), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Classes) == 0 {
+		t.Fatal("no classes listed")
+	}
+	for _, class := range resp.Classes {
+		if want := char.ManaForClass(class.Name, 1); class.ManaPerIntelligence != want {
+			t.Errorf("%s: mana per intelligence %d, want %d", class.Name, class.ManaPerIntelligence, want)
+		}
+	}
+}
+
+func TestCharactersFromTemplate(t *testing.T) {
+	srv := newTestServer(t)
+	mustSend(t, srv, http.MethodPost, "/templates", `{"name":"Town Guard","character":`+testCharacter("Guard")+`}`, http.StatusCreated)
+
+	for _, tt := range []struct{ path, name string }{
+		{"/characters/from-template/Town%20Guard", "Gate Guard"},
+		{"/templates/Town%20Guard/characters", "Wall Guard"},
+	} {
+		var resp struct {
+			Character struct {
+				ID   string `json:"id"`
+				Name string `json:"name"`
+			} `json:"character"`
+		}
+		if err := json.Unmarshal(mustSend(t, srv, http.MethodPost, tt.path, fmt.Sprintf(`{"name":%q}`, tt.name), http.StatusCreated), &resp); err != nil {
+			t.Fatal(err)
+		}
+		if resp.Character.Name != tt.name || resp.Character.ID == "" {
+			t.Errorf("%s: got %+v, want a new character named %s", tt.path, resp.Character, tt.name)
+		}
+	}
+	mustSend(t, srv, http.MethodPost, "/characters/from-template/Missing", `{"name":"Nobody"}`, http.StatusNotFound)
+	mustSend(t, srv, http.MethodGet, "/characters/from-template/Town%20Guard", "", http.StatusMethodNotAllowed)
+
+	// The other /characters routes are still served
+	mustSend(t, srv, http.MethodGet, "/characters/Gate%20Guard/relationships", "", http.StatusOK)
+}
+
+func TestAdminConditionsNotRegisteredWhenSaveFails(t *testing.T) {
+	memory := store.NewMemoryStore()
+	missing := filepath.Join(t.TempDir(), "missing", "conditions.yaml")
+	srv := httptest.NewServer(newAPI(memory, memory, missing))
+	t.Cleanup(srv.Close)
+
+	body := `[{"name":"Unsaved dread","severity":1,"category":"character","stacking":"ignore"}]`
+	mustSend(t, srv, http.MethodPost, "/admin/conditions", body, http.StatusInternalServerError)
+	if listed := mustSend(t, srv, http.MethodGet, "/conditions", "", http.StatusOK); strings.Contains(string(listed), "Unsaved dread") {
+		t.Errorf("GET /conditions lists a condition that was never saved: %s", listed)
+	}
+
+	mustSend(t, srv, http.MethodPost, "/admin/conditions", `[{"name":"Dread","severity":1,"category":"character","stacking":"ignore","abilityEffects":{"courage":-1}}]`, http.StatusBadRequest)
+}
+
+// fumbleSeed returns a seed whose first d20 roll is a natural 1
+func fumbleSeed(t *testing.T) int64 {
+	t.Helper()
+	for seed := int64(1); seed < 1000; seed++ {
+		if natural, _ := (dice.Expression{Count: 1, Sides: 20}).Roll(rand.New(rand.NewSource(seed))); natural == 1 {
+			return seed
+		}
+	}
+	t.Fatal("no seed rolls a natural 1")
+	return 0
+}
+
+func TestCampaignAttackFumbleDropsWeaponIntoLoot(t *testing.T) {
+	srv := newTestServer(t)
+	annID := createCharacter(t, srv, testCharacter("Ann"))
+	createCharacter(t, srv, testCharacter("Bo"))
+	mustSend(t, srv, http.MethodPost, "/add-items?name=Ann", `[{"name":"Axe","quantity":1,"condition":"Pristine","description":"Sharp","slot":"mainHand"}]`, http.StatusOK)
+	mustSend(t, srv, http.MethodPost, "/characters/"+annID+"/equip", `{"item":"Axe"}`, http.StatusOK)
+	mustSend(t, srv, http.MethodPost, "/campaigns", `{"name":"Tomb","fumbleTable":[{"name":"drop weapon","weight":1}]}`, http.StatusCreated)
+
+	body := fmt.Sprintf(`{"attacker":"Ann","defender":"Bo","seed":%d}`, fumbleSeed(t))
+	var attack struct {
+		Result char.AttackResult  `json:"result"`
+		Fumble *char.FumbleResult `json:"fumble"`
+	}
+	if err := json.Unmarshal(mustSend(t, srv, http.MethodPost, "/campaigns/Tomb/attack", body, http.StatusOK), &attack); err != nil {
+		t.Fatal(err)
+	}
+	if attack.Result.Natural != 1 || attack.Result.CritRange.Threshold != 20 {
+		t.Fatalf("natural %d with crit range %d, want a fumble with the plain range", attack.Result.Natural, attack.Result.CritRange.Threshold)
+	}
+	if attack.Fumble == nil || attack.Fumble.DroppedItem != "Axe" {
+		t.Fatalf("fumble %+v, want the axe dropped", attack.Fumble)
+	}
+
+	var ann char.Character
+	if err := json.Unmarshal(mustSend(t, srv, http.MethodGet, "/characters/"+annID, "", http.StatusOK), &ann); err != nil {
+		t.Fatal(err)
+	}
+	if ann.IsEquipped("Axe") || ann.Inventory().GetItem("Axe") != nil {
+		t.Error("Ann still holds the axe")
+	}
+
+	var tomb struct {
+		Loot inv.Inventory `json:"loot"`
+		Log  []struct {
+			Kind   string `json:"kind"`
+			Fumble *struct {
+				Name string `json:"name"`
+			} `json:"fumble"`
+			Effect string `json:"effect"`
+		} `json:"log"`
+	}
+	if err := json.Unmarshal(mustSend(t, srv, http.MethodGet, "/campaigns/Tomb", "", http.StatusOK), &tomb); err != nil {
+		t.Fatal(err)
+	}
+	if tomb.Loot.GetItem("Axe") == nil {
+		t.Error("the axe is not in the loot pile")
+	}
+	if len(tomb.Log) != 1 || tomb.Log[0].Kind != "attack" || tomb.Log[0].Fumble == nil || tomb.Log[0].Effect == "" {
+		t.Errorf("encounter log %+v, want the fumbled attack", tomb.Log)
+	}
+
+	// The default campaign keeps its own log
+	var def struct {
+		Log []json.RawMessage `json:"log"`
+	}
+	if err := json.Unmarshal(mustSend(t, srv, http.MethodGet, "/campaigns/default/log", "", http.StatusOK), &def); err != nil {
+		t.Fatal(err)
+	}
+	if len(def.Log) != 0 {
+		t.Errorf("default campaign logged %d entries, want none", len(def.Log))
+	}
+}
+
+func TestSkillCheckUsesCampaign(t *testing.T) {
+	srv := newTestServer(t)
+	createCharacter(t, srv, testCharacter("Ann"))
+	mustSend(t, srv, http.MethodPost, "/skill-check", `{"character":"Ann","ability":"strength","dc":10,"campaign":"missing"}`, http.StatusNotFound)
+	mustSend(t, srv, http.MethodPost, "/campaigns", `{"name":"Tomb","critModifiers":[{"ability":"strength","minScore":8,"threshold":15}]}`, http.StatusCreated)
+
+	var check struct {
+		Result struct {
+			CritRange struct {
+				Threshold int `json:"threshold"`
+			} `json:"critRange"`
+		} `json:"result"`
+	}
+	data := mustSend(t, srv, http.MethodPost, "/skill-check", `{"character":"Ann","ability":"strength","dc":10,"campaign":"Tomb"}`, http.StatusOK)
+	if err := json.Unmarshal(data, &check); err != nil {
+		t.Fatal(err)
+	}
+	if check.Result.CritRange.Threshold != 15 {
+		t.Errorf("crit range %d, want the campaign's 15", check.Result.CritRange.Threshold)
+	}
+	if log := mustSend(t, srv, http.MethodGet, "/campaigns/Tomb/log", "", http.StatusOK); !strings.Contains(string(log), `"kind":"check"`) {
+		t.Errorf("campaign log %s does not record the check", log)
+	}
+}
+
+func TestCampaignExportIncludesCustomConditions(t *testing.T) {
+	srv := newTestServer(t)
+	createCharacter(t, srv, testCharacter("Ann"))
+	mustSend(t, srv, http.MethodPost, "/admin/conditions", `[{"name":"Frightened of spiders","severity":1,"category":"character","stacking":"ignore","abilityEffects":{"agility":-1}}]`, http.StatusCreated)
+
+	var export struct {
+		Campaign struct {
+			Name string `json:"name"`
+		} `json:"campaign"`
+		Characters []json.RawMessage `json:"characters"`
+		Conditions []struct {
+			Name string `json:"name"`
+		} `json:"conditions"`
+	}
+	if err := json.Unmarshal(mustSend(t, srv, http.MethodGet, "/campaigns/default/export", "", http.StatusOK), &export); err != nil {
+		t.Fatal(err)
+	}
+	if export.Campaign.Name != "default" || len(export.Characters) != 1 {
+		t.Errorf("exported campaign %q with %d characters, want default with 1", export.Campaign.Name, len(export.Characters))
+	}
+	found := false
+	for _, def := range export.Conditions {
+		found = found || def.Name == "Frightened of spiders"
+	}
+	if !found {
+		t.Errorf("exported conditions %+v miss the custom condition", export.Conditions)
+	}
+	mustSend(t, srv, http.MethodGet, "/campaigns/missing/export", "", http.StatusNotFound)
+}
+
+// newFaultyServer serves the API over a store that fails writes as config
+// says, seeded with the default warriors Ann and Bo, who carry 3 rations each
+func newFaultyServer(t *testing.T, config store.FaultConfig) (*httptest.Server, *store.MemoryStore) {
+	t.Helper()
+	memory := store.NewMemoryStore()
+	for _, name := range []string{"Ann", "Bo"} {
+		if err := memory.Save(*char.NewDefaultCharacter("human", name, "warrior")); err != nil {
+			t.Fatal(err)
+		}
+	}
+	faulty, err := store.NewFaultStore(memory, memory, config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := httptest.NewServer(newAPI(faulty, faulty, filepath.Join(t.TempDir(), "conditions.yaml")))
+	t.Cleanup(srv.Close)
+	return srv, memory
+}
+
+func TestTransfersSurviveFailedWrites(t *testing.T) {
+	for _, config := range []store.FaultConfig{
+		{FailEvery: 2},
+		{FailEvery: 2, Partial: true},
+		{FailEvery: 3},
+		{FailEvery: 3, Partial: true},
+	} {
+		srv, memory := newFaultyServer(t, config)
+		failed := 0
+		for i := range 6 {
+			from, to := "Ann", "Bo"
+			if i%2 == 1 {
+				from, to = to, from
+			}
+			body := fmt.Sprintf(`{"from":%q,"to":%q,"item":"Rations","quantity":1}`, from, to)
+			status, data, err := send(srv, http.MethodPost, "/transfer-item", body)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if status != http.StatusOK && status != http.StatusInternalServerError {
+				t.Fatalf("%+v: transfer %d answered %d: %s", config, i, status, data)
+			}
+			if status != http.StatusOK {
+				failed++
+			}
+
+			roster, err := memory.List()
+			if err != nil {
+				t.Fatal(err)
+			}
+			total := 0
+			for j := range roster {
+				if rations := roster[j].Inventory().GetItem("Rations"); rations != nil {
+					total += rations.GetQuantity()
+				}
+			}
+			if total != 6 {
+				t.Fatalf("%+v: %d rations stored after transfer %d answered %d, want 6", config, total, i, status)
+			}
+		}
+		if failed == 0 {
+			t.Errorf("%+v: no transfer failed", config)
+		}
+	}
+}
+
+func TestCreateBatchIsAllOrNone(t *testing.T) {
+	for _, config := range []store.FaultConfig{
+		{FailEvery: 2},
+		{FailEvery: 2, Partial: true},
+		{FailEvery: 3},
+		{FailEvery: 3, Partial: true},
+	} {
+		srv, memory := newFaultyServer(t, config)
+		body := "[" + testCharacter("Cy") + "," + testCharacter("Di") + "," + testCharacter("Ed") + "]"
+		mustSend(t, srv, http.MethodPost, "/create-character", body, http.StatusInternalServerError)
+		if count, err := memory.Count(); err != nil || count != 2 {
+			t.Errorf("%+v: %d characters stored after a failed batch, want the 2 seeded: %v", config, count, err)
+		}
+	}
+}
+
+func TestEncounterInitiative(t *testing.T) {
+	memory := store.NewMemoryStore()
+	// Bo is knocked out at 0 hit points and Cy killed by overflow damage
+	hits := map[string]int{"Bo": 1, "Cy": 2}
+	for _, name := range []string{"Ann", "Bo", "Cy", "Di"} {
+		c := char.NewDefaultCharacter("human", name, "warrior")
+		if err := c.TakeDamage(hits[name] * c.GetMaxHP()); err != nil {
+			t.Fatal(err)
+		}
+		if err := memory.Save(*c); err != nil {
+			t.Fatal(err)
+		}
+	}
+	srv := httptest.NewServer(newAPI(memory, memory, filepath.Join(t.TempDir(), "conditions.yaml")))
+	t.Cleanup(srv.Close)
+
+	body := `{"characters":["Ann","Bo","Cy","Di"],"seed":7}`
+	first := mustSend(t, srv, http.MethodPost, "/encounter/initiative", body, http.StatusOK)
+	if again := mustSend(t, srv, http.MethodPost, "/encounter/initiative", body, http.StatusOK); !bytes.Equal(first, again) {
+		t.Errorf("same seed gave %s, then %s", first, again)
+	}
+
+	var resp struct {
+		Order []struct {
+			Character  string `json:"character"`
+			Initiative int    `json:"initiative"`
+		} `json:"order"`
+		Excluded []struct{ Character, Note string } `json:"excluded"`
+	}
+	if err := json.Unmarshal(first, &resp); err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Order) != 2 || resp.Order[0].Initiative < resp.Order[1].Initiative {
+		t.Errorf("order %+v, want Ann and Di, highest first", resp.Order)
+	}
+	if len(resp.Excluded) != 2 || resp.Excluded[0].Character != "Bo" || resp.Excluded[1].Character != "Cy" {
+		t.Fatalf("excluded %+v, want Bo and Cy", resp.Excluded)
+	}
+	for _, excluded := range resp.Excluded {
+		if excluded.Note == "" {
+			t.Errorf("%s excluded without a note", excluded.Character)
+		}
+	}
+}
+
+func TestReadyzReportsPersistentFailure(t *testing.T) {
+	srv, _ := newFaultyServer(t, store.FaultConfig{FailEvery: 1})
+	mustSend(t, srv, http.MethodGet, "/readyz", "", http.StatusOK)
+	mustSend(t, srv, http.MethodPost, "/transfer-item", `{"from":"Ann","to":"Bo","item":"Rations","quantity":1}`, http.StatusInternalServerError)
+	if data := mustSend(t, srv, http.MethodGet, "/readyz", "", http.StatusServiceUnavailable); !strings.Contains(string(data), "characters") {
+		t.Errorf("readiness %s does not name the failing store", data)
+	}
+	// Reads keep working, so liveness is unaffected
+	mustSend(t, srv, http.MethodGet, "/healthz", "", http.StatusOK)
+}
+
+func TestProbesNeedNoAPIKey(t *testing.T) {
+	memory := store.NewMemoryStore()
+	srv := httptest.NewServer(withAuth("secret", newAPI(memory, memory, filepath.Join(t.TempDir(), "conditions.yaml"))))
+	t.Cleanup(srv.Close)
+	for _, path := range []string{"/healthz", "/readyz"} {
+		mustSend(t, srv, http.MethodGet, path, "", http.StatusOK)
+	}
+	mustSend(t, srv, http.MethodGet, "/get-chars", "", http.StatusUnauthorized)
+}
@@ -0,0 +1,136 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// defaultRateLimit and defaultRateBurst apply when -rate-limit and
+	// -rate-burst are not set
+	defaultRateLimit = 10
+	defaultRateBurst = 20
+	// rateLimitSweepInterval is how often idle client buckets are evicted
+	rateLimitSweepInterval = time.Minute
+)
+
+// parseRateLimit parses the requests per second and the burst of the rate
+// limiter, empty strings meaning the defaults. A rate of 0 disables it
+func parseRateLimit(rate, burst string) (float64, int, error) {
+	r, b := float64(defaultRateLimit), defaultRateBurst
+	if rate != "" {
+		parsed, err := strconv.ParseFloat(rate, 64)
+		if err != nil || parsed < 0 || math.IsNaN(parsed) || math.IsInf(parsed, 0) {
+			return 0, 0, fmt.Errorf("invalid rate limit %q, must be a number of requests per second, 0 to disable", rate)
+		}
+		r = parsed
+	}
+	if burst != "" {
+		parsed, err := strconv.Atoi(burst)
+		if err != nil || parsed < 1 {
+			return 0, 0, fmt.Errorf("invalid rate burst %q, must be a positive number of requests", burst)
+		}
+		b = parsed
+	}
+	return r, b, nil
+}
+
+// rateLimiter is a token bucket per client IP: each client may send burst
+// requests at once and rate requests per second after that
+type rateLimiter struct {
+	mu         sync.Mutex
+	rate       float64
+	burst      float64
+	trustProxy bool // take the client IP from X-Forwarded-For
+	buckets    map[string]*tokenBucket
+	lastSweep  time.Time
+}
+
+type tokenBucket struct {
+	tokens float64
+	last   time.Time // when tokens was last refilled
+}
+
+func newRateLimiter(rate float64, burst int, trustProxy bool) *rateLimiter {
+	return &rateLimiter{
+		rate:       rate,
+		burst:      float64(burst),
+		trustProxy: trustProxy,
+		buckets:    map[string]*tokenBucket{},
+		lastSweep:  time.Now(),
+	}
+}
+
+// allow takes a token from the bucket of client. Without a token left it
+// returns how long until the next one
+func (l *rateLimiter) allow(client string, now time.Time) (bool, time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.sweep(now)
+
+	b, ok := l.buckets[client]
+	if !ok {
+		b = &tokenBucket{tokens: l.burst, last: now}
+		l.buckets[client] = b
+	}
+	b.tokens = math.Min(l.burst, b.tokens+now.Sub(b.last).Seconds()*l.rate)
+	b.last = now
+	if b.tokens >= 1 {
+		b.tokens--
+		return true, 0
+	}
+	return false, time.Duration((1 - b.tokens) / l.rate * float64(time.Second))
+}
+
+// sweep evicts the buckets that have refilled completely, since a new
+// bucket would start out the same. It runs at most once per
+// rateLimitSweepInterval, so memory stays bounded by the recently active
+// clients
+func (l *rateLimiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < rateLimitSweepInterval {
+		return
+	}
+	l.lastSweep = now
+	full := time.Duration(l.burst / l.rate * float64(time.Second))
+	for client, b := range l.buckets {
+		if now.Sub(b.last) >= full {
+			delete(l.buckets, client)
+		}
+	}
+}
+
+// clientIP returns the IP of the client of r: the first X-Forwarded-For
+// entry behind a trusted proxy, otherwise the host of RemoteAddr
+func (l *rateLimiter) clientIP(r *http.Request) string {
+	if l.trustProxy {
+		if forwarded := r.Header.Get("X-Forwarded-For"); forwarded != "" {
+			first, _, _ := strings.Cut(forwarded, ",")
+			return strings.TrimSpace(first)
+		}
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// withRateLimit answers 429 with Retry-After once a client IP runs out of
+// tokens in limiter
+func withRateLimit(limiter *rateLimiter, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if ok, wait := limiter.allow(limiter.clientIP(r), time.Now()); !ok {
+			seconds := int(math.Ceil(wait.Seconds()))
+			w.Header().Set("Retry-After", strconv.Itoa(max(seconds, 1)))
+			http.Error(w, "Too many requests", http.StatusTooManyRequests)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
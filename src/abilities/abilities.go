@@ -1,8 +1,10 @@
 package abilities
 
 import (
+	"dnd-helper/src/validation"
+	"encoding/json"
 	"fmt"
-	"log"
+	"log/slog"
 )
 
 /*
@@ -35,14 +37,37 @@ const (
 	AbilityPointBudget  = 5
 )
 
+// standardAbilities lists the six built-in abilities in canonical order
+var standardAbilities = []string{"strength", "luck", "charisma", "agility", "perception", "intelligence"}
+
+// Abilities holds a character's ability scores and point-buy state.
+// Methods with pointer receivers that change the scores or the pool are not
+// goroutine-safe; callers sharing an Abilities value must synchronize, or use
+// WithAbility to derive new values instead of mutating a shared one
 type Abilities struct {
 	pointsPool   int //counter for ability points spent by character creator UI
+	granted      int //total points granted on top of AbilityPointBudget
+	bonus        int //budget moved by free bonuses such as racial modifiers, may be negative
 	strength     int
 	luck         int
 	charisma     int
 	agility      int
 	perception   int
 	intelligence int
+	custom       map[string]int   // scores of custom abilities registered in registry
+	registry     *AbilityRegistry // ruleset the custom abilities come from, nil for standard rules
+	costTable    CostTable        // point-buy prices, nil means LinearCostTable
+	limits       map[string]Limit // per-ability ranges overriding the global one
+	drained      map[string]int   // temporary drain per ability, not charged to the pool
+}
+
+func isStandardAbility(name string) bool {
+	for _, std := range standardAbilities {
+		if std == name {
+			return true
+		}
+	}
+	return false
 }
 
 // NewDefaultAbilities creates an Abilities instance with all default values
@@ -58,76 +83,230 @@ func NewDefaultAbilities() Abilities {
 	}
 }
 
+// NewItemAbilities creates the ability bonuses carried by an item. Unlike
+// NewAbilities the values are not bound to the point budget; a value of 0
+// means no bonus. inventory.NewItem validates their range
+func NewItemAbilities(strength int, luck int, charisma int, agility int, perception int, intelligence int) Abilities {
+	return Abilities{
+		strength:     strength,
+		luck:         luck,
+		charisma:     charisma,
+		agility:      agility,
+		perception:   perception,
+		intelligence: intelligence,
+	}
+}
+
 // NewAbilities creates an Abilities instance with validation
 func NewAbilities(strength int, luck int, charisma int, agility int, perception int, intelligence int) (Abilities, error) {
-	// Validate each ability is in range
-	abilities := []struct {
-		name  string
-		value int
-	}{
-		{"strength", strength},
-		{"luck", luck},
-		{"charisma", charisma},
-		{"agility", agility},
-		{"perception", perception},
-		{"intelligence", intelligence},
-	}
-
-	for _, ability := range abilities {
-		if ability.value < MinAbilityValue || ability.value > MaxAbilityValue {
-			return Abilities{}, fmt.Errorf("ability %s value %d must be in range [%d, %d]",
-				ability.name, ability.value, MinAbilityValue, MaxAbilityValue)
+	return NewAbilitiesWithRegistry(nil, map[string]int{
+		"strength":     strength,
+		"luck":         luck,
+		"charisma":     charisma,
+		"agility":      agility,
+		"perception":   perception,
+		"intelligence": intelligence,
+	})
+}
+
+// NewAbilitiesWithRegistry creates an Abilities instance carrying the custom
+// abilities registered in reg. scores must contain all six standard abilities
+// and may contain registered custom ones; omitted custom abilities start at
+// their default. Custom abilities follow the same range and budget rules,
+// with their point cost measured from their own default
+func NewAbilitiesWithRegistry(reg *AbilityRegistry, scores map[string]int) (Abilities, error) {
+	return NewAbilitiesWithCostTable(LinearCostTable, reg, scores)
+}
+
+// NewAbilitiesWithCostTable creates an Abilities instance whose point-buy
+// prices come from table. The total cost of all deviations from the
+// defaults must equal the ability point budget
+func NewAbilitiesWithCostTable(table CostTable, reg *AbilityRegistry, scores map[string]int) (Abilities, error) {
+	abilities := Abilities{
+		registry:  reg,
+		custom:    map[string]int{},
+		costTable: table,
+	}
+	for _, def := range reg.Definitions() {
+		abilities.custom[def.Name] = def.Default
+	}
+
+	var errs validation.ValidationErrors
+	for name := range scores {
+		if !isStandardAbility(name) {
+			if _, ok := reg.Lookup(name); !ok {
+				errs.Add(name, "unknown ability: %s", name)
+			}
+		}
+	}
+	for _, name := range standardAbilities {
+		if _, ok := scores[name]; !ok {
+			errs.Add(name, "ability %s is missing", name)
 		}
 	}
 
-	// Calculate total sum of abilities
-	totalAbilitySum := strength + luck + charisma + agility + perception + intelligence
-	expectedSum := (6 * DefaultAbilityValue) + AbilityPointBudget
+	// Validate each ability is in range
+	for _, name := range abilities.names() {
+		value, ok := scores[name]
+		if !ok {
+			value = abilities.custom[name]
+		}
+		if value < MinAbilityValue || value > MaxAbilityValue {
+			errs.Add(name, "ability %s value %d must be in range [%d, %d]",
+				name, value, MinAbilityValue, MaxAbilityValue)
+			continue
+		}
+		abilities.setValue(name, value)
+	}
+	if len(errs) > 0 {
+		return Abilities{}, errs
+	}
 
-	if totalAbilitySum != expectedSum {
-		return Abilities{}, fmt.Errorf("total ability points (%d) must equal %d (6×%d base + %d bonus points)",
-			totalAbilitySum, expectedSum, DefaultAbilityValue, AbilityPointBudget)
+	// Calculate total cost of deviations from the defaults
+	pointsSpent := abilities.spentPoints()
+	if pointsSpent != AbilityPointBudget {
+		return Abilities{}, fmt.Errorf("total ability point cost (%d) must equal the budget of %d bonus points",
+			pointsSpent, AbilityPointBudget)
 	}
 
 	// Calculate remaining points in pool
-	pointsSpent := (strength - DefaultAbilityValue) + (luck - DefaultAbilityValue) +
-		(charisma - DefaultAbilityValue) + (agility - DefaultAbilityValue) +
-		(perception - DefaultAbilityValue) + (intelligence - DefaultAbilityValue)
-	remainingPoints := AbilityPointBudget - pointsSpent
+	abilities.pointsPool = AbilityPointBudget - pointsSpent
 
-	return Abilities{
-		pointsPool:   remainingPoints,
-		strength:     strength,
-		luck:         luck,
-		charisma:     charisma,
-		agility:      agility,
-		perception:   perception,
-		intelligence: intelligence,
-	}, nil
+	return abilities, nil
 }
 
-// AddToAbility adds value to a specific ability using pointsPool for tracking
-func (a *Abilities) AddToAbility(abilityName string, value int) error {
-	getCurrentValue := func() int {
-		switch abilityName {
-		case "strength":
-			return a.strength
-		case "luck":
-			return a.luck
-		case "charisma":
-			return a.charisma
-		case "agility":
-			return a.agility
-		case "perception":
-			return a.perception
-		case "intelligence":
-			return a.intelligence
-		default:
-			return 0
+// names returns the standard abilities followed by the registered custom ones
+func (a *Abilities) names() []string {
+	names := append([]string{}, standardAbilities...)
+	for _, def := range a.registry.Definitions() {
+		names = append(names, def.Name)
+	}
+	return names
+}
+
+// value returns the current score of a standard or registered custom ability
+func (a *Abilities) value(abilityName string) (int, bool) {
+	switch abilityName {
+	case "strength":
+		return a.strength, true
+	case "luck":
+		return a.luck, true
+	case "charisma":
+		return a.charisma, true
+	case "agility":
+		return a.agility, true
+	case "perception":
+		return a.perception, true
+	case "intelligence":
+		return a.intelligence, true
+	}
+	if def, ok := a.registry.Lookup(abilityName); ok {
+		if v, set := a.custom[abilityName]; set {
+			return v, true
+		}
+		return def.Default, true
+	}
+	return 0, false
+}
+
+// setValue stores the score of a standard or registered custom ability
+func (a *Abilities) setValue(abilityName string, value int) bool {
+	switch abilityName {
+	case "strength":
+		a.strength = value
+	case "luck":
+		a.luck = value
+	case "charisma":
+		a.charisma = value
+	case "agility":
+		a.agility = value
+	case "perception":
+		a.perception = value
+	case "intelligence":
+		a.intelligence = value
+	default:
+		if _, ok := a.registry.Lookup(abilityName); !ok {
+			return false
 		}
+		if a.custom == nil {
+			a.custom = map[string]int{}
+		}
+		a.custom[abilityName] = value
+	}
+	return true
+}
+
+// pointCost returns the cost of an ability value measured from its default
+func (a *Abilities) pointCost(abilityName string, value int) int {
+	return a.costTable.Cost(a.defaultFor(abilityName), value)
+}
+
+// spentPoints returns the total cost of all abilities
+func (a *Abilities) spentPoints() int {
+	spent := 0
+	for _, name := range a.names() {
+		value, _ := a.value(name)
+		spent += a.pointCost(name, value)
+	}
+	return spent
+}
+
+// CostTable returns the point-buy prices used by these abilities
+func (a *Abilities) CostTable() CostTable {
+	if a.costTable == nil {
+		return LinearCostTable
+	}
+	return a.costTable
+}
+
+// defaultFor returns the base value point costs are measured from
+func (a *Abilities) defaultFor(abilityName string) int {
+	if def, ok := a.registry.Lookup(abilityName); ok {
+		return def.Default
 	}
+	return DefaultAbilityValue
+}
 
-	currentValue := getCurrentValue()
+// Registry returns the custom ability registry, or nil for standard rules
+func (a *Abilities) Registry() *AbilityRegistry {
+	return a.registry
+}
+
+// Clone returns a copy of the abilities that shares no custom score storage
+func (a *Abilities) Clone() Abilities {
+	clone := *a
+	if a.custom != nil {
+		clone.custom = make(map[string]int, len(a.custom))
+		for name, value := range a.custom {
+			clone.custom[name] = value
+		}
+	}
+	if a.limits != nil {
+		clone.limits = a.Limits()
+	}
+	if a.drained != nil {
+		clone.drained = a.DrainedAbilities()
+	}
+	return clone
+}
+
+// WithAbility returns a copy of the abilities with one ability set to value
+// through the points pool. The receiver is never modified, so it is safe to
+// call concurrently on a shared value that nobody mutates
+func (a *Abilities) WithAbility(abilityName string, value int) (Abilities, error) {
+	updated := a.Clone()
+	if err := updated.SetAbility(abilityName, value); err != nil {
+		return Abilities{}, err
+	}
+	return updated, nil
+}
+
+// AddToAbility adds value to a specific ability using pointsPool for tracking
+func (a *Abilities) AddToAbility(abilityName string, value int) error {
+	currentValue, ok := a.value(abilityName)
+	if !ok {
+		return fmt.Errorf("unknown ability: %s", abilityName)
+	}
 	newValue := currentValue + value
 
 	// Validate range
@@ -135,107 +314,68 @@ func (a *Abilities) AddToAbility(abilityName string, value int) error {
 		return fmt.Errorf("cannot decrease %s below minimum (%d)", abilityName, MinAbilityValue)
 	}
 	if newValue > MaxAbilityValue {
-		log.Printf("cannot increase ability more than 10")
+		slog.Debug("Ability not changed, above the maximum", "ability", abilityName, "value", newValue)
 		return fmt.Errorf("cannot increase %s above maximum (%d)", abilityName, MaxAbilityValue)
 	}
+	if err := a.checkLimit(abilityName, newValue); err != nil {
+		return fmt.Errorf("cannot change %s: %w", abilityName, err)
+	}
 
-	// Calculate point cost (relative to default value of 5)
-	currentCost := currentValue - DefaultAbilityValue
-	newCost := newValue - DefaultAbilityValue
+	// Calculate point cost (relative to the ability's default value)
+	currentCost := a.pointCost(abilityName, currentValue)
+	newCost := a.pointCost(abilityName, newValue)
 	pointDelta := newCost - currentCost
 
 	// Check if we have enough points in pool
 	if pointDelta > 0 && a.pointsPool < pointDelta {
-		return fmt.Errorf("insufficient points in pool: need %d, have %d", pointDelta, a.pointsPool)
+		return fmt.Errorf("insufficient points in pool: need %d (%s), have %d",
+			pointDelta, a.costTable.Breakdown(currentValue, newValue), a.pointsPool)
 	}
 
 	// Update the ability and pointsPool
-	switch abilityName {
-	case "strength":
-		a.strength = newValue
-	case "luck":
-		a.luck = newValue
-	case "charisma":
-		a.charisma = newValue
-	case "agility":
-		a.agility = newValue
-	case "perception":
-		a.perception = newValue
-	case "intelligence":
-		a.intelligence = newValue
-	default:
-		return fmt.Errorf("unknown ability: %s", abilityName)
-	}
+	a.setValue(abilityName, newValue)
 
 	// Update points pool (if value decreased, points return to pool)
 	a.pointsPool -= pointDelta
-	log.Printf("Updated %s: %d -> %d (points pool: %d)", abilityName, currentValue, newValue, a.pointsPool)
+	slog.Debug("Ability updated", "ability", abilityName, "old", currentValue, "new", newValue, "pointsPool", a.pointsPool)
 
 	return nil
 }
 
 // SetAbility sets a specific ability value using pointsPool for tracking
 func (a *Abilities) SetAbility(abilityName string, value int) error {
+	currentValue, ok := a.value(abilityName)
+	if !ok {
+		return fmt.Errorf("unknown ability: %s", abilityName)
+	}
 	if value < MinAbilityValue {
 		return fmt.Errorf("cannot set %s below minimum (%d)", abilityName, MinAbilityValue)
 	}
 	if value > MaxAbilityValue {
-		log.Printf("You can't set ability more than 10")
+		slog.Debug("Ability not set, above the maximum", "ability", abilityName, "value", value)
 		return fmt.Errorf("cannot set %s above maximum (%d)", abilityName, MaxAbilityValue)
 	}
-
-	getCurrentValue := func() int {
-		switch abilityName {
-		case "strength":
-			return a.strength
-		case "luck":
-			return a.luck
-		case "charisma":
-			return a.charisma
-		case "agility":
-			return a.agility
-		case "perception":
-			return a.perception
-		case "intelligence":
-			return a.intelligence
-		default:
-			return 0
-		}
+	if err := a.checkLimit(abilityName, value); err != nil {
+		return fmt.Errorf("cannot set %s: %w", abilityName, err)
 	}
 
-	currentValue := getCurrentValue()
-
 	// Calculate point cost change
-	currentCost := currentValue - DefaultAbilityValue
-	newCost := value - DefaultAbilityValue
+	currentCost := a.pointCost(abilityName, currentValue)
+	newCost := a.pointCost(abilityName, value)
 	pointDelta := newCost - currentCost
 
 	// Check if we have enough points
 	if pointDelta > 0 && a.pointsPool < pointDelta {
-		return fmt.Errorf("insufficient points in pool: need %d, have %d", pointDelta, a.pointsPool)
+		return fmt.Errorf("insufficient points in pool: need %d (%s), have %d",
+			pointDelta, a.costTable.Breakdown(currentValue, value), a.pointsPool)
 	}
 
 	// Update the ability
-	switch abilityName {
-	case "strength":
-		a.strength = value
-	case "luck":
-		a.luck = value
-	case "charisma":
-		a.charisma = value
-	case "agility":
-		a.agility = value
-	case "perception":
-		a.perception = value
-	case "intelligence":
-		a.intelligence = value
-	default:
-		return fmt.Errorf("unknown ability: %s", abilityName)
-	}
+	a.setValue(abilityName, value)
 
 	// Update points pool
 	a.pointsPool -= pointDelta
-	log.Printf("Set %s to %d (points pool: %d)", abilityName, value, a.pointsPool)
+	slog.Debug("Ability set", "ability", abilityName, "value", value, "pointsPool", a.pointsPool)
 
 	return nil
 }
@@ -265,8 +405,9 @@ func (a *Abilities) GetIntelligence() int {
 	return a.intelligence
 }
 
+// GetAllAbilities returns the standard abilities and any registered custom ones
 func (a *Abilities) GetAllAbilities() map[string]int {
-	return map[string]int{
+	all := map[string]int{
 		"strength":     a.strength,
 		"luck":         a.luck,
 		"charisma":     a.charisma,
@@ -274,42 +415,203 @@ func (a *Abilities) GetAllAbilities() map[string]int {
 		"perception":   a.perception,
 		"intelligence": a.intelligence,
 	}
+	for _, def := range a.registry.Definitions() {
+		all[def.Name], _ = a.value(def.Name)
+	}
+	return all
 }
 
 // String returns a string representation of all abilities
 func (a *Abilities) String() string {
-	log.Printf("Abilities: Strength=%d, Luck=%d, Charisma=%d, Agility=%d, Perception=%d, Intelligence=%d",
-		a.strength, a.luck, a.charisma, a.agility, a.perception, a.intelligence)
-	return fmt.Sprintf("Strength: %d, Luck: %d, Charisma: %d, Agility: %d, Perception: %d, Intelligence: %d",
+	result := fmt.Sprintf("Strength: %d, Luck: %d, Charisma: %d, Agility: %d, Perception: %d, Intelligence: %d",
 		a.strength, a.luck, a.charisma, a.agility, a.perception, a.intelligence)
+	for _, def := range a.registry.Definitions() {
+		value, _ := a.value(def.Name)
+		result += fmt.Sprintf(", %s: %d", def.Name, value)
+	}
+	return result
+}
+
+// LogState logs the abilities and the points pool for debugging, so String
+// can stay free of side effects
+func (a *Abilities) LogState() {
+	slog.Debug("Abilities", "abilities", a.String(), "pointsPool", a.pointsPool)
 }
 
 func (a *Abilities) GetPointsPool() int {
 	return a.pointsPool
 }
 
+// GrantPoints adds n points to the pool and to the total budget, e.g. as a
+// reward. The granted total is tracked so validation accepts the larger budget
+func (a *Abilities) GrantPoints(n int) error {
+	if n < 0 {
+		return fmt.Errorf("cannot grant a negative number of points (%d)", n)
+	}
+	a.pointsPool += n
+	a.granted += n
+	slog.Debug("Ability points granted", "points", n, "pointsPool", a.pointsPool)
+	return nil
+}
+
+// SpentPoints returns how many points of the budget are currently spent
+func (a *Abilities) SpentPoints() int {
+	return a.spentPoints()
+}
+
+// TotalBudget returns the starting budget plus all granted points and the
+// cost of free bonuses
+func (a *Abilities) TotalBudget() int {
+	return AbilityPointBudget + a.granted + a.bonus
+}
+
+// ValidateAbilities checks every ability against its range and limits and
+// the points pool against the budget. All problems are returned at once as
+// validation.ValidationErrors keyed by ability name
 func (a *Abilities) ValidateAbilities() error {
-	log.Println("Validating abilities")
-	abilities := []struct {
-		name  string
-		value int
-	}{
-		{"strength", a.strength},
-		{"luck", a.luck},
-		{"charisma", a.charisma},
-		{"agility", a.agility},
-		{"perception", a.perception},
-		{"intelligence", a.intelligence},
-	}
-
-	for _, ability := range abilities {
-		if ability.value < MinAbilityValue || ability.value > MaxAbilityValue {
-			errMsg := fmt.Sprintf("ability %s value %d must be in range [%d, %d]",
-				ability.name, ability.value, MinAbilityValue, MaxAbilityValue)
-			log.Println(errMsg)
-			return fmt.Errorf(errMsg, nil)
+	var errs validation.ValidationErrors
+	for _, name := range a.names() {
+		value, _ := a.value(name)
+		if value < MinAbilityValue || value > MaxAbilityValue {
+			errs.Add(name, "ability %s value %d must be in range [%d, %d]",
+				name, value, MinAbilityValue, MaxAbilityValue)
+			continue
+		}
+		if err := a.checkLimit(name, value); err != nil {
+			errs.Add(name, "%v", err)
 		}
 	}
-	log.Println("All abilities are valid")
+	if err := a.validateDrain(); err != nil {
+		errs.Add("drained", "%v", err)
+	}
+	if spent := a.spentPoints(); a.pointsPool < 0 || spent+a.pointsPool != a.TotalBudget() {
+		errs.Add("pointsPool", "spent points (%d) and points pool (%d) must add up to the budget of %d",
+			spent, a.pointsPool, a.TotalBudget())
+	}
+	if len(errs) > 0 {
+		slog.Debug("Abilities are invalid", "error", errs)
+		return errs
+	}
 	return nil
 }
+
+// customAbilityJSON is the serialized form of a custom ability, carrying its
+// definition so the ruleset travels with the character
+type customAbilityJSON struct {
+	AbilityDefinition
+	Value int `json:"value"`
+}
+
+type abilitiesJSON struct {
+	Strength     int                 `json:"strength"`
+	Luck         int                 `json:"luck"`
+	Charisma     int                 `json:"charisma"`
+	Agility      int                 `json:"agility"`
+	Perception   int                 `json:"perception"`
+	Intelligence int                 `json:"intelligence"`
+	PointsPool   int                 `json:"pointsPool"`
+	Custom       []customAbilityJSON `json:"custom,omitempty"`
+	CostTable    CostTable           `json:"costTable,omitempty"`
+	Granted      int                 `json:"grantedPoints,omitempty"`
+	Bonus        int                 `json:"bonusPoints,omitempty"`
+	Limits       map[string]Limit    `json:"limits,omitempty"`
+	Drained      map[string]int      `json:"drained,omitempty"`
+}
+
+// MarshalJSON encodes the standard abilities, the points pool and any
+// registered custom abilities together with their definitions
+func (a Abilities) MarshalJSON() ([]byte, error) {
+	data := abilitiesJSON{
+		Strength:     a.strength,
+		Luck:         a.luck,
+		Charisma:     a.charisma,
+		Agility:      a.agility,
+		Perception:   a.perception,
+		Intelligence: a.intelligence,
+		PointsPool:   a.pointsPool,
+		CostTable:    a.costTable,
+		Granted:      a.granted,
+		Bonus:        a.bonus,
+		Limits:       a.limits,
+		Drained:      a.drained,
+	}
+	for _, def := range a.registry.Definitions() {
+		value, _ := a.value(def.Name)
+		data.Custom = append(data.Custom, customAbilityJSON{AbilityDefinition: def, Value: value})
+	}
+	return json.Marshal(data)
+}
+
+// UnmarshalJSON rebuilds the abilities and their custom registry, applying
+// the same range and budget rules as NewAbilitiesWithRegistry
+func (a *Abilities) UnmarshalJSON(b []byte) error {
+	var data abilitiesJSON
+	if err := json.Unmarshal(b, &data); err != nil {
+		return err
+	}
+
+	var reg *AbilityRegistry
+	scores := map[string]int{
+		"strength":     data.Strength,
+		"luck":         data.Luck,
+		"charisma":     data.Charisma,
+		"agility":      data.Agility,
+		"perception":   data.Perception,
+		"intelligence": data.Intelligence,
+	}
+	if len(data.Custom) > 0 {
+		reg = NewAbilityRegistry()
+		for _, custom := range data.Custom {
+			if err := reg.Register(custom.AbilityDefinition); err != nil {
+				return err
+			}
+			scores[custom.Name] = custom.Value
+		}
+	}
+
+	base := Abilities{pointsPool: data.PointsPool, granted: data.Granted, bonus: data.Bonus, registry: reg, costTable: data.CostTable, drained: data.Drained}
+	parsed, err := restoreAbilities(base, data.Limits, scores)
+	if err != nil {
+		return err
+	}
+	*a = parsed
+	return nil
+}
+
+// restoreAbilities rebuilds serialized abilities from base, which carries
+// the pool, granted and bonus points, registry and cost table. It checks
+// every score is in range and within its limit and that the spent points
+// and the pool add up to the total budget
+func restoreAbilities(base Abilities, limits map[string]Limit, scores map[string]int) (Abilities, error) {
+	if base.granted < 0 {
+		return Abilities{}, fmt.Errorf("granted points (%d) cannot be negative", base.granted)
+	}
+	parsed := base
+	parsed.custom = map[string]int{}
+	for _, name := range parsed.names() {
+		value, ok := scores[name]
+		if !ok {
+			return Abilities{}, fmt.Errorf("ability %s is missing", name)
+		}
+		if value < MinAbilityValue || value > MaxAbilityValue {
+			return Abilities{}, fmt.Errorf("ability %s value %d must be in range [%d, %d]",
+				name, value, MinAbilityValue, MaxAbilityValue)
+		}
+		parsed.setValue(name, value)
+	}
+	if err := parsed.SetLimits(limits); err != nil {
+		return Abilities{}, err
+	}
+	if err := parsed.validateDrain(); err != nil {
+		return Abilities{}, err
+	}
+	if len(limits) == 0 {
+		parsed.limits = nil
+	}
+	spent := parsed.spentPoints()
+	if parsed.pointsPool < 0 || spent+parsed.pointsPool != parsed.TotalBudget() {
+		return Abilities{}, fmt.Errorf("spent points (%d) and points pool (%d) must add up to %d",
+			spent, parsed.pointsPool, parsed.TotalBudget())
+	}
+	return parsed, nil
+}
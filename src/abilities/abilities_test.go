@@ -0,0 +1,38 @@
+package abilities
+
+import (
+	"sync"
+	"testing"
+)
+
+// TestWithAbilityConcurrent derives new values from one shared Abilities
+// from many goroutines. Run it with -race
+func TestWithAbilityConcurrent(t *testing.T) {
+	shared := NewDefaultAbilities()
+	var wg sync.WaitGroup
+	for g := range 16 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range 50 {
+				name := standardAbilities[(g+i)%len(standardAbilities)]
+				updated, err := shared.WithAbility(name, MinAbilityValue+(g+i)%(MaxAbilityValue-MinAbilityValue+1))
+				if err != nil {
+					continue
+				}
+				if pool := updated.GetPointsPool(); pool < 0 {
+					t.Errorf("points pool %d is negative", pool)
+				}
+				if updated.SpentPoints()+updated.GetPointsPool() != updated.TotalBudget() {
+					t.Errorf("spent %d + pool %d != budget %d", updated.SpentPoints(), updated.GetPointsPool(), updated.TotalBudget())
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	defaults := NewDefaultAbilities()
+	if !shared.Equals(&defaults) || shared.GetPointsPool() != AbilityPointBudget {
+		t.Errorf("shared abilities changed to %s, pool %d", shared.String(), shared.GetPointsPool())
+	}
+}
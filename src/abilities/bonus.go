@@ -0,0 +1,20 @@
+package abilities
+
+import "fmt"
+
+// ApplyFreeBonus changes an ability by delta without touching the points
+// pool, e.g. for racial bonuses. The new value is clamped to the ability's
+// limit (see LimitFor), and the total budget moves by the cost difference so
+// the abilities stay valid. It returns the change actually applied
+func (a *Abilities) ApplyFreeBonus(abilityName string, delta int) (int, error) {
+	currentValue, ok := a.value(abilityName)
+	if !ok {
+		return 0, fmt.Errorf("unknown ability: %s", abilityName)
+	}
+	limit := a.LimitFor(abilityName)
+	newValue := min(max(currentValue+delta, limit.Min), limit.Max)
+
+	a.bonus += a.pointCost(abilityName, newValue) - a.pointCost(abilityName, currentValue)
+	a.setValue(abilityName, newValue)
+	return newValue - currentValue, nil
+}
@@ -0,0 +1,70 @@
+package abilities
+
+import (
+	"fmt"
+	"math/rand"
+)
+
+// Contest sides
+const (
+	SideA = "a"
+	SideB = "b"
+)
+
+// ContestResult describes the rolls of an opposed ability contest
+type ContestResult struct {
+	AbilityA  string `json:"abilityA"`
+	AbilityB  string `json:"abilityB"`
+	RollA     int    `json:"rollA"`
+	RollB     int    `json:"rollB"`
+	ModifierA int    `json:"modifierA"`
+	ModifierB int    `json:"modifierB"`
+	TotalA    int    `json:"totalA"`
+	TotalB    int    `json:"totalB"`
+	Winner    string `json:"winner"`
+	// TieBreak is "score" when equal totals were settled by the raw scores
+	TieBreak string `json:"tieBreak,omitempty"`
+	Rerolls  int    `json:"rerolls"`
+}
+
+// Contest rolls an opposed d20 check of a's abilityA against b's abilityB,
+// each adding its modifier (see Modifier). Equal totals go to the higher raw
+// score; if the scores are equal as well, both sides roll again
+func Contest(a *Abilities, abilityA string, b *Abilities, abilityB string, rng *rand.Rand) (ContestResult, error) {
+	scoreA, ok := a.value(abilityA)
+	if !ok {
+		return ContestResult{}, fmt.Errorf("unknown ability: %s", abilityA)
+	}
+	scoreB, ok := b.value(abilityB)
+	if !ok {
+		return ContestResult{}, fmt.Errorf("unknown ability: %s", abilityB)
+	}
+
+	result := ContestResult{
+		AbilityA:  abilityA,
+		AbilityB:  abilityB,
+		ModifierA: modifierFor(scoreA),
+		ModifierB: modifierFor(scoreB),
+	}
+	for {
+		result.RollA = rng.Intn(20) + 1
+		result.RollB = rng.Intn(20) + 1
+		result.TotalA = result.RollA + result.ModifierA
+		result.TotalB = result.RollB + result.ModifierB
+
+		switch {
+		case result.TotalA > result.TotalB:
+			result.Winner = SideA
+		case result.TotalA < result.TotalB:
+			result.Winner = SideB
+		case scoreA > scoreB:
+			result.Winner, result.TieBreak = SideA, "score"
+		case scoreA < scoreB:
+			result.Winner, result.TieBreak = SideB, "score"
+		default:
+			result.Rerolls++
+			continue
+		}
+		return result, nil
+	}
+}
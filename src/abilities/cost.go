@@ -0,0 +1,49 @@
+package abilities
+
+import (
+	"fmt"
+	"strings"
+)
+
+// CostTable maps an ability value to the number of points it costs to raise
+// the ability from the value below to that value. Values without an entry
+// cost 1 point per step. Lowering an ability refunds the same amount
+type CostTable map[int]int
+
+var (
+	// LinearCostTable charges 1 point per step, which is the original point-buy rule
+	LinearCostTable = CostTable{}
+	// CurvedCostTable makes high scores expensive: 6–8 cost 1 point each, 9 costs 2 and 10 costs 3
+	CurvedCostTable = CostTable{9: 2, 10: 3}
+)
+
+// StepCost returns the cost of raising an ability to value from value-1
+func (t CostTable) StepCost(value int) int {
+	if cost, ok := t[value]; ok {
+		return cost
+	}
+	return 1
+}
+
+// Cost returns the signed number of points needed to move an ability from
+// one value to another. Negative results are refunds
+func (t CostTable) Cost(from int, to int) int {
+	total := 0
+	for v := from + 1; v <= to; v++ {
+		total += t.StepCost(v)
+	}
+	for v := from; v > to; v-- {
+		total -= t.StepCost(v)
+	}
+	return total
+}
+
+// Breakdown describes the cost of every step raising an ability from one
+// value to another, e.g. "8->9: 2, 9->10: 3"
+func (t CostTable) Breakdown(from int, to int) string {
+	steps := []string{}
+	for v := from + 1; v <= to; v++ {
+		steps = append(steps, fmt.Sprintf("%d->%d: %d", v-1, v, t.StepCost(v)))
+	}
+	return strings.Join(steps, ", ")
+}
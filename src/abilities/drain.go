@@ -0,0 +1,108 @@
+package abilities
+
+import (
+	"fmt"
+	"log/slog"
+)
+
+// Drain lowers an ability temporarily, e.g. from poison. Drain is tracked
+// separately from the score, never touches the points pool and only lowers
+// the current value, which is clamped at MinAbilityValue
+func (a *Abilities) Drain(abilityName string, amount int) error {
+	if _, ok := a.value(abilityName); !ok {
+		return fmt.Errorf("unknown ability: %s", abilityName)
+	}
+	if amount <= 0 {
+		return fmt.Errorf("drain amount %d must be positive", amount)
+	}
+	if a.drained == nil {
+		a.drained = map[string]int{}
+	}
+	a.drained[abilityName] += amount
+	slog.Debug("Ability drained", "ability", abilityName, "amount", amount, "drained", a.drained[abilityName])
+	return nil
+}
+
+// RestoreDrain gives back drained points of an ability. Restoration is
+// capped at the drained amount, so the ability never rises above its score
+func (a *Abilities) RestoreDrain(abilityName string, amount int) error {
+	if _, ok := a.value(abilityName); !ok {
+		return fmt.Errorf("unknown ability: %s", abilityName)
+	}
+	if amount <= 0 {
+		return fmt.Errorf("restore amount %d must be positive", amount)
+	}
+	drained := a.drained[abilityName]
+	if amount > drained {
+		amount = drained
+	}
+	if drained-amount == 0 {
+		delete(a.drained, abilityName)
+	} else {
+		a.drained[abilityName] = drained - amount
+	}
+	slog.Debug("Drained ability restored", "ability", abilityName, "amount", amount, "drained", drained-amount)
+	return nil
+}
+
+// Drained returns the drained amount of an ability
+func (a *Abilities) Drained(abilityName string) int {
+	return a.drained[abilityName]
+}
+
+// DrainedAbilities returns the drained amount of every drained ability
+func (a *Abilities) DrainedAbilities() map[string]int {
+	drained := make(map[string]int, len(a.drained))
+	for name, amount := range a.drained {
+		drained[name] = amount
+	}
+	return drained
+}
+
+// CurrentValue returns an ability's score minus its drain, clamped at
+// MinAbilityValue
+func (a *Abilities) CurrentValue(abilityName string) (int, error) {
+	value, ok := a.value(abilityName)
+	if !ok {
+		return 0, fmt.Errorf("unknown ability: %s", abilityName)
+	}
+	return max(value-a.drained[abilityName], MinAbilityValue), nil
+}
+
+// CurrentAbilities returns the current value of every ability
+func (a *Abilities) CurrentAbilities() map[string]int {
+	current := a.GetAllAbilities()
+	for name := range current {
+		current[name], _ = a.CurrentValue(name)
+	}
+	return current
+}
+
+// validateDrain checks the drained amounts belong to known abilities and
+// are positive
+func (a *Abilities) validateDrain() error {
+	for name, amount := range a.drained {
+		if _, ok := a.value(name); !ok {
+			return fmt.Errorf("unknown ability: %s", name)
+		}
+		if amount <= 0 {
+			return fmt.Errorf("drained %s amount %d must be positive", name, amount)
+		}
+	}
+	return nil
+}
+
+// WithBonuses returns a snapshot of the current abilities with bonuses
+// added, each clamped to [MinAbilityValue, MaxAbilityValue]. Drain is folded
+// into the scores, and the bonuses are not charged to the points pool, so
+// the snapshot is meant for reading effective values, not for point-buy
+func (a *Abilities) WithBonuses(bonuses map[string]int) Abilities {
+	effective := a.Clone()
+	effective.drained = nil
+	effective.limits = nil
+	for _, name := range a.names() {
+		current, _ := a.CurrentValue(name)
+		effective.setValue(name, min(max(current+bonuses[name], MinAbilityValue), MaxAbilityValue))
+	}
+	return effective
+}
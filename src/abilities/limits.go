@@ -0,0 +1,84 @@
+package abilities
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Limit overrides the global ability range for a single ability, e.g. a
+// racial cap or floor
+type Limit struct {
+	Min int `json:"min"`
+	Max int `json:"max"`
+}
+
+// SetLimits replaces the per-ability limits. Abilities without a limit fall
+// back to [MinAbilityValue, MaxAbilityValue]. Limits must lie within the
+// global range and the current scores must already satisfy them
+func (a *Abilities) SetLimits(limits map[string]Limit) error {
+	for name, limit := range limits {
+		if _, ok := a.value(name); !ok {
+			return fmt.Errorf("unknown ability: %s", name)
+		}
+		if limit.Min < MinAbilityValue || limit.Max > MaxAbilityValue || limit.Min > limit.Max {
+			return fmt.Errorf("limit [%d, %d] for %s must be a range within [%d, %d]",
+				limit.Min, limit.Max, name, MinAbilityValue, MaxAbilityValue)
+		}
+	}
+
+	previous := a.limits
+	a.limits = make(map[string]Limit, len(limits))
+	for name, limit := range limits {
+		a.limits[name] = limit
+	}
+	for _, name := range a.names() {
+		value, _ := a.value(name)
+		if err := a.checkLimit(name, value); err != nil {
+			a.limits = previous
+			return err
+		}
+	}
+	return nil
+}
+
+// Limits returns a copy of the per-ability limits
+func (a *Abilities) Limits() map[string]Limit {
+	limits := make(map[string]Limit, len(a.limits))
+	for name, limit := range a.limits {
+		limits[name] = limit
+	}
+	return limits
+}
+
+// LimitFor returns the range an ability must stay in
+func (a *Abilities) LimitFor(abilityName string) Limit {
+	if limit, ok := a.limits[abilityName]; ok {
+		return limit
+	}
+	return Limit{Min: MinAbilityValue, Max: MaxAbilityValue}
+}
+
+// checkLimit validates a value against the ability's own limit, if it has one
+func (a *Abilities) checkLimit(abilityName string, value int) error {
+	limit, ok := a.limits[abilityName]
+	if !ok {
+		return nil
+	}
+	if value > limit.Max {
+		return fmt.Errorf("%s %d exceeds its cap of %d", abilityName, value, limit.Max)
+	}
+	if value < limit.Min {
+		return fmt.Errorf("%s %d is below its floor of %d", abilityName, value, limit.Min)
+	}
+	return nil
+}
+
+// limitNames returns the names of abilities with limits in a stable order
+func (a *Abilities) limitNames() []string {
+	names := make([]string, 0, len(a.limits))
+	for name := range a.limits {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,38 @@
+package abilities
+
+// modifierFor converts a score into its modifier: value - DefaultAbilityValue.
+// This is the classic (score-10)/2 at half scale, since scores here run 1–10
+// around an average of 5 instead of 3–20 around 10. A score of 1 gives -4,
+// 5 gives +0 and 10 gives +5, the same spread as the classic table
+func modifierFor(value int) int {
+	return value - DefaultAbilityValue
+}
+
+// Modifier returns the modifier of an ability's current (drain-aware) value.
+// Checks and combat add the modifier to their d20 rolls
+func (a *Abilities) Modifier(name string) (int, error) {
+	value, err := a.CurrentValue(name)
+	if err != nil {
+		return 0, err
+	}
+	return modifierFor(value), nil
+}
+
+// OrderedModifiers returns the modifier of every ability in the order of
+// OrderedAbilities
+func (a *Abilities) OrderedModifiers() AbilityValues {
+	ordered := a.OrderedAbilities()
+	for i := range ordered {
+		ordered[i].Value, _ = a.Modifier(ordered[i].Name)
+	}
+	return ordered
+}
+
+// Modifiers returns the modifier of every ability, keyed like GetAllAbilities
+func (a *Abilities) Modifiers() map[string]int {
+	modifiers := a.CurrentAbilities()
+	for name, value := range modifiers {
+		modifiers[name] = modifierFor(value)
+	}
+	return modifiers
+}
@@ -0,0 +1,81 @@
+package abilities
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// AbilityValue is the score, or modifier, of one ability
+type AbilityValue struct {
+	Name  string
+	Value int
+}
+
+// AbilityValues is a list of abilities in a fixed order. It encodes as a
+// JSON object whose keys keep that order, so responses do not depend on map
+// iteration
+type AbilityValues []AbilityValue
+
+// OrderedAbilities returns the value of every ability like GetAllAbilities,
+// in canonical order: strength, luck, charisma, agility, perception and
+// intelligence, then custom abilities in the order they were registered
+func (a *Abilities) OrderedAbilities() AbilityValues {
+	names := a.names()
+	ordered := make(AbilityValues, 0, len(names))
+	for _, name := range names {
+		value, _ := a.value(name)
+		ordered = append(ordered, AbilityValue{Name: name, Value: value})
+	}
+	return ordered
+}
+
+// Map returns the values keyed by ability name
+func (v AbilityValues) Map() map[string]int {
+	m := make(map[string]int, len(v))
+	for _, ability := range v {
+		m[ability.Name] = ability.Value
+	}
+	return m
+}
+
+// MarshalJSON encodes the values as an object in list order
+func (v AbilityValues) MarshalJSON() ([]byte, error) {
+	var b bytes.Buffer
+	b.WriteByte('{')
+	for i, ability := range v {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		key, err := json.Marshal(ability.Name)
+		if err != nil {
+			return nil, err
+		}
+		fmt.Fprintf(&b, "%s:%d", key, ability.Value)
+	}
+	b.WriteByte('}')
+	return b.Bytes(), nil
+}
+
+// UnmarshalJSON decodes an object of ability values, keeping the order of
+// its keys
+func (v *AbilityValues) UnmarshalJSON(b []byte) error {
+	decoder := json.NewDecoder(bytes.NewReader(b))
+	if token, err := decoder.Token(); err != nil || token != json.Delim('{') {
+		return fmt.Errorf("ability values must be an object")
+	}
+	values := AbilityValues{}
+	for decoder.More() {
+		token, err := decoder.Token()
+		if err != nil {
+			return err
+		}
+		var value int
+		if err := decoder.Decode(&value); err != nil {
+			return fmt.Errorf("ability %s: %w", token, err)
+		}
+		values = append(values, AbilityValue{Name: token.(string), Value: value})
+	}
+	*v = values
+	return nil
+}
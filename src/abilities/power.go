@@ -0,0 +1,95 @@
+package abilities
+
+// DefaultPowerWeights are the weights PowerRating uses. Abilities without a
+// weight count once
+var DefaultPowerWeights = map[string]int{
+	"strength":     2,
+	"agility":      2,
+	"intelligence": 2,
+	"luck":         1,
+	"charisma":     1,
+	"perception":   1,
+}
+
+// AbilityDiff holds per-ability deltas between two ability blocks
+type AbilityDiff struct {
+	Deltas map[string]int `json:"deltas"`
+	Net    int            `json:"net"`
+}
+
+// PowerRating returns a single comparable number for the abilities, the sum
+// of every score times its weight in DefaultPowerWeights
+func (a *Abilities) PowerRating() int {
+	return a.WeightedPowerRating(DefaultPowerWeights)
+}
+
+// WeightedPowerRating is PowerRating with custom weights. Abilities missing
+// from weights count once
+func (a *Abilities) WeightedPowerRating(weights map[string]int) int {
+	rating := 0
+	for name, value := range a.GetAllAbilities() {
+		weight, ok := weights[name]
+		if !ok {
+			weight = 1
+		}
+		rating += value * weight
+	}
+	return rating
+}
+
+// Compare returns other minus a for every ability of either block and the
+// net difference. Compare(a, b) is always the negation of Compare(b, a)
+func (a *Abilities) Compare(other *Abilities) AbilityDiff {
+	diff := AbilityDiff{Deltas: a.Diff(other)}
+	for _, delta := range diff.Deltas {
+		diff.Net += delta
+	}
+	return diff
+}
+
+// Diff returns other minus a for every ability of either block, including
+// unchanged ones with a delta of 0. A nil block counts as all zeros, so
+// a.Diff(nil) is the negation of every score of a
+func (a *Abilities) Diff(other *Abilities) map[string]int {
+	mine, theirs := a.scores(), other.scores()
+	deltas := make(map[string]int, len(mine))
+	for name, value := range mine {
+		deltas[name] = theirs[name] - value
+	}
+	for name, value := range theirs {
+		if _, ok := mine[name]; !ok {
+			deltas[name] = value
+		}
+	}
+	return deltas
+}
+
+// Equals checks if both blocks have the same abilities with the same scores
+// and the same points pool. Two nil blocks are equal, a nil and a non-nil
+// block never are
+func (a *Abilities) Equals(other *Abilities) bool {
+	if a == nil || other == nil {
+		return a == other
+	}
+	if a.pointsPool != other.pointsPool {
+		return false
+	}
+	mine, theirs := a.GetAllAbilities(), other.GetAllAbilities()
+	if len(mine) != len(theirs) {
+		return false
+	}
+	for name, value := range mine {
+		if theirs[name] != value {
+			return false
+		}
+	}
+	return true
+}
+
+// scores is GetAllAbilities that treats a nil block as having no scores
+func (a *Abilities) scores() map[string]int {
+	if a == nil {
+		return map[string]int{}
+	}
+	return a.GetAllAbilities()
+}
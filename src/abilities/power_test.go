@@ -0,0 +1,36 @@
+package abilities
+
+import (
+	"maps"
+	"testing"
+)
+
+func TestCompareIsAntisymmetric(t *testing.T) {
+	blocks := textFixtures(t)
+	var none *Abilities
+	for nameA, a := range blocks {
+		for nameB, b := range blocks {
+			forward, backward := a.Compare(&b), b.Compare(&a)
+			if forward.Net != -backward.Net {
+				t.Errorf("%s vs %s: net %d and %d", nameA, nameB, forward.Net, backward.Net)
+			}
+			negated := map[string]int{}
+			for name, delta := range backward.Deltas {
+				negated[name] = -delta
+			}
+			if !maps.Equal(forward.Deltas, negated) {
+				t.Errorf("%s vs %s: deltas %v and %v", nameA, nameB, forward.Deltas, backward.Deltas)
+			}
+		}
+
+		// A nil block counts as all zeros
+		total := 0
+		for _, value := range a.GetAllAbilities() {
+			total += value
+		}
+		forward, backward := a.Compare(nil), none.Compare(&a)
+		if forward.Net != -total || backward.Net != total {
+			t.Errorf("%s vs nil: net %d and %d, want %d and %d", nameA, forward.Net, backward.Net, -total, total)
+		}
+	}
+}
@@ -0,0 +1,53 @@
+package abilities
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+var (
+	presetsMu sync.RWMutex
+	// presets maps lower-case class names to ability spreads that spend the
+	// whole AbilityPointBudget
+	presets = map[string]map[string]int{
+		"warrior": {"strength": 8, "luck": 5, "charisma": 5, "agility": 6, "perception": 6, "intelligence": 5},
+		"mage":    {"strength": 5, "luck": 5, "charisma": 5, "agility": 5, "perception": 6, "intelligence": 9},
+		"rogue":   {"strength": 5, "luck": 7, "charisma": 5, "agility": 8, "perception": 5, "intelligence": 5},
+		"ranger":  {"strength": 6, "luck": 5, "charisma": 5, "agility": 7, "perception": 7, "intelligence": 5},
+	}
+)
+
+// RegisterPreset adds or replaces the ability preset of a class. The scores
+// must be valid under NewAbilities
+func RegisterPreset(class string, scores map[string]int) error {
+	if _, err := NewAbilitiesWithRegistry(nil, scores); err != nil {
+		return fmt.Errorf("invalid preset for %s: %w", class, err)
+	}
+	preset := make(map[string]int, len(scores))
+	for name, value := range scores {
+		preset[name] = value
+	}
+
+	presetsMu.Lock()
+	defer presetsMu.Unlock()
+	presets[strings.ToLower(class)] = preset
+	return nil
+}
+
+// PresetFor returns the preset abilities of a class, matched case-insensitively
+func PresetFor(class string) (Abilities, bool) {
+	presetsMu.RLock()
+	preset, ok := presets[strings.ToLower(class)]
+	presetsMu.RUnlock()
+	if !ok {
+		return Abilities{}, false
+	}
+
+	abilities, err := NewAbilitiesWithRegistry(nil, preset)
+	if err != nil {
+		// Presets are validated on registration
+		return Abilities{}, false
+	}
+	return abilities, true
+}
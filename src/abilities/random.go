@@ -0,0 +1,35 @@
+package abilities
+
+import (
+	"math/rand"
+)
+
+// maxRandomAttempts bounds the random steps of Randomize, so abilities
+// boxed in by limits cannot loop forever
+const maxRandomAttempts = 100
+
+// NewRandomAbilities returns default abilities with the whole
+// AbilityPointBudget spent at random, see Randomize. The same seed always
+// gives the same abilities, and the result passes ValidateAbilities
+func NewRandomAbilities(seed int64) Abilities {
+	abs := NewDefaultAbilities()
+	abs.Randomize(rand.New(rand.NewSource(seed)))
+	return abs
+}
+
+// Randomize lowers a few random standard abilities to free points and then
+// spends the points pool on random raises. Every step goes through
+// SetAbility, so scores stay within [MinAbilityValue, MaxAbilityValue] and
+// any limits, and steps that would break them are skipped
+func (a *Abilities) Randomize(rng *rand.Rand) {
+	for n := rng.Intn(4); n > 0; n-- {
+		name := standardAbilities[rng.Intn(len(standardAbilities))]
+		value, _ := a.value(name)
+		_ = a.SetAbility(name, value-1)
+	}
+	for attempts := 0; a.pointsPool > 0 && attempts < maxRandomAttempts; attempts++ {
+		name := standardAbilities[rng.Intn(len(standardAbilities))]
+		value, _ := a.value(name)
+		_ = a.SetAbility(name, value+1)
+	}
+}
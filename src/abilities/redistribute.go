@@ -0,0 +1,61 @@
+package abilities
+
+import (
+	"dnd-helper/src/validation"
+	"log/slog"
+)
+
+// Redistribute replaces all ability scores at once. values must contain the
+// six standard abilities and may contain registered custom ones; omitted
+// custom abilities keep their score. The whole set is checked against the
+// ranges, limits and total budget before anything changes, so on error the
+// abilities are left untouched. On success the points pool holds whatever
+// the new set leaves unspent
+func (a *Abilities) Redistribute(values map[string]int) error {
+	updated := a.Clone()
+
+	var errs validation.ValidationErrors
+	for name := range values {
+		if !isStandardAbility(name) {
+			if _, ok := a.registry.Lookup(name); !ok {
+				errs.Add(name, "unknown ability: %s", name)
+			}
+		}
+	}
+	for _, name := range standardAbilities {
+		if _, ok := values[name]; !ok {
+			errs.Add(name, "ability %s is missing", name)
+		}
+	}
+	for _, name := range updated.names() {
+		value, ok := values[name]
+		if !ok {
+			continue
+		}
+		if value < MinAbilityValue || value > MaxAbilityValue {
+			errs.Add(name, "ability %s value %d must be in range [%d, %d]",
+				name, value, MinAbilityValue, MaxAbilityValue)
+			continue
+		}
+		if err := updated.checkLimit(name, value); err != nil {
+			errs.Add(name, "%v", err)
+			continue
+		}
+		updated.setValue(name, value)
+	}
+	if err := errs.Err(); err != nil {
+		return err
+	}
+
+	spent := updated.spentPoints()
+	if spent > updated.TotalBudget() {
+		errs.Add("pointsPool", "total ability point cost (%d) exceeds the budget of %d points",
+			spent, updated.TotalBudget())
+		return errs
+	}
+	updated.pointsPool = updated.TotalBudget() - spent
+
+	*a = updated
+	slog.Debug("Abilities redistributed", "pointsPool", a.pointsPool)
+	return nil
+}
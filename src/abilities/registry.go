@@ -0,0 +1,69 @@
+package abilities
+
+import (
+	"fmt"
+)
+
+// AbilityDefinition describes a homebrew ability score that is tracked
+// alongside the six standard abilities
+type AbilityDefinition struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Default     int    `json:"default"`
+}
+
+// AbilityRegistry holds the custom abilities of a single ruleset.
+// Registries are attached to Abilities at character-creation time, so two
+// characters can use different rulesets
+type AbilityRegistry struct {
+	definitions []AbilityDefinition
+}
+
+// NewAbilityRegistry creates an empty registry
+func NewAbilityRegistry() *AbilityRegistry {
+	return &AbilityRegistry{
+		definitions: []AbilityDefinition{},
+	}
+}
+
+// Register adds a custom ability definition to the registry
+func (r *AbilityRegistry) Register(def AbilityDefinition) error {
+	if def.Name == "" {
+		return fmt.Errorf("custom ability name cannot be empty")
+	}
+	if isStandardAbility(def.Name) {
+		return fmt.Errorf("custom ability %s collides with a standard ability", def.Name)
+	}
+	if _, ok := r.Lookup(def.Name); ok {
+		return fmt.Errorf("custom ability %s is already registered", def.Name)
+	}
+	if def.Default < MinAbilityValue || def.Default > MaxAbilityValue {
+		return fmt.Errorf("custom ability %s default %d must be in range [%d, %d]",
+			def.Name, def.Default, MinAbilityValue, MaxAbilityValue)
+	}
+	r.definitions = append(r.definitions, def)
+	return nil
+}
+
+// Lookup returns the definition registered under name
+func (r *AbilityRegistry) Lookup(name string) (AbilityDefinition, bool) {
+	if r == nil {
+		return AbilityDefinition{}, false
+	}
+	for _, def := range r.definitions {
+		if def.Name == name {
+			return def, true
+		}
+	}
+	return AbilityDefinition{}, false
+}
+
+// Definitions returns all registered definitions in registration order
+func (r *AbilityRegistry) Definitions() []AbilityDefinition {
+	if r == nil {
+		return nil
+	}
+	defs := make([]AbilityDefinition, len(r.definitions))
+	copy(defs, r.definitions)
+	return defs
+}
@@ -0,0 +1,71 @@
+package abilities
+
+import (
+	"fmt"
+	"io"
+)
+
+// Supported RenderSheet formats
+const (
+	FormatText     = "text"
+	FormatMarkdown = "markdown"
+)
+
+// BonusSource provides ability bonuses from outside the abilities block,
+// such as the items of an inventory
+type BonusSource interface {
+	AbilityBonuses() map[string]int
+}
+
+// signed formats a number with an explicit sign, e.g. "+1" or "-2"
+func signed(n int) string {
+	return fmt.Sprintf("%+d", n)
+}
+
+// RenderSheet writes every ability with its modifier and the
+// remaining points pool. Item bonuses are listed when bonuses is not nil.
+// format is FormatText or FormatMarkdown
+func (a *Abilities) RenderSheet(w io.Writer, format string, bonuses BonusSource) error {
+	var itemBonuses map[string]int
+	if bonuses != nil {
+		itemBonuses = bonuses.AbilityBonuses()
+	}
+
+	switch format {
+	case FormatText:
+		fmt.Fprintf(w, "%-14s %5s %4s", "Ability", "Score", "Mod")
+		if itemBonuses != nil {
+			fmt.Fprintf(w, " %6s", "Items")
+		}
+		fmt.Fprintln(w)
+		for _, name := range a.names() {
+			value, _ := a.value(name)
+			fmt.Fprintf(w, "%-14s %5d %4s", name, value, signed(modifierFor(value)))
+			if itemBonuses != nil {
+				fmt.Fprintf(w, " %6s", signed(itemBonuses[name]))
+			}
+			fmt.Fprintln(w)
+		}
+		fmt.Fprintf(w, "Points pool: %d\n", a.pointsPool)
+	case FormatMarkdown:
+		if itemBonuses != nil {
+			fmt.Fprintln(w, "| Ability | Score | Modifier | Item bonus |")
+			fmt.Fprintln(w, "|---|---:|---:|---:|")
+		} else {
+			fmt.Fprintln(w, "| Ability | Score | Modifier |")
+			fmt.Fprintln(w, "|---|---:|---:|")
+		}
+		for _, name := range a.names() {
+			value, _ := a.value(name)
+			fmt.Fprintf(w, "| %s | %d | %s |", name, value, signed(modifierFor(value)))
+			if itemBonuses != nil {
+				fmt.Fprintf(w, " %s |", signed(itemBonuses[name]))
+			}
+			fmt.Fprintln(w)
+		}
+		fmt.Fprintf(w, "\n**Points pool:** %d\n", a.pointsPool)
+	default:
+		return fmt.Errorf("unknown sheet format %q, supported formats are %q and %q", format, FormatText, FormatMarkdown)
+	}
+	return nil
+}
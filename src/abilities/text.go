@@ -0,0 +1,207 @@
+package abilities
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// textKeys maps the standard abilities to their short keys in the text form
+var textKeys = map[string]string{
+	"strength":     "str",
+	"luck":         "luck",
+	"charisma":     "cha",
+	"agility":      "agi",
+	"perception":   "per",
+	"intelligence": "int",
+}
+
+// MarshalText encodes the abilities in a compact single-line form such as
+// "str=6;luck=5;cha=5;agi=6;per=5;int=8;pool=0". Custom abilities are written
+// under their own names, followed by "granted", "bonus", "cost", "limits" and
+// "drain" when they differ from the defaults
+func (a Abilities) MarshalText() ([]byte, error) {
+	parts := []string{}
+	for _, name := range a.names() {
+		value, _ := a.value(name)
+		key, ok := textKeys[name]
+		if !ok {
+			key = name
+		}
+		parts = append(parts, fmt.Sprintf("%s=%d", key, value))
+	}
+	parts = append(parts, fmt.Sprintf("pool=%d", a.pointsPool))
+	if a.granted != 0 {
+		parts = append(parts, fmt.Sprintf("granted=%d", a.granted))
+	}
+	if a.bonus != 0 {
+		parts = append(parts, fmt.Sprintf("bonus=%d", a.bonus))
+	}
+	if len(a.costTable) > 0 {
+		steps := make([]int, 0, len(a.costTable))
+		for value := range a.costTable {
+			steps = append(steps, value)
+		}
+		sort.Ints(steps)
+		costs := make([]string, len(steps))
+		for i, value := range steps {
+			costs[i] = fmt.Sprintf("%d:%d", value, a.costTable[value])
+		}
+		parts = append(parts, "cost="+strings.Join(costs, ","))
+	}
+	if len(a.limits) > 0 {
+		limits := []string{}
+		for _, name := range a.limitNames() {
+			limit := a.limits[name]
+			limits = append(limits, fmt.Sprintf("%s:%d:%d", name, limit.Min, limit.Max))
+		}
+		parts = append(parts, "limits="+strings.Join(limits, ","))
+	}
+	if len(a.drained) > 0 {
+		drained := []string{}
+		for _, name := range a.names() {
+			if amount, ok := a.drained[name]; ok {
+				drained = append(drained, fmt.Sprintf("%s:%d", name, amount))
+			}
+		}
+		parts = append(parts, "drain="+strings.Join(drained, ","))
+	}
+	return []byte(strings.Join(parts, ";")), nil
+}
+
+// UnmarshalText parses the form produced by MarshalText and runs the same
+// range and budget validation as NewAbilities. Custom abilities are resolved
+// through the registry already attached to the receiver; unknown keys and
+// missing abilities are rejected
+func (a *Abilities) UnmarshalText(text []byte) error {
+	reg := a.registry
+	scores := map[string]int{}
+	pool, granted, bonus := 0, 0, 0
+	var table CostTable
+	var limits map[string]Limit
+	var drained map[string]int
+	seenPool := false
+
+	for _, part := range strings.Split(string(text), ";") {
+		key, raw, ok := strings.Cut(part, "=")
+		if !ok {
+			return fmt.Errorf("invalid abilities entry %q", part)
+		}
+
+		if key == "cost" {
+			table = CostTable{}
+			for _, step := range strings.Split(raw, ",") {
+				valueText, costText, ok := strings.Cut(step, ":")
+				if !ok {
+					return fmt.Errorf("invalid cost table step %q", step)
+				}
+				value, err := strconv.Atoi(valueText)
+				if err != nil {
+					return fmt.Errorf("invalid cost table step %q", step)
+				}
+				cost, err := strconv.Atoi(costText)
+				if err != nil {
+					return fmt.Errorf("invalid cost table step %q", step)
+				}
+				table[value] = cost
+			}
+			continue
+		}
+
+		if key == "limits" {
+			limits = map[string]Limit{}
+			for _, entry := range strings.Split(raw, ",") {
+				var limit Limit
+				fields := strings.Split(entry, ":")
+				if len(fields) != 3 {
+					return fmt.Errorf("invalid limit %q", entry)
+				}
+				if _, err := fmt.Sscanf(fields[1]+" "+fields[2], "%d %d", &limit.Min, &limit.Max); err != nil {
+					return fmt.Errorf("invalid limit %q", entry)
+				}
+				limits[fields[0]] = limit
+			}
+			continue
+		}
+
+		if key == "drain" {
+			drained = map[string]int{}
+			for _, entry := range strings.Split(raw, ",") {
+				name, amountText, ok := strings.Cut(entry, ":")
+				amount, err := strconv.Atoi(amountText)
+				if !ok || err != nil {
+					return fmt.Errorf("invalid drain %q", entry)
+				}
+				drained[name] = amount
+			}
+			continue
+		}
+
+		value, err := strconv.Atoi(raw)
+		if err != nil {
+			return fmt.Errorf("invalid value for %s: %q", key, raw)
+		}
+		switch key {
+		case "pool":
+			pool, seenPool = value, true
+			continue
+		case "granted":
+			granted = value
+			continue
+		case "bonus":
+			bonus = value
+			continue
+		}
+
+		name := ""
+		for ability, short := range textKeys {
+			if short == key {
+				name = ability
+			}
+		}
+		if name == "" {
+			if _, ok := reg.Lookup(key); !ok {
+				return fmt.Errorf("unknown ability: %s", key)
+			}
+			name = key
+		}
+		if _, dup := scores[name]; dup {
+			return fmt.Errorf("ability %s is given twice", name)
+		}
+		scores[name] = value
+	}
+	if !seenPool {
+		return fmt.Errorf("points pool is missing")
+	}
+
+	base := Abilities{pointsPool: pool, granted: granted, bonus: bonus, registry: reg, costTable: table, drained: drained}
+	parsed, err := restoreAbilities(base, limits, scores)
+	if err != nil {
+		return err
+	}
+	*a = parsed
+	return nil
+}
+
+// Value implements driver.Valuer by storing the text form in a single column
+func (a Abilities) Value() (driver.Value, error) {
+	text, err := a.MarshalText()
+	if err != nil {
+		return nil, err
+	}
+	return string(text), nil
+}
+
+// Scan implements sql.Scanner by parsing the text form
+func (a *Abilities) Scan(src any) error {
+	switch v := src.(type) {
+	case string:
+		return a.UnmarshalText([]byte(v))
+	case []byte:
+		return a.UnmarshalText(v)
+	default:
+		return fmt.Errorf("cannot scan %T into Abilities", src)
+	}
+}
@@ -0,0 +1,99 @@
+package abilities
+
+import (
+	"testing"
+)
+
+// textFixtures returns ability blocks covering every part of the text form
+func textFixtures(t *testing.T) map[string]Abilities {
+	t.Helper()
+	spent, err := NewAbilities(7, 5, 4, 6, 5, 8)
+	if err != nil {
+		t.Fatal(err)
+	}
+	granted := NewDefaultAbilities()
+	if err := granted.GrantPoints(3); err != nil {
+		t.Fatal(err)
+	}
+	if err := granted.SetAbility("luck", 8); err != nil {
+		t.Fatal(err)
+	}
+	limited := spent.Clone()
+	if err := limited.SetLimits(map[string]Limit{"strength": {Min: 3, Max: 8}}); err != nil {
+		t.Fatal(err)
+	}
+	drained := spent.Clone()
+	if err := drained.Drain("agility", 2); err != nil {
+		t.Fatal(err)
+	}
+	return map[string]Abilities{
+		"unspent pool": NewDefaultAbilities(),
+		"spent pool":   spent,
+		"granted":      granted,
+		"limits":       limited,
+		"drained":      drained,
+	}
+}
+
+func TestTextRoundTrip(t *testing.T) {
+	for name, original := range textFixtures(t) {
+		t.Run(name, func(t *testing.T) {
+			text, err := original.MarshalText()
+			if err != nil {
+				t.Fatal(err)
+			}
+			var decoded Abilities
+			if err := decoded.UnmarshalText(text); err != nil {
+				t.Fatalf("unmarshal %q: %v", text, err)
+			}
+			again, _ := decoded.MarshalText()
+			if string(again) != string(text) {
+				t.Errorf("round trip changed %q to %q", text, again)
+			}
+			if !decoded.Equals(&original) || decoded.TotalBudget() != original.TotalBudget() {
+				t.Errorf("round trip of %q lost information: %s pool %d budget %d", text, decoded.String(), decoded.GetPointsPool(), decoded.TotalBudget())
+			}
+		})
+	}
+}
+
+func TestValueScanRoundTrip(t *testing.T) {
+	for name, original := range textFixtures(t) {
+		t.Run(name, func(t *testing.T) {
+			value, err := original.Value()
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, src := range []any{value, []byte(value.(string))} {
+				var scanned Abilities
+				if err := scanned.Scan(src); err != nil {
+					t.Fatalf("scan %q: %v", value, err)
+				}
+				if again, _ := scanned.Value(); again != value {
+					t.Errorf("Value/Scan changed %q to %q", value, again)
+				}
+			}
+		})
+	}
+}
+
+func TestUnmarshalTextRejectsInvalid(t *testing.T) {
+	for _, text := range []string{
+		"str=5;luck=5;cha=5;agi=5;per=5;int=5",              // missing pool
+		"str=5;luck=5;cha=5;agi=5;per=5;pool=5",             // missing intelligence
+		"str=5;luck=5;cha=5;agi=5;per=5;int=5;wis=5;pool=5", // unknown key
+		"str=11;luck=5;cha=5;agi=5;per=5;int=4;pool=0",      // above the range
+		"str=9;luck=5;cha=5;agi=5;per=5;int=5;pool=5",       // over budget
+		"str=5;str=5;luck=5;cha=5;agi=5;per=5;int=5;pool=5",
+		"str5;luck=5;cha=5;agi=5;per=5;int=5;pool=5",
+	} {
+		var a Abilities
+		if err := a.UnmarshalText([]byte(text)); err == nil {
+			t.Errorf("UnmarshalText accepted %q", text)
+		}
+	}
+	var a Abilities
+	if err := a.Scan(42); err == nil {
+		t.Error("Scan accepted an int")
+	}
+}
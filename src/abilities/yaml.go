@@ -0,0 +1,145 @@
+package abilities
+
+import (
+	"bytes"
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+)
+
+// abilitiesYAML is the document described in the comment at the top of
+// abilities.go, with the schema's integer placeholders filled in
+type abilitiesYAML struct {
+	Ability abilityYAML `yaml:"ability"`
+}
+
+type abilityYAML struct {
+	MinAbilityValue int                 `yaml:"minAbilityValue"`
+	MaxAbilityValue int                 `yaml:"maxAbilityValue"`
+	Defaults        abilityDefaultsYAML `yaml:"defaults"`
+	PointsPool      int                 `yaml:"pointsPool"`
+	GrantedPoints   int                 `yaml:"grantedPoints,omitempty"`
+	BonusPoints     int                 `yaml:"bonusPoints,omitempty"`
+	Strength        *int                `yaml:"strength"`
+	Luck            *int                `yaml:"luck"`
+	Charisma        *int                `yaml:"charisma"`
+	Agility         *int                `yaml:"agility"`
+	Perception      *int                `yaml:"perception"`
+	Intelligence    *int                `yaml:"intelligence"`
+	Custom          []customAbilityYAML `yaml:"custom,omitempty"`
+	CostTable       CostTable           `yaml:"costTable,omitempty"`
+	Limits          map[string]Limit    `yaml:"limits,omitempty"`
+	Drained         map[string]int      `yaml:"drained,omitempty"`
+}
+
+// customAbilityYAML is a custom ability with its definition, as in
+// customAbilityJSON
+type customAbilityYAML struct {
+	Name        string `yaml:"name"`
+	Description string `yaml:"description,omitempty"`
+	Default     int    `yaml:"default"`
+	Value       int    `yaml:"value"`
+}
+
+type abilityDefaultsYAML struct {
+	ValueOfEachAbility int `yaml:"valueOfEachAbility"`
+	AbilityPoints      int `yaml:"abilityPoints"`
+}
+
+// ToYAML encodes the abilities in the schema documented at the top of
+// abilities.go. Custom abilities with their definitions, the cost table,
+// limits and drain are added under their own keys when present
+func (a *Abilities) ToYAML() ([]byte, error) {
+	doc := abilitiesYAML{Ability: abilityYAML{
+		MinAbilityValue: MinAbilityValue,
+		MaxAbilityValue: MaxAbilityValue,
+		Defaults: abilityDefaultsYAML{
+			ValueOfEachAbility: DefaultAbilityValue,
+			AbilityPoints:      AbilityPointBudget,
+		},
+		PointsPool:    a.pointsPool,
+		GrantedPoints: a.granted,
+		BonusPoints:   a.bonus,
+		Strength:      &a.strength,
+		Luck:          &a.luck,
+		Charisma:      &a.charisma,
+		Agility:       &a.agility,
+		Perception:    &a.perception,
+		Intelligence:  &a.intelligence,
+		CostTable:     a.costTable,
+		Limits:        a.limits,
+		Drained:       a.drained,
+	}}
+	for _, def := range a.registry.Definitions() {
+		value, _ := a.value(def.Name)
+		doc.Ability.Custom = append(doc.Ability.Custom, customAbilityYAML{Name: def.Name, Description: def.Description, Default: def.Default, Value: value})
+	}
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(doc); err != nil {
+		return nil, err
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// AbilitiesFromYAML parses the form produced by ToYAML. Unknown keys and
+// missing abilities are rejected, the range and defaults must match this
+// ruleset if given, and the scores go through the same range, limit, drain
+// and budget validation as UnmarshalJSON
+func AbilitiesFromYAML(data []byte) (Abilities, error) {
+	var doc abilitiesYAML
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&doc); err != nil {
+		return Abilities{}, fmt.Errorf("abilities yaml: %w", err)
+	}
+
+	body := doc.Ability
+	rules := []struct {
+		key          string
+		value, fixed int
+	}{
+		{"minAbilityValue", body.MinAbilityValue, MinAbilityValue},
+		{"maxAbilityValue", body.MaxAbilityValue, MaxAbilityValue},
+		{"defaults.valueOfEachAbility", body.Defaults.ValueOfEachAbility, DefaultAbilityValue},
+		{"defaults.abilityPoints", body.Defaults.AbilityPoints, AbilityPointBudget},
+	}
+	for _, rule := range rules {
+		if rule.value != 0 && rule.value != rule.fixed {
+			return Abilities{}, fmt.Errorf("abilities yaml: %s is %d, this ruleset uses %d", rule.key, rule.value, rule.fixed)
+		}
+	}
+
+	scores := map[string]int{}
+	for name, value := range map[string]*int{
+		"strength":     body.Strength,
+		"luck":         body.Luck,
+		"charisma":     body.Charisma,
+		"agility":      body.Agility,
+		"perception":   body.Perception,
+		"intelligence": body.Intelligence,
+	} {
+		if value != nil {
+			scores[name] = *value
+		}
+	}
+	var reg *AbilityRegistry
+	if len(body.Custom) > 0 {
+		reg = NewAbilityRegistry()
+		for _, custom := range body.Custom {
+			def := AbilityDefinition{Name: custom.Name, Description: custom.Description, Default: custom.Default}
+			if err := reg.Register(def); err != nil {
+				return Abilities{}, fmt.Errorf("abilities yaml: %w", err)
+			}
+			scores[custom.Name] = custom.Value
+		}
+	}
+
+	base := Abilities{pointsPool: body.PointsPool, granted: body.GrantedPoints, bonus: body.BonusPoints,
+		registry: reg, costTable: body.CostTable, drained: body.Drained}
+	return restoreAbilities(base, body.Limits, scores)
+}
@@ -0,0 +1,68 @@
+package abilities
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestYAMLRoundTrip(t *testing.T) {
+	fixtures := textFixtures(t)
+
+	reg := NewAbilityRegistry()
+	if err := reg.Register(AbilityDefinition{Name: "sanity", Description: "Keeps the mind whole", Default: 5}); err != nil {
+		t.Fatal(err)
+	}
+	custom, err := NewAbilitiesWithCostTable(CurvedCostTable, reg, map[string]int{
+		"strength": 8, "luck": 5, "charisma": 5, "agility": 5, "perception": 5, "intelligence": 5, "sanity": 7,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := custom.ApplyFreeBonus("intelligence", -1); err != nil {
+		t.Fatal(err)
+	}
+	if err := custom.SetLimits(map[string]Limit{"sanity": {Min: 2, Max: 9}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := custom.Drain("sanity", 3); err != nil {
+		t.Fatal(err)
+	}
+	fixtures["custom, cost table and bonus"] = custom
+
+	for name, original := range fixtures {
+		t.Run(name, func(t *testing.T) {
+			doc, err := original.ToYAML()
+			if err != nil {
+				t.Fatal(err)
+			}
+			decoded, err := AbilitiesFromYAML(doc)
+			if err != nil {
+				t.Fatalf("parse:\n%s\n%v", doc, err)
+			}
+			want, _ := original.MarshalJSON()
+			got, _ := decoded.MarshalJSON()
+			if string(got) != string(want) {
+				t.Errorf("round trip of\n%s\ngave %s, want %s", doc, got, want)
+			}
+		})
+	}
+}
+
+func TestAbilitiesFromYAMLValidatesCostTable(t *testing.T) {
+	spent, err := NewAbilities(9, 5, 5, 5, 5, 6)
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc, err := spent.ToYAML()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Strength 9 costs 5 points under the curved table, not 4
+	curved := strings.Replace(string(doc), "  intelligence: 6\n", "  intelligence: 6\n  costTable:\n    9: 2\n    10: 3\n", 1)
+	if curved == string(doc) {
+		t.Fatalf("document has no intelligence line:\n%s", doc)
+	}
+	if _, err := AbilitiesFromYAML([]byte(curved)); err == nil {
+		t.Errorf("accepted scores over budget for the cost table:\n%s", curved)
+	}
+}
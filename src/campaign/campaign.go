@@ -0,0 +1,173 @@
+package campaign
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"sync"
+	"time"
+
+	"dnd-helper/src/inventory"
+	"dnd-helper/src/rules"
+)
+
+var (
+	// ErrNotFound is returned when no campaign has the requested name
+	ErrNotFound = errors.New("campaign not found")
+	// ErrExists is returned when creating a campaign whose name is taken
+	ErrExists = errors.New("campaign already exists")
+)
+
+// DefaultName is the campaign of requests that do not name one. It always
+// exists and uses rules.DefaultRuleset
+const DefaultName = "default"
+
+// MaxLogEntries is how many encounter log entries a campaign keeps, the
+// oldest are dropped first
+const MaxLogEntries = 1000
+
+// Kinds of encounter log entries
+const (
+	EntryAttack = "attack"
+	EntryCheck  = "check"
+)
+
+// Entry is a roll recorded in the encounter log, with the crit range that
+// applied and the fumble outcome when one was rolled
+type Entry struct {
+	Time      time.Time            `json:"time"`
+	Kind      string               `json:"kind"`
+	Actor     string               `json:"actor"`
+	Target    string               `json:"target,omitempty"`
+	Natural   int                  `json:"natural"`
+	Success   bool                 `json:"success"`
+	Critical  bool                 `json:"critical"`
+	CritRange rules.CritRange      `json:"critRange"`
+	Fumble    *rules.FumbleOutcome `json:"fumble,omitempty"`
+	// Effect describes what the fumble changed, e.g. the dropped weapon
+	Effect string `json:"effect,omitempty"`
+}
+
+// Campaign holds the crit and fumble rules of a table, the loot pile that
+// dropped items end up in and the encounter log
+type Campaign struct {
+	Name    string              `json:"name"`
+	Ruleset *rules.Ruleset      `json:"ruleset"`
+	Loot    inventory.Inventory `json:"loot"`
+	Log     []Entry             `json:"log"`
+}
+
+// New creates a campaign with an empty loot pile. A nil ruleset means
+// rules.DefaultRuleset
+func New(name string, ruleset *rules.Ruleset) (*Campaign, error) {
+	if name == "" {
+		return nil, fmt.Errorf("campaign name cannot be empty")
+	}
+	if ruleset == nil {
+		ruleset = rules.DefaultRuleset()
+	}
+	return &Campaign{Name: name, Ruleset: ruleset, Loot: *inventory.NewInventory(), Log: []Entry{}}, nil
+}
+
+// clone returns a copy of the campaign that shares no storage with the
+// original
+func (c *Campaign) clone() *Campaign {
+	return &Campaign{
+		Name:    c.Name,
+		Ruleset: c.Ruleset.Clone(),
+		Loot:    *c.Loot.Clone(),
+		Log:     append([]Entry{}, c.Log...),
+	}
+}
+
+// Registry holds the campaigns, starting with DefaultName. It is safe for
+// concurrent use and hands out copies
+type Registry struct {
+	mu        sync.RWMutex
+	campaigns map[string]*Campaign
+}
+
+// NewRegistry creates a registry holding only the default campaign
+func NewRegistry() *Registry {
+	// DefaultName is a valid name, so New cannot fail
+	def, _ := New(DefaultName, nil)
+	return &Registry{campaigns: map[string]*Campaign{DefaultName: def}}
+}
+
+// Create registers a new campaign, see New
+func (r *Registry) Create(name string, ruleset *rules.Ruleset) (*Campaign, error) {
+	c, err := New(name, ruleset)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.campaigns[name]; ok {
+		return nil, fmt.Errorf("%w: %s", ErrExists, name)
+	}
+	r.campaigns[name] = c
+	slog.Info("Campaign created", "campaign", name)
+	return c.clone(), nil
+}
+
+// Get returns a copy of the campaign with the given name, "" meaning
+// DefaultName
+func (r *Registry) Get(name string) (*Campaign, error) {
+	if name == "" {
+		name = DefaultName
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	c, ok := r.campaigns[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, name)
+	}
+	return c.clone(), nil
+}
+
+// List returns copies of every campaign sorted by name
+func (r *Registry) List() []*Campaign {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	list := make([]*Campaign, 0, len(r.campaigns))
+	for _, c := range r.campaigns {
+		list = append(list, c.clone())
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list
+}
+
+// SetLoot replaces the loot pile of a campaign, e.g. after a dropped weapon
+// was moved into a copy of it
+func (r *Registry) SetLoot(name string, loot inventory.Inventory) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	c, ok := r.campaigns[name]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrNotFound, name)
+	}
+	c.Loot = *loot.Clone()
+	return nil
+}
+
+// Record appends an entry to the encounter log of a campaign, stamping it
+// with the current time if it has none
+func (r *Registry) Record(name string, entry Entry) error {
+	if entry.Time.IsZero() {
+		entry.Time = time.Now().UTC()
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	c, ok := r.campaigns[name]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrNotFound, name)
+	}
+	c.Log = append(c.Log, entry)
+	if len(c.Log) > MaxLogEntries {
+		c.Log = append([]Entry{}, c.Log[len(c.Log)-MaxLogEntries:]...)
+	}
+	slog.Debug("Encounter logged", "campaign", name, "kind", entry.Kind, "actor", entry.Actor)
+	return nil
+}
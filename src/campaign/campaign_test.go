@@ -0,0 +1,72 @@
+package campaign
+
+import (
+	"errors"
+	"testing"
+
+	"dnd-helper/src/inventory"
+	"dnd-helper/src/rules"
+)
+
+func TestRegistryHasDefaultCampaign(t *testing.T) {
+	r := NewRegistry()
+	c, err := r.Get("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Name != DefaultName || len(c.Ruleset.FumbleTable) != len(rules.DefaultRuleset().FumbleTable) {
+		t.Errorf("campaign %s with %d fumble outcomes, want the default one", c.Name, len(c.Ruleset.FumbleTable))
+	}
+	if _, err := r.Create(DefaultName, nil); !errors.Is(err, ErrExists) {
+		t.Errorf("creating the default campaign again: error %v, want ErrExists", err)
+	}
+	if _, err := r.Get("missing"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("unknown campaign: error %v, want ErrNotFound", err)
+	}
+}
+
+func TestGetReturnsCopies(t *testing.T) {
+	r := NewRegistry()
+	ruleset, err := rules.NewRuleset(nil, []rules.FumbleOutcome{{Name: rules.FumbleDropWeapon, Weight: 1}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.Create("Tomb", ruleset); err != nil {
+		t.Fatal(err)
+	}
+
+	c, _ := r.Get("Tomb")
+	c.Ruleset.FumbleTable[0].Name = "changed"
+	item, err := inventory.NewItem("Dagger", 1, nil, "Pristine", "", inventory.DefaultItemRarity, 0, 0, inventory.SlotMainHand)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.Loot.AddItem(item)
+
+	again, _ := r.Get("Tomb")
+	if again.Ruleset.FumbleTable[0].Name != rules.FumbleDropWeapon || again.Loot.GetItem("Dagger") != nil {
+		t.Error("changing a copy changed the registered campaign")
+	}
+	if err := r.SetLoot("Tomb", c.Loot); err != nil {
+		t.Fatal(err)
+	}
+	if again, _ = r.Get("Tomb"); again.Loot.GetItem("Dagger") == nil {
+		t.Error("SetLoot did not store the loot")
+	}
+}
+
+func TestRecordKeepsNewestEntries(t *testing.T) {
+	r := NewRegistry()
+	for i := range MaxLogEntries + 5 {
+		if err := r.Record(DefaultName, Entry{Kind: EntryCheck, Natural: i}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	c, _ := r.Get(DefaultName)
+	if len(c.Log) != MaxLogEntries || c.Log[0].Natural != 5 || c.Log[0].Time.IsZero() {
+		t.Errorf("%d entries starting at %d, want %d starting at 5 with a time", len(c.Log), c.Log[0].Natural, MaxLogEntries)
+	}
+	if err := r.Record("missing", Entry{}); !errors.Is(err, ErrNotFound) {
+		t.Errorf("recording to an unknown campaign: error %v, want ErrNotFound", err)
+	}
+}
@@ -0,0 +1,22 @@
+package character
+
+// ArmorBonus sums the armor class bonuses of all equipped items
+func (c *Character) ArmorBonus() int {
+	bonus := 0
+	for _, itemName := range c.equipment {
+		if item := c.inventory.GetItem(itemName); item != nil {
+			bonus += item.GetArmorBonus()
+		}
+	}
+	return bonus
+}
+
+// ArmorClass is the attack total needed to hit the character: BaseDefense
+// plus the effective agility modifier plus the armor bonuses of equipped
+// items. Drained agility lowers it and unequipping armor drops it at once,
+// since it is derived on every call
+func (c *Character) ArmorClass() int {
+	effective := c.GetEffectiveAbilities()
+	agilityMod, _ := effective.Modifier("agility")
+	return BaseDefense + agilityMod + c.ArmorBonus()
+}
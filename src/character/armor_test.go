@@ -0,0 +1,80 @@
+package character
+
+import (
+	"dnd-helper/src/abilities"
+	"dnd-helper/src/condition"
+	"dnd-helper/src/inventory"
+	"testing"
+)
+
+// armorPiece returns an item granting bonus armor class in slot
+func armorPiece(t *testing.T, name string, bonus int, slot string) inventory.Item {
+	t.Helper()
+	item, err := inventory.NewItem(name, 1, nil, condition.NewCondition("Pristine"), "", inventory.RarityCommon, 0, bonus, slot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return item
+}
+
+func TestArmorClassMatrix(t *testing.T) {
+	armor := []struct {
+		name  string
+		items []inventory.Item
+		bonus int
+	}{
+		{"no armor", nil, 0},
+		{"chest", []inventory.Item{armorPiece(t, "Leather", 2, inventory.SlotChest)}, 2},
+		{"chest and shield", []inventory.Item{
+			armorPiece(t, "Leather", 2, inventory.SlotChest),
+			armorPiece(t, "Buckler", 1, inventory.SlotOffHand),
+		}, 3},
+		{"max bonus", []inventory.Item{armorPiece(t, "Plate", inventory.MaxArmorBonus, inventory.SlotChest)}, inventory.MaxArmorBonus},
+	}
+	// drain lowers agility, 100 drains it far past the minimum
+	for _, drain := range []int{0, 2, 100} {
+		for _, a := range armor {
+			c := NewDefaultCharacter("human", "Ann", "mage")
+			for _, item := range a.items {
+				if err := c.AddItem(item); err != nil {
+					t.Fatal(err)
+				}
+				if err := c.Equip(item.Name); err != nil {
+					t.Fatal(err)
+				}
+			}
+			agility := c.abilities.GetAllAbilities()["agility"]
+			if drain > 0 {
+				if err := c.AbilitiesRef().Drain("agility", drain); err != nil {
+					t.Fatal(err)
+				}
+			}
+			current := max(agility-drain, abilities.MinAbilityValue)
+			want := BaseDefense + current - abilities.DefaultAbilityValue + a.bonus
+			if got := c.ArmorClass(); got != want {
+				t.Errorf("agility %d drained by %d, %s: armor class %d, want %d", agility, drain, a.name, got, want)
+			}
+		}
+	}
+}
+
+func TestUnequipArmorDropsArmorClass(t *testing.T) {
+	c := NewDefaultCharacter("human", "Ann", "mage")
+	before := c.ArmorClass()
+	plate := armorPiece(t, "Plate", 4, inventory.SlotChest)
+	if err := c.AddItem(plate); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Equip("Plate"); err != nil {
+		t.Fatal(err)
+	}
+	if got := c.ArmorClass(); got != before+4 {
+		t.Errorf("armor class %d with plate, want %d", got, before+4)
+	}
+	if err := c.Unequip("Plate"); err != nil {
+		t.Fatal(err)
+	}
+	if got := c.ArmorClass(); got != before {
+		t.Errorf("armor class %d after unequipping, want %d", got, before)
+	}
+}
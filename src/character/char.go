@@ -1,49 +1,152 @@
 package character
 
 import (
+	"crypto/sha256"
 	"dnd-helper/src/abilities"
 	"dnd-helper/src/condition"
 	"dnd-helper/src/inventory"
+	"dnd-helper/src/uuid"
+	"dnd-helper/src/validation"
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
-	"log"
+	"log/slog"
+	"unicode"
+	"unicode/utf8"
 )
 
 type Character struct {
+	id         string
 	race       string
 	name       string
 	class      string
 	abilities  abilities.Abilities
 	inventory  inventory.Inventory
-	condition  condition.Condition
+	conditions condition.Conditions
 	manaPoints int
+	level      int
+	experience int
+	manaBonus  int               // max mana gained from level ups
+	equipment  map[string]string // slot -> name of the equipped item
+	raceMods   map[string]int    // racial modifiers applied at creation
+	spellbook  []string          // names of the known spells, sorted
+	buffs      map[string]int    // ability modifiers from spells, until the next rest
+	effects    []Effect          // active status effects in the order applied
+	initiative int               // initiative of the current encounter
+	isNPC      bool              // generated by NewRandomNPC
+	alignment  string            // one of Alignments, empty if not chosen
+	biography  string            // free text, at most MaxBiographyBytes
+	tags       []string          // lowercase and unique, at most MaxTags
+	companions []*Character      // owned companions, never in the roster
+	owned      bool              // a companion owned by another character
+	shareLoad  bool              // companion inventories count against the carry capacity
+	history    []HistoryEntry    // recent changes, oldest first
+	actor      string            // who the next changes are attributed to
+	multiclass []ClassLevel      // classes after the primary one, in the order taken
+	relations  []Relationship    // edges to other roster characters by ID
+	hitPoints  int               // at most GetMaxHitPoints
 }
 
+// NewCharacter creates a character that owns a deep copy of inv. The limits
+// and modifiers of a registered race are applied to abs; callers should
+// check the limits with ApplyRaceLimits beforehand
 func NewCharacter(race string, name string, class string, abs abilities.Abilities, inv inventory.Inventory, cond condition.Condition) *Character {
-	log.Printf("Creating new character %s %s with class %s, \nabilities %v, \ninventory %v \nand in %v condition", race, name, class, abs.String(), inv.String(), cond)
-	return &Character{
+	if err := ApplyRaceLimits(&abs, race); err != nil {
+		slog.Warn("Character created without racial limits", "character", name, "race", race, "error", err)
+	}
+	applied, err := applyRaceBonus(&abs, race)
+	if err != nil {
+		slog.Warn("Character created without racial modifiers", "character", name, "race", race, "error", err)
+	}
+	c := newCharacter(race, name, class, abs, inv, cond)
+	c.raceMods = applied
+	slog.Info("Character created", "character", name, "id", c.id, "race", race, "class", class, "items", len(inv.GetAllItems()), "condition", cond)
+	return c
+}
+
+// newCharacter is NewCharacter without the racial presets, for abilities
+// that already carry them
+func newCharacter(race string, name string, class string, abs abilities.Abilities, inv inventory.Inventory, cond condition.Condition) *Character {
+	c := &Character{
+		id:         uuid.New(),
 		race:       race,
 		name:       name,
 		class:      class,
 		abilities:  abs,
-		inventory:  inv,
-		condition:  cond,
-		manaPoints: abs.GetIntelligence() * 50,
+		inventory:  *inv.Clone(),
+		conditions: condition.NewConditions(cond),
+		level:      StartingLevel,
 	}
+	c.hitPoints = c.GetMaxHitPoints()
+	c.manaPoints = c.GetMaxManaPoints()
+	return c
 }
 
+// NewDefaultCharacter creates a Healthy character with the class preset
+// abilities (or the flat default spread) and the class starting kit and gold
 func NewDefaultCharacter(race string, name string, class string) *Character {
-	defaultAbilities := abilities.NewDefaultAbilities()
+	defaultAbilities, ok := abilities.PresetFor(class)
+	if !ok {
+		defaultAbilities = abilities.NewDefaultAbilities()
+	}
+	kit, gold := ClassKit(class)
 	defaultInventory := inventory.NewInventory()
-	defaultCondition := condition.NewCondition("Healthy")
-	return &Character{
-		race:       race,
-		name:       name,
-		class:      class,
-		abilities:  defaultAbilities,
-		inventory:  *defaultInventory,
-		condition:  defaultCondition,
-		manaPoints: defaultAbilities.GetIntelligence() * 50,
+	for _, item := range kit {
+		defaultInventory.AddItem(item)
 	}
+	c := NewCharacter(race, name, class, defaultAbilities, *defaultInventory, condition.NewCondition("Healthy"))
+	c.inventory.AddGold(gold)
+	return c
+}
+
+// Clone returns a deep copy of the character, ID included, that shares no
+// state with the original
+func (c *Character) Clone() *Character {
+	clone := *c
+	clone.abilities = c.abilities.Clone()
+	clone.inventory = *c.inventory.Clone()
+	clone.conditions = c.conditions.Clone()
+	clone.equipment = c.GetEquipment()
+	if c.raceMods != nil {
+		clone.raceMods = c.GetRaceModifiers()
+	}
+	clone.spellbook = c.GetSpellbook()
+	if c.buffs != nil {
+		clone.buffs = c.GetBuffs()
+	}
+	if c.effects != nil {
+		clone.effects = c.GetEffects()
+	}
+	clone.tags = c.GetTags()
+	if c.companions != nil {
+		clone.companions = c.Companions()
+	}
+	clone.relations = c.Relationships()
+	if c.multiclass != nil {
+		clone.multiclass = append([]ClassLevel{}, c.multiclass...)
+	}
+	clone.history = c.GetHistory(0)
+	clone.actor = ""
+	return &clone
+}
+
+// CloneAs returns a deep copy of the character under a new ID and name,
+// e.g. for mirror images or to fork a build. The copy shares no state with
+// the original
+func (c *Character) CloneAs(newName string) (*Character, error) {
+	if err := ValidateName(newName); err != nil {
+		return nil, err
+	}
+	clone := c.Clone()
+	clone.id = uuid.New()
+	clone.name = newName
+	slog.Info("Character cloned", "character", c.name, "clone", newName, "id", clone.id)
+	return clone, nil
+}
+
+// GetID returns the unique ID assigned at creation
+func (c *Character) GetID() string {
+	return c.id
 }
 
 func (c *Character) GetName() string {
@@ -59,64 +162,471 @@ func (c *Character) GetClass() string {
 }
 
 func (c *Character) GetAbilities() abilities.Abilities {
-	return c.abilities
+	return c.abilities.Clone()
 }
 
+// GetInventory returns a snapshot of the character's inventory. It is a deep
+// copy, so changes to it (including reordering its items) never reach the
+// character; use Inventory or the character's item methods to change it
 func (c *Character) GetInventory() inventory.Inventory {
-	return c.inventory
+	return *c.inventory.Clone()
 }
 
+// Inventory returns the character's own inventory, so changes made through
+// it are seen by later reads. Items added through it skip the carry capacity
+// check of AddItem, and removed items stay equipped until RemoveItem or
+// SellItem is called; prefer those methods when they fit
+func (c *Character) Inventory() *inventory.Inventory {
+	return &c.inventory
+}
+
+// RemoveItem removes quantity items named name and unequips any item that
+// is no longer carried
+func (c *Character) RemoveItem(name string, quantity int) error {
+	before := c.itemQuantity(name)
+	if err := c.inventory.RemoveItem(name, quantity); err != nil {
+		return fmt.Errorf("cannot remove %s: %w", name, err)
+	}
+	c.recordItem(name, before)
+	c.dropMissingEquipment()
+	return nil
+}
+
+// GetCondition returns all active conditions joined into a single condition
 func (c *Character) GetCondition() condition.Condition {
-	return c.condition
+	return condition.NewCondition(c.conditions.String())
+}
+
+// GetConditions returns a copy of the active conditions
+func (c *Character) GetConditions() condition.Conditions {
+	return c.conditions.Clone()
 }
 
+// GetManaPoints returns the current mana
 func (c *Character) GetManaPoints() int {
 	return c.manaPoints
 }
 
-func (c *Character) SetName(newName string) {
-	if newName != "" {
-		c.name = newName
-		log.Printf("Name changed to: %s", newName)
-	} else {
-		log.Println("Name not changed, new name is empty")
+// GetGold returns the gold in the character's inventory
+func (c *Character) GetGold() int {
+	return c.inventory.GetGold()
+}
+
+// AddGold adds n gold to the character's inventory
+func (c *Character) AddGold(n int) error {
+	if err := c.inventory.AddGold(n); err != nil {
+		return err
+	}
+	slog.Debug("Gold received", "character", c.name, "amount", n, "gold", c.inventory.GetGold())
+	return nil
+}
+
+// SpendGold removes n gold from the character's inventory. Gold can never
+// go below zero, so spending more than the character has fails with an
+// *inventory.InsufficientFundsError
+func (c *Character) SpendGold(n int) error {
+	if err := c.inventory.SpendGold(n); err != nil {
+		return err
+	}
+	slog.Debug("Gold spent", "character", c.name, "amount", n, "gold", c.inventory.GetGold())
+	return nil
+}
+
+// BuyItem pays price gold and adds item to the inventory. Nothing changes
+// unless both steps can succeed
+func (c *Character) BuyItem(item inventory.Item, price int) error {
+	if price < 0 {
+		return fmt.Errorf("price %d cannot be negative", price)
+	}
+	if gold := c.inventory.GetGold(); gold < price {
+		return fmt.Errorf("cannot buy %s: %w", item.GetName(), &inventory.InsufficientFundsError{Have: gold, Need: price})
+	}
+	if err := c.AddItem(item); err != nil {
+		return err
+	}
+	c.inventory.SpendGold(price)
+	slog.Info("Item bought", "character", c.name, "item", item.GetName(), "quantity", item.GetQuantity(), "price", price, "gold", c.inventory.GetGold())
+	return nil
+}
+
+// SellItem removes quantity items named name and credits quantity*unitPrice
+// gold. If the items cannot be removed the gold is left unchanged
+func (c *Character) SellItem(name string, quantity int, unitPrice int) error {
+	if quantity <= 0 {
+		return fmt.Errorf("quantity %d must be positive", quantity)
+	}
+	if unitPrice < 0 {
+		return fmt.Errorf("unit price %d cannot be negative", unitPrice)
+	}
+	before := c.itemQuantity(name)
+	if err := c.inventory.RemoveItem(name, quantity); err != nil {
+		return fmt.Errorf("cannot sell %s: %w", name, err)
+	}
+	c.recordItem(name, before)
+	c.dropMissingEquipment()
+	c.inventory.AddGold(quantity * unitPrice)
+	slog.Info("Item sold", "character", c.name, "item", name, "quantity", quantity, "price", quantity*unitPrice, "gold", c.inventory.GetGold())
+	return nil
+}
+
+// GiveItem moves quantity units of name to other through
+// inventory.Transfer, e.g. between party members. The units keep their
+// condition and the rest of a partly given stack stays behind. The quantity
+// and other's carry capacity are checked first, so a failed give changes
+// neither character
+func (c *Character) GiveItem(other *Character, name string, quantity int) error {
+	if other.id == c.id {
+		return fmt.Errorf("%s cannot give items to themselves", c.name)
+	}
+	weight, err := c.inventory.UnitsWeight(name, quantity)
+	if err != nil {
+		return fmt.Errorf("cannot give %s: %w", name, err)
+	}
+	if err := other.checkCapacity(name, weight); err != nil {
+		return fmt.Errorf("cannot give %s: %w", name, err)
+	}
+
+	before, otherBefore := c.itemQuantity(name), other.itemQuantity(name)
+	if err := inventory.Transfer(&c.inventory, &other.inventory, name, quantity); err != nil {
+		return fmt.Errorf("cannot give %s: %w", name, err)
+	}
+	c.recordItem(name, before)
+	other.recordItem(name, otherBefore)
+	c.dropMissingEquipment()
+	slog.Info("Item given", "character", c.name, "item", name, "quantity", quantity, "to", other.name)
+	return nil
+}
+
+// MaxNameLength is the longest name a character can have, in runes
+const MaxNameLength = 64
+
+// ValidateName checks a name is non-empty, at most MaxNameLength runes and
+// free of control characters, since names end up in JSON and logs
+func ValidateName(name string) error {
+	if name == "" {
+		return fmt.Errorf("name cannot be empty")
+	}
+	if n := utf8.RuneCountInString(name); n > MaxNameLength {
+		return fmt.Errorf("name is %d characters long, the maximum is %d", n, MaxNameLength)
+	}
+	for _, r := range name {
+		if unicode.IsControl(r) {
+			return fmt.Errorf("name cannot contain control characters")
+		}
+	}
+	return nil
+}
+
+// SetName renames the character. Invalid names are rejected and the old
+// name is kept
+func (c *Character) SetName(newName string) error {
+	if err := ValidateName(newName); err != nil {
+		slog.Debug("Name not changed", "character", c.name, "error", err)
+		return err
+	}
+	c.record("name", c.name, newName)
+	c.name = newName
+	slog.Debug("Name changed", "character", newName)
+	return nil
+}
+
+// SetClass changes the class and the mana scaling that comes with it
+func (c *Character) SetClass(newClass string) error {
+	if newClass == "" {
+		slog.Debug("Class not changed, new class is empty", "character", c.name)
+		return fmt.Errorf("class cannot be empty")
+	}
+	if c.multiclassIndex(newClass) >= 0 {
+		return fmt.Errorf("%s already has levels in %s", c.name, newClass)
+	}
+	c.record("class", c.class, newClass)
+	c.class = newClass
+	c.RecalculateDerivedStats()
+	slog.Debug("Class changed", "character", c.name, "class", newClass)
+	return nil
+}
+
+// SetRace changes the race. The modifiers of the old race are taken back and
+// the limits and modifiers of the new one applied. If the abilities break a
+// limit of the new race nothing changes
+func (c *Character) SetRace(newRace string) error {
+	if newRace == "" {
+		slog.Debug("Race not changed, new race is empty", "character", c.name)
+		return fmt.Errorf("race cannot be empty")
+	}
+
+	abs := c.abilities.Clone()
+	for name, delta := range c.raceMods {
+		if _, err := abs.ApplyFreeBonus(name, -delta); err != nil {
+			return fmt.Errorf("cannot remove %s modifiers: %w", c.race, err)
+		}
+	}
+	limits := LimitsForRace(newRace)
+	if limits == nil {
+		limits = map[string]abilities.Limit{}
+	}
+	if err := abs.SetLimits(limits); err != nil {
+		return fmt.Errorf("racial limit for %s: %w", newRace, err)
+	}
+	applied, err := applyRaceBonus(&abs, newRace)
+	if err != nil {
+		return err
+	}
+
+	before := c.abilities.Clone()
+	c.record("race", c.race, newRace)
+	c.race = newRace
+	c.abilities = abs
+	c.raceMods = applied
+	c.recordAbilities(before)
+	c.RecalculateDerivedStats()
+	slog.Debug("Race changed", "character", c.name, "race", newRace)
+	return nil
+}
+
+// SetCondition replaces all active conditions with newCondition, which must
+// be registered in condition.DefaultRegistry and reachable from every active
+// condition, see condition.Registry.CanTransition
+func (c *Character) SetCondition(newCondition condition.Condition) error {
+	if newCondition.String() == "" {
+		slog.Debug("Condition not changed, new condition is empty", "character", c.name)
+		return fmt.Errorf("condition cannot be empty")
+	}
+	if _, ok := condition.DefaultRegistry.Lookup(newCondition.String()); !ok {
+		slog.Debug("Condition not changed, unknown condition", "character", c.name, "condition", newCondition.String())
+		return fmt.Errorf("unknown condition %s", newCondition.String())
+	}
+	for _, active := range c.conditions.List() {
+		if !condition.DefaultRegistry.CanTransition(active, newCondition) {
+			slog.Debug("Condition not changed, transition not allowed", "character", c.name, "from", active.String(), "to", newCondition.String())
+			return fmt.Errorf("condition %s cannot change to %s", active.String(), newCondition.String())
+		}
+	}
+	before := c.conditions.String()
+	c.conditions = condition.NewConditions(newCondition)
+	c.record("conditions", before, c.conditions.String())
+	c.RecalculateDerivedStats()
+	slog.Debug("Condition changed", "character", c.name, "condition", newCondition.String())
+	return nil
+}
+
+// AddCondition adds an indefinite condition alongside the active ones.
+// Adding an active condition again follows its stacking policy
+func (c *Character) AddCondition(newCondition condition.Condition) {
+	if newCondition.String() == "" {
+		slog.Debug("Condition not added, new condition is empty", "character", c.name)
+		return
+	}
+	before := c.conditions.String()
+	c.conditions.Apply(newCondition, 0, condition.DefaultRegistry.Stacking(newCondition))
+	c.record("conditions", before, c.conditions.String())
+	c.RecalculateDerivedStats()
+	slog.Debug("Condition added", "character", c.name, "conditions", c.conditions.String())
+}
+
+// AddTimedCondition adds a condition that expires after the given number of
+// turns. A duration of 0 means the condition lasts indefinitely. Adding an
+// active condition again follows its stacking policy
+func (c *Character) AddTimedCondition(newCondition condition.Condition, turns int) {
+	if newCondition.String() == "" {
+		slog.Debug("Condition not added, new condition is empty", "character", c.name)
+		return
 	}
+	before := c.conditions.String()
+	c.conditions.Apply(newCondition, turns, condition.DefaultRegistry.Stacking(newCondition))
+	c.record("conditions", before, c.conditions.String())
+	c.RecalculateDerivedStats()
+	slog.Debug("Timed condition added", "character", c.name, "condition", newCondition.String(), "turns", turns, "conditions", c.conditions.String())
 }
 
-func (c *Character) SetClass(newClass string) {
-	if newClass != "" {
-		c.class = newClass
-		log.Printf("Class changed to: %s", newClass)
-	} else {
-		log.Println("Class not changed, new class is empty")
+// TickConditions advances every timed condition by one turn and removes the
+// ones that expire. Indefinite conditions are left untouched. Conditions
+// with damage per turn, e.g. Poisoned, deal it once per stack first
+func (c *Character) TickConditions() []condition.Condition {
+	damage := 0
+	for _, cond := range c.conditions.List() {
+		damage += condition.DefaultRegistry.DamagePerTurn(cond) * c.conditions.Stacks(cond)
+	}
+	if damage > 0 && !c.IsDead() {
+		c.TakeDamage(damage)
+		slog.Debug("Condition damage taken", "character", c.name, "damage", damage, "hitPoints", c.hitPoints)
+	}
+
+	before := c.conditions.String()
+	expired := c.conditions.Tick()
+	c.record("conditions", before, c.conditions.String())
+	for _, cond := range expired {
+		slog.Debug("Condition expired", "character", c.name, "condition", cond.String())
 	}
+	return expired
+}
 
+// RemoveCondition removes an active condition and reports whether it was active
+func (c *Character) RemoveCondition(cond condition.Condition) bool {
+	before := c.conditions.String()
+	if !c.conditions.Remove(cond) {
+		return false
+	}
+	c.record("conditions", before, c.conditions.String())
+	c.RecalculateDerivedStats()
+	slog.Debug("Condition removed", "character", c.name, "condition", cond.String(), "conditions", c.conditions.String())
+	return true
 }
 
-func (c *Character) SetCondition(newCondition condition.Condition) {
-	if newCondition.String() != "" {
-		c.condition = newCondition
-		log.Printf("Condition changed to: %s", newCondition.String())
-	} else {
-		log.Println("Condition not changed, new condition is empty")
+// SetAbilities replaces the character's abilities after validating them and
+// recomputes the mana derived from intelligence
+func (c *Character) SetAbilities(abs abilities.Abilities) error {
+	if err := abs.ValidateAbilities(); err != nil {
+		return err
 	}
+	before := c.abilities
+	c.abilities = abs.Clone()
+	c.recordAbilities(before)
+	c.manaPoints = c.GetMaxManaPoints()
+	c.RecalculateDerivedStats()
+	slog.Debug("Abilities changed", "character", c.name, "abilities", c.abilities.String())
+	return nil
 }
 
-func (c *Character) SetInventory(newItem inventory.Item) {
+// ModifyAbility changes one of the character's own abilities by delta
+// through the points pool rules, then recomputes the stats derived from it
+func (c *Character) ModifyAbility(name string, delta int) error {
+	before := c.abilities.Clone()
+	if err := c.abilities.AddToAbility(name, delta); err != nil {
+		return err
+	}
+	c.recordAbilities(before)
+	c.RecalculateDerivedStats()
+	slog.Debug("Ability modified", "character", c.name, "ability", name, "delta", delta, "abilities", c.abilities.String())
+	return nil
+}
 
-	c.inventory.AddItem(newItem)
+// AbilitiesRef returns the character's own abilities for callers that need
+// the full Abilities API. Changes through it are not validated and do not
+// update derived stats, so call RecalculateDerivedStats afterwards. Prefer
+// ModifyAbility and SetAbilities
+func (c *Character) AbilitiesRef() *abilities.Abilities {
+	return &c.abilities
 }
 
+// RecalculateDerivedStats clamps the current hit points and mana to their
+// maximums after a change that may have lowered them, e.g. a drained ability,
+// an unequipped item or a new condition. Like the maximums it follows the
+// effective abilities, see GetMaxHitPoints
+func (c *Character) RecalculateDerivedStats() {
+	c.hitPoints = min(c.hitPoints, c.GetMaxHitPoints())
+	c.RecalculateManaPoints()
+}
+
+// SetInventory adds an item to the inventory, see AddItem
+func (c *Character) SetInventory(newItem inventory.Item) error {
+	return c.AddItem(newItem)
+}
+
+// ValidateCharacter checks the name, race, class and abilities and returns
+// all problems at once as validation.ValidationErrors
 func (c *Character) ValidateCharacter() error {
-	log.Printf("Validating character: %s", c.name)
-	if c.name == "" || c.race == "" || c.class == "" {
-		errMsg := "Character validation failed: name, race, or class cannot be empty"
-		log.Println(errMsg)
-		return fmt.Errorf(errMsg, nil)
-	}
-	if err := c.abilities.ValidateAbilities(); err != nil {
-		log.Printf("Character validation failed: %v", err)
+	var errs validation.ValidationErrors
+	if err := ValidateName(c.name); err != nil {
+		errs.Add("name", "%v", err)
+	}
+	if c.race == "" {
+		errs.Add("race", "race cannot be empty")
+	}
+	if c.class == "" {
+		errs.Add("class", "class cannot be empty")
+	}
+	errs.Merge("abilities", c.abilities.ValidateAbilities())
+	if err := errs.Err(); err != nil {
+		slog.Warn("Character validation failed", "character", c.name, "error", err)
 		return err
 	}
 	return nil
 }
+
+// canonicalCharacter is the stable serialization the content hash is computed
+// from. Field order is fixed by the struct and map keys are sorted by
+// encoding/json, so equal characters always produce equal bytes. Volatile
+// fields such as timestamps and identities such as the character and item
+// IDs must not be added here
+type canonicalCharacter struct {
+	Race       string              `json:"race"`
+	Name       string              `json:"name"`
+	Class      string              `json:"class"`
+	Abilities  abilities.Abilities `json:"abilities"`
+	Inventory  []json.RawMessage   `json:"inventory"`
+	Condition  string              `json:"condition"`
+	Conditions []conditionJSON     `json:"conditions"`
+	ManaPoints int                 `json:"manaPoints"`
+	Level      int                 `json:"level"`
+	Experience int                 `json:"experience"`
+	Gold       int                 `json:"gold"`
+	Equipment  map[string]string   `json:"equipment"`
+	RaceMods   map[string]int      `json:"raceModifiers"`
+	Spellbook  []string            `json:"spellbook"`
+	Buffs      map[string]int      `json:"buffs"`
+	Effects    []Effect            `json:"effects"`
+	HitPoints  int                 `json:"hitPoints"`
+	MaxHP      int                 `json:"maxHitPoints"`
+	Alignment  string              `json:"alignment"`
+	Biography  string              `json:"biography"`
+	Tags       []string            `json:"tags"`
+	Companions []string            `json:"companions"`
+	ShareLoad  bool                `json:"countCompanionWeight"`
+	Classes    []ClassLevel        `json:"classes"`
+	Relations  []Relationship      `json:"relationships"`
+	Initiative int                 `json:"initiative"`
+	IsNPC      bool                `json:"isNPC"`
+}
+
+// Hash returns the SHA-256 of the character's canonical serialization as a
+// hex string. It is computed from the current state, so it changes with
+// every mutation and can be used as an ETag for sync and conflict detection
+func (c *Character) Hash() string {
+	doc := canonicalCharacter{
+		Race:       c.race,
+		Name:       c.name,
+		Class:      c.class,
+		Abilities:  c.abilities,
+		Inventory:  []json.RawMessage{},
+		Condition:  c.conditions.String(),
+		Conditions: c.conditionDocs(),
+		ManaPoints: c.manaPoints,
+		Level:      c.level,
+		Experience: c.experience,
+		Gold:       c.inventory.GetGold(),
+		Equipment:  c.GetEquipment(),
+		RaceMods:   c.GetRaceModifiers(),
+		Spellbook:  c.GetSpellbook(),
+		Buffs:      c.GetBuffs(),
+		Effects:    c.GetEffects(),
+		HitPoints:  c.hitPoints,
+		MaxHP:      c.GetMaxHitPoints(),
+		Alignment:  c.alignment,
+		Biography:  c.biography,
+		Tags:       c.GetTags(),
+		Companions: []string{},
+		ShareLoad:  c.shareLoad,
+		Classes:    c.Classes(),
+		Relations:  c.Relationships(),
+		Initiative: c.initiative,
+		IsNPC:      c.isNPC,
+	}
+	for _, item := range c.inventory.GetAllItems() {
+		data, err := item.ContentJSON()
+		if err != nil {
+			panic(fmt.Sprintf("character hash: %v", err))
+		}
+		doc.Inventory = append(doc.Inventory, data)
+	}
+	for _, companion := range c.companions {
+		doc.Companions = append(doc.Companions, companion.Hash())
+	}
+	data, err := json.Marshal(doc)
+	if err != nil {
+		// Every field above is plain data, so this cannot happen
+		panic(fmt.Sprintf("character hash: %v", err))
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
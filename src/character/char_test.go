@@ -0,0 +1,192 @@
+package character
+
+import (
+	"dnd-helper/src/condition"
+	"dnd-helper/src/inventory"
+	"encoding/json"
+	"log/slog"
+	"math/rand"
+	"os"
+	"testing"
+)
+
+func TestMain(m *testing.M) {
+	slog.SetDefault(slog.New(slog.DiscardHandler))
+	os.Exit(m.Run())
+}
+
+// roundTrip encodes c and decodes it into a new character
+func roundTrip(t *testing.T, c *Character) *Character {
+	t.Helper()
+	data, err := json.Marshal(c)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	var decoded Character
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("unmarshal: %v\n%s", err, data)
+	}
+	return &decoded
+}
+
+func TestHashIsStable(t *testing.T) {
+	tests := []struct {
+		name string
+		// build makes the same character each time, filling its maps in
+		// reverse order when reversed is set
+		build func(t *testing.T, reversed bool) *Character
+	}{
+		{"default character", func(t *testing.T, reversed bool) *Character {
+			return NewDefaultCharacter("human", "Ann", "warrior")
+		}},
+		{"equipment", func(t *testing.T, reversed bool) *Character {
+			c := NewDefaultCharacter("human", "Ann", "warrior")
+			items := []string{"Longsword", "Chain mail"}
+			if reversed {
+				items = []string{"Chain mail", "Longsword"}
+			}
+			for _, item := range items {
+				if err := c.Equip(item); err != nil {
+					t.Fatal(err)
+				}
+			}
+			return c
+		}},
+		{"effect modifiers", func(t *testing.T, reversed bool) *Character {
+			c := NewDefaultCharacter("human", "Ann", "mage")
+			mods := map[string]int{}
+			names := []string{"strength", "luck", "agility", "perception"}
+			for i := range names {
+				if reversed {
+					i = len(names) - 1 - i
+				}
+				mods[names[i]] = i + 1
+			}
+			if err := c.ApplyEffect(Effect{Name: "Blessed", Duration: 3, AbilityMods: mods}); err != nil {
+				t.Fatal(err)
+			}
+			return c
+		}},
+		{"buffs and race modifiers", func(t *testing.T, reversed bool) *Character {
+			c := NewDefaultCharacter("elf", "Ann", "mage")
+			c.buffs = map[string]int{}
+			names := []string{"strength", "charisma", "intelligence"}
+			for i := range names {
+				if reversed {
+					i = len(names) - 1 - i
+				}
+				c.buffs[names[i]] = i + 1
+			}
+			return c
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			first, second := tt.build(t, false), tt.build(t, true)
+			if first.GetID() == second.GetID() {
+				t.Fatal("both characters have the same ID")
+			}
+			hash := first.Hash()
+			if got := second.Hash(); got != hash {
+				t.Errorf("characters built in a different order hash to %s and %s", hash, got)
+			}
+			if got := first.Hash(); got != hash {
+				t.Errorf("hashing twice gave %s and %s", hash, got)
+			}
+			if got := roundTrip(t, first).Hash(); got != hash {
+				t.Errorf("hash changed from %s to %s after a JSON round trip", hash, got)
+			}
+			if got := roundTrip(t, roundTrip(t, second)).Hash(); got != hash {
+				t.Errorf("hash changed from %s to %s after two JSON round trips", hash, got)
+			}
+		})
+	}
+}
+
+func TestHashChangesWithContent(t *testing.T) {
+	c := NewDefaultCharacter("human", "Ann", "warrior")
+	before := c.Hash()
+	if err := c.TakeDamage(1); err != nil {
+		t.Fatal(err)
+	}
+	if c.Hash() == before {
+		t.Error("hash did not change after taking damage")
+	}
+}
+
+func TestHashCoversConditionState(t *testing.T) {
+	bleeding := condition.NewCondition("Bleeding")
+	err := condition.DefaultRegistry.Register(condition.Definition{Name: bleeding.String(), Severity: 1,
+		Category: condition.CategoryCharacter, Stacking: condition.StackAdd, DamagePerTurn: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name   string
+		change func(c *Character)
+	}{
+		{"turns", func(c *Character) { c.AddTimedCondition(condition.NewCondition("Poisoned"), 3) }},
+		{"stacks", func(c *Character) { c.AddTimedCondition(bleeding, 2) }},
+		{"initiative", func(c *Character) { c.RollInitiative(rand.New(rand.NewSource(1))) }},
+		{"npc", func(c *Character) { c.isNPC = true }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewDefaultCharacter("human", "Ann", "warrior")
+			c.AddTimedCondition(condition.NewCondition("Poisoned"), 2)
+			c.AddTimedCondition(bleeding, 2)
+			before := c.Hash()
+			tt.change(c)
+			if c.Hash() == before {
+				t.Errorf("hash did not change with the %s", tt.name)
+			}
+		})
+	}
+}
+
+func TestGetInventoryIsSnapshot(t *testing.T) {
+	c := NewDefaultCharacter("human", "Ann", "warrior")
+	before := c.Hash()
+	snapshot := c.GetInventory()
+	snapshot.Items[0], snapshot.Items[1] = snapshot.Items[1], snapshot.Items[0]
+	snapshot.Items[0].SetDescription("changed")
+	if err := snapshot.RemoveItem("Rations", 1); err != nil {
+		t.Fatal(err)
+	}
+	if c.Hash() != before {
+		t.Error("changes to the GetInventory snapshot reached the character")
+	}
+}
+
+// rations returns how many rations a snapshot of an inventory holds
+func rations(inv inventory.Inventory) int {
+	if item := inv.GetItem("Rations"); item != nil {
+		return item.GetQuantity()
+	}
+	return 0
+}
+
+func TestInventoryAccessorChangesCharacter(t *testing.T) {
+	c := NewDefaultCharacter("human", "Ann", "warrior")
+	quantity := rations(c.GetInventory())
+	if quantity < 2 {
+		t.Fatalf("default character has %d rations, want at least 2", quantity)
+	}
+	before := c.Hash()
+
+	if err := c.Inventory().RemoveItem("Rations", 1); err != nil {
+		t.Fatal(err)
+	}
+	if got := rations(c.GetInventory()); got != quantity-1 {
+		t.Errorf("GetInventory after removing through Inventory: got %d rations, want %d", got, quantity-1)
+	}
+	if got := rations(*c.Inventory()); got != quantity-1 {
+		t.Errorf("Inventory after removing through it: got %d rations, want %d", got, quantity-1)
+	}
+	if c.Hash() == before {
+		t.Error("hash did not change after removing through Inventory")
+	}
+	if got := rations(roundTrip(t, c).GetInventory()); got != quantity-1 {
+		t.Errorf("after a JSON round trip: got %d rations, want %d", got, quantity-1)
+	}
+}
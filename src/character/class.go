@@ -0,0 +1,184 @@
+package character
+
+import (
+	"dnd-helper/src/abilities"
+	"dnd-helper/src/inventory"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// KitItem is the template of an item in a class starting kit. Abilities
+// are item bonuses, as for inventory.NewItem
+type KitItem struct {
+	Name        string `json:"name"`
+	Quantity    int    `json:"quantity"`
+	Description string `json:"description"`
+	// Weight is the weight of a single unit, nil means DefaultItemWeight
+	Weight    *float64       `json:"weight,omitempty"`
+	Abilities map[string]int `json:"abilities,omitempty"`
+	// Rarity is one of inventory.Rarities, "" means DefaultItemRarity
+	Rarity string `json:"rarity,omitempty"`
+	// Value is the gold value of a single unit
+	Value int `json:"value,omitempty"`
+	// ArmorBonus is the armor class bonus while equipped
+	ArmorBonus int `json:"armorBonus,omitempty"`
+	// Slot is one of inventory.ItemSlots, "" means DefaultItemSlot
+	Slot string `json:"slot,omitempty"`
+	// Consumable items are used up one unit at a time, applying Effect
+	Consumable bool                  `json:"consumable,omitempty"`
+	Effect     *inventory.ItemEffect `json:"effect,omitempty"`
+}
+
+// Class describes the mechanics of a class: its mana scaling and the gear
+// and gold a level-1 character starts with
+type Class struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	// ManaPerIntelligence is the max mana per point of intelligence, 0 means
+	// the default ManaPerIntelligence
+	ManaPerIntelligence int       `json:"manaPerIntelligence"`
+	StartingGold        int       `json:"startingGold"`
+	Kit                 []KitItem `json:"kit"`
+	// Proficiencies are the abilities whose checks get the proficiency
+	// bonus. Proficiency in agility also applies to attack rolls
+	Proficiencies []string `json:"proficiencies"`
+}
+
+var (
+	classesMu sync.RWMutex
+	// classes holds the registered classes keyed by lower-case name
+	classes = map[string]Class{}
+)
+
+func init() {
+	defaults := []Class{
+		{
+			Name: "warrior", Description: "Front-line fighter", ManaPerIntelligence: 20, StartingGold: 15,
+			Proficiencies: []string{"strength", "agility"},
+			Kit: []KitItem{
+				{Name: "Longsword", Quantity: 1, Weight: inventory.Weight(3), Slot: inventory.SlotMainHand, Description: "A sturdy steel blade", Abilities: map[string]int{"strength": 1}},
+				{Name: "Chain mail", Quantity: 1, Weight: inventory.Weight(20), ArmorBonus: 3, Slot: inventory.SlotChest, Description: "Heavy but reliable armor"},
+				{Name: "Rations", Quantity: 3, Weight: inventory.Weight(1), Description: "A day of food each", Consumable: true},
+			},
+		},
+		{
+			Name: "mage", Description: "Wielder of arcane power", ManaPerIntelligence: 50, StartingGold: 10,
+			Proficiencies: []string{"intelligence", "perception"},
+			Kit: []KitItem{
+				{Name: "Staff", Quantity: 1, Weight: inventory.Weight(4), Slot: inventory.SlotMainHand, Description: "A focus for spellcasting", Abilities: map[string]int{"intelligence": 1}},
+				{Name: "Mana potion", Quantity: 2, Weight: inventory.Weight(1), Description: "Restores mana", Consumable: true,
+					Effect: &inventory.ItemEffect{Type: inventory.ItemEffectMana, Amount: 25}},
+				{Name: "Rations", Quantity: 2, Weight: inventory.Weight(1), Description: "A day of food each", Consumable: true},
+			},
+		},
+		{
+			Name: "rogue", Description: "Stealthy and precise", ManaPerIntelligence: 20, StartingGold: 25,
+			Proficiencies: []string{"agility", "luck"},
+			Kit: []KitItem{
+				{Name: "Dagger", Quantity: 2, Weight: inventory.Weight(1), Slot: inventory.SlotMainHand, Description: "Light and easy to hide", Abilities: map[string]int{"agility": 1}},
+				{Name: "Lockpicks", Quantity: 1, Weight: inventory.Weight(1), Description: "For doors that should stay closed"},
+				{Name: "Rations", Quantity: 2, Weight: inventory.Weight(1), Description: "A day of food each", Consumable: true},
+			},
+		},
+		{
+			Name: "ranger", Description: "Hunter of the wilds", ManaPerIntelligence: 25, StartingGold: 15,
+			Proficiencies: []string{"perception", "agility"},
+			Kit: []KitItem{
+				{Name: "Longbow", Quantity: 1, Weight: inventory.Weight(2), Slot: inventory.SlotMainHand, Description: "A yew bow", Abilities: map[string]int{"perception": 1}},
+				{Name: "Arrows", Quantity: 20, Weight: inventory.Weight(1), Description: "Fletched with goose feathers"},
+				{Name: "Rations", Quantity: 3, Weight: inventory.Weight(1), Description: "A day of food each", Consumable: true},
+			},
+		},
+	}
+	for _, class := range defaults {
+		if err := RegisterClass(class); err != nil {
+			panic(err)
+		}
+	}
+}
+
+// RegisterClass adds a class or replaces the class with the same name. Kit
+// items are validated like inventory.NewItem
+func RegisterClass(class Class) error {
+	if class.Name == "" {
+		return fmt.Errorf("class name cannot be empty")
+	}
+	if class.ManaPerIntelligence < 0 || class.StartingGold < 0 {
+		return fmt.Errorf("class %s: mana scaling and starting gold cannot be negative", class.Name)
+	}
+	for _, kitItem := range class.Kit {
+		if _, err := kitItem.newItem(); err != nil {
+			return fmt.Errorf("class %s: kit item %s: %w", class.Name, kitItem.Name, err)
+		}
+	}
+	standard := abilities.NewDefaultAbilities()
+	for _, ability := range class.Proficiencies {
+		if _, err := standard.CurrentValue(ability); err != nil {
+			return fmt.Errorf("class %s: proficiency: %w", class.Name, err)
+		}
+	}
+
+	classesMu.Lock()
+	defer classesMu.Unlock()
+	classes[strings.ToLower(class.Name)] = class
+	slog.Debug("Class registered", "class", class.Name)
+	return nil
+}
+
+// LookupClass returns a registered class by name, ignoring case
+func LookupClass(name string) (Class, bool) {
+	classesMu.RLock()
+	defer classesMu.RUnlock()
+	class, ok := classes[strings.ToLower(name)]
+	return class, ok
+}
+
+// Classes returns every registered class sorted by name
+func Classes() []Class {
+	classesMu.RLock()
+	defer classesMu.RUnlock()
+	list := make([]Class, 0, len(classes))
+	for _, class := range classes {
+		list = append(list, class)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list
+}
+
+// ClassKit returns new items for the starting kit of a class and its
+// starting gold. Unknown classes get an empty kit and no gold
+func ClassKit(class string) ([]inventory.Item, int) {
+	registered, ok := LookupClass(class)
+	if !ok {
+		slog.Debug("Unknown class, no starting kit", "class", class)
+		return nil, 0
+	}
+	items := make([]inventory.Item, 0, len(registered.Kit))
+	for _, kitItem := range registered.Kit {
+		// Kit items are validated on registration
+		item, _ := kitItem.newItem()
+		items = append(items, item)
+	}
+	return items, registered.StartingGold
+}
+
+// newItem builds a fresh item from the template, so characters never share
+// item abilities
+func (k KitItem) newItem() (inventory.Item, error) {
+	template := inventory.ItemTemplate{
+		Name:        k.Name,
+		Description: k.Description,
+		Weight:      k.Weight,
+		Abilities:   k.Abilities,
+		Rarity:      k.Rarity,
+		Value:       k.Value,
+		ArmorBonus:  k.ArmorBonus,
+		Slot:        k.Slot,
+		Consumable:  k.Consumable,
+		Effect:      k.Effect,
+	}
+	return template.New(k.Quantity)
+}
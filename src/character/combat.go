@@ -0,0 +1,181 @@
+package character
+
+import (
+	"dnd-helper/src/condition"
+	"dnd-helper/src/dice"
+	"dnd-helper/src/inventory"
+	"dnd-helper/src/rules"
+	"fmt"
+	"log/slog"
+	"math/rand"
+	"time"
+)
+
+// Combat settings
+const (
+	// BaseDefense is the armor class of a defender with an agility modifier
+	// of 0 and no armor
+	BaseDefense = 10
+	// BaseDamage is rolled on every hit, before the strength modifier
+	BaseDamage = "1d6"
+	// CritMultiplier multiplies the damage of a critical hit
+	CritMultiplier = 2
+)
+
+// ConditionStunned costs a fumbling character their next action
+const ConditionStunned = condition.Condition("Stunned")
+
+// AttackResult is the breakdown of a single attack
+type AttackResult struct {
+	Natural     int                  `json:"natural"`
+	Modifier    int                  `json:"modifier"`
+	Proficiency int                  `json:"proficiency,omitempty"`
+	Total       int                  `json:"total"`
+	Defense     int                  `json:"defense"`
+	Hit         bool                 `json:"hit"`
+	Critical    bool                 `json:"critical"`
+	CritRange   rules.CritRange      `json:"critRange"`
+	Damage      int                  `json:"damage"`
+	Fumble      *rules.FumbleOutcome `json:"fumble,omitempty"`
+}
+
+// FumbleResult is what applying a fumble outcome changed
+type FumbleResult struct {
+	Outcome string `json:"outcome"`
+	// DroppedItem is the weapon moved to the loot pile
+	DroppedItem string `json:"droppedItem,omitempty"`
+	// Ally is the name of the ally that was hit for AllyDamage
+	Ally       string `json:"ally,omitempty"`
+	AllyDamage int    `json:"allyDamage,omitempty"`
+	LostAction bool   `json:"lostAction,omitempty"`
+}
+
+// Attack makes attacker strike defender once with the default ruleset and
+// applies the damage through TakeDamage
+func Attack(attacker, defender *Character) (hit bool, damage int, err error) {
+	result, err := ResolveAttack(attacker, defender, rules.DefaultRuleset(), rand.New(rand.NewSource(time.Now().UnixNano())))
+	return result.Hit, result.Damage, err
+}
+
+// ResolveAttack rolls a d20 plus the attacker's agility modifier against the
+// defender's ArmorClass, adding the proficiency bonus if the attacker is
+// proficient in agility. A hit deals BaseDamage plus the attacker's strength
+// modifier, at least 1. Natural rolls in the
+// crit range always hit for CritMultiplier damage; a natural 1 always misses
+// and rolls on the fumble table. Effective abilities are used on both sides
+func ResolveAttack(attacker, defender *Character, ruleset *rules.Ruleset, rng *rand.Rand) (AttackResult, error) {
+	if attacker == nil || defender == nil {
+		return AttackResult{}, fmt.Errorf("attacker and defender are required")
+	}
+	if attacker == defender {
+		return AttackResult{}, fmt.Errorf("%s cannot attack themselves", attacker.name)
+	}
+	if attacker.IsDead() {
+		return AttackResult{}, fmt.Errorf("%s is dead and cannot attack", attacker.name)
+	}
+	if defender.IsDead() {
+		return AttackResult{}, fmt.Errorf("%s is already dead", defender.name)
+	}
+
+	attackerAbilities := attacker.GetEffectiveAbilities()
+	attackMod, _ := attackerAbilities.Modifier("agility")
+	proficiency := attacker.proficiencyFor("agility")
+	strengthMod, _ := attackerAbilities.Modifier("strength")
+
+	natural, _ := dice.Expression{Count: 1, Sides: 20}.Roll(rng)
+	result := AttackResult{
+		Natural:     natural,
+		Modifier:    attackMod,
+		Proficiency: proficiency,
+		Total:       natural + attackMod + proficiency,
+		Defense:     defender.ArmorClass(),
+		CritRange:   ruleset.CritRangeFor(&attackerAbilities),
+	}
+	switch {
+	case result.CritRange.IsCrit(natural):
+		result.Hit, result.Critical = true, true
+	case natural == rules.FumbleRoll:
+		if fumble, err := ruleset.RollFumble(rng); err == nil {
+			result.Fumble = &fumble
+		}
+	default:
+		result.Hit = result.Total >= result.Defense
+	}
+
+	if !result.Hit {
+		slog.Debug("Attack missed", "attacker", attacker.name, "defender", defender.name, "total", result.Total, "defense", result.Defense)
+		return result, nil
+	}
+
+	damage, err := rollDamage(strengthMod, rng)
+	if err != nil {
+		return AttackResult{}, err
+	}
+	result.Damage = damage
+	if result.Critical {
+		result.Damage *= CritMultiplier
+	}
+	if err := defender.TakeDamage(result.Damage); err != nil {
+		return AttackResult{}, err
+	}
+	slog.Debug("Attack hit", "attacker", attacker.name, "defender", defender.name, "damage", result.Damage, "total", result.Total, "defense", result.Defense, "critical", result.Critical)
+	return result, nil
+}
+
+// rollDamage rolls BaseDamage plus a strength modifier, at least 1
+func rollDamage(strengthMod int, rng *rand.Rand) (int, error) {
+	damage, _, err := dice.RollWith(BaseDamage, rng)
+	if err != nil {
+		return 0, err
+	}
+	return max(damage+strengthMod, 1), nil
+}
+
+// ApplyFumble applies a fumble outcome to the character who fumbled.
+// rules.FumbleDropWeapon unequips the main-hand weapon and moves one unit of it
+// to loot through inventory.Transfer, rules.FumbleHitAlly deals the
+// character's damage to ally and rules.FumbleLoseNextAction stuns the
+// character for a turn.
+// Outcomes without a rule, e.g. dropping a weapon with empty hands or
+// hitting an ally when none is near, change nothing
+func (c *Character) ApplyFumble(outcome rules.FumbleOutcome, loot *inventory.Inventory, ally *Character, rng *rand.Rand) (FumbleResult, error) {
+	result := FumbleResult{Outcome: outcome.Name}
+	switch outcome.Name {
+	case rules.FumbleDropWeapon:
+		weapon, armed := c.equipment[SlotMainHand]
+		if !armed {
+			break
+		}
+		before := c.itemQuantity(weapon)
+		if err := inventory.Transfer(&c.inventory, loot, weapon, 1); err != nil {
+			return FumbleResult{}, fmt.Errorf("cannot drop %s: %w", weapon, err)
+		}
+		c.recordItem(weapon, before)
+		delete(c.equipment, SlotMainHand)
+		c.dropMissingEquipment()
+		result.DroppedItem = weapon
+		slog.Info("Weapon dropped", "character", c.name, "item", weapon)
+	case rules.FumbleHitAlly:
+		if ally == nil || ally == c || ally.IsDead() {
+			break
+		}
+		effective := c.GetEffectiveAbilities()
+		strengthMod, _ := effective.Modifier("strength")
+		damage, err := rollDamage(strengthMod, rng)
+		if err != nil {
+			return FumbleResult{}, err
+		}
+		if err := ally.TakeDamage(damage); err != nil {
+			return FumbleResult{}, err
+		}
+		result.Ally, result.AllyDamage = ally.name, damage
+		slog.Info("Ally hit", "character", c.name, "ally", ally.name, "damage", damage)
+	case rules.FumbleLoseNextAction:
+		c.AddTimedCondition(ConditionStunned, 1)
+		result.LostAction = true
+		slog.Info("Next action lost", "character", c.name)
+	default:
+		slog.Debug("Fumble outcome without a rule", "character", c.name, "outcome", outcome.Name)
+	}
+	return result, nil
+}
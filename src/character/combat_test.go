@@ -0,0 +1,72 @@
+package character
+
+import (
+	"dnd-helper/src/inventory"
+	"dnd-helper/src/rules"
+	"math/rand"
+	"testing"
+)
+
+func TestAttackReportsCritRange(t *testing.T) {
+	ruleset := rules.DefaultRuleset()
+	attacker := NewDefaultCharacter("human", "Ann", "warrior")
+	result, err := ResolveAttack(attacker, NewDefaultCharacter("elf", "Bo", "mage"), ruleset, rand.New(rand.NewSource(1)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	effective := attacker.GetEffectiveAbilities()
+	if want := ruleset.CritRangeFor(&effective); result.CritRange.Threshold != want.Threshold {
+		t.Errorf("crit range %d, want %d", result.CritRange.Threshold, want.Threshold)
+	}
+}
+
+func TestApplyFumbleDropWeapon(t *testing.T) {
+	c := NewDefaultCharacter("human", "Ann", "warrior")
+	if err := c.Equip("Longsword"); err != nil {
+		t.Fatal(err)
+	}
+	loot := inventory.NewInventory()
+
+	result, err := c.ApplyFumble(rules.FumbleOutcome{Name: rules.FumbleDropWeapon}, loot, nil, rand.New(rand.NewSource(1)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.DroppedItem != "Longsword" {
+		t.Errorf("dropped %q, want Longsword", result.DroppedItem)
+	}
+	if c.IsEquipped("Longsword") || c.Inventory().GetItem("Longsword") != nil {
+		t.Error("the longsword is still equipped or carried")
+	}
+	if loot.GetItem("Longsword") == nil {
+		t.Error("the longsword is not in the loot pile")
+	}
+
+	// Nothing is left to drop
+	result, err = c.ApplyFumble(rules.FumbleOutcome{Name: rules.FumbleDropWeapon}, loot, nil, rand.New(rand.NewSource(1)))
+	if err != nil || result.DroppedItem != "" {
+		t.Errorf("dropped %q with empty hands, error %v", result.DroppedItem, err)
+	}
+}
+
+func TestApplyFumbleHitAllyAndLoseAction(t *testing.T) {
+	c := NewDefaultCharacter("human", "Ann", "warrior")
+	ally := NewDefaultCharacter("elf", "Bo", "mage")
+	start := ally.GetCurrentHP()
+
+	result, err := c.ApplyFumble(rules.FumbleOutcome{Name: rules.FumbleHitAlly}, inventory.NewInventory(), ally, rand.New(rand.NewSource(1)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.AllyDamage < 1 || ally.GetCurrentHP() != start-result.AllyDamage {
+		t.Errorf("ally at %d hit points after %d damage, started at %d", ally.GetCurrentHP(), result.AllyDamage, start)
+	}
+
+	result, err = c.ApplyFumble(rules.FumbleOutcome{Name: rules.FumbleLoseNextAction}, inventory.NewInventory(), nil, rand.New(rand.NewSource(1)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	conditions := c.GetConditions()
+	if !result.LostAction || conditions.Remaining(ConditionStunned) != 1 {
+		t.Errorf("lost action %v with %s, want Stunned for 1 turn", result.LostAction, conditions.String())
+	}
+}
@@ -0,0 +1,97 @@
+package character
+
+import (
+	"fmt"
+	"log/slog"
+)
+
+// AddCompanion makes the character the owner of a copy of companion.
+// Companions live inside their owner rather than in the roster, cannot own
+// companions themselves and must have a name unique among the owner's
+// companions
+func (c *Character) AddCompanion(companion *Character) error {
+	if companion == nil {
+		return fmt.Errorf("companion cannot be nil")
+	}
+	if companion == c || companion.id == c.id {
+		return fmt.Errorf("%s cannot be their own companion", c.name)
+	}
+	if c.owned {
+		return fmt.Errorf("companion %s cannot own companions", c.name)
+	}
+	if len(companion.companions) > 0 {
+		return fmt.Errorf("%s owns companions and cannot become one", companion.name)
+	}
+	if _, ok := c.companionIndex(companion.name); ok {
+		return fmt.Errorf("%s already has a companion named %s", c.name, companion.name)
+	}
+
+	added := companion.Clone()
+	added.owned = true
+	c.companions = append(c.companions, added)
+	c.record("companions", "", added.name)
+	slog.Info("Companion added", "character", c.name, "companion", added.name)
+	return nil
+}
+
+// RemoveCompanion removes the companion with the given name and returns it
+func (c *Character) RemoveCompanion(name string) (*Character, error) {
+	i, ok := c.companionIndex(name)
+	if !ok {
+		return nil, fmt.Errorf("%s has no companion named %s", c.name, name)
+	}
+	removed := c.companions[i]
+	c.companions = append(c.companions[:i:i], c.companions[i+1:]...)
+	c.record("companions", removed.name, "")
+	slog.Info("Companion removed", "character", c.name, "companion", removed.name)
+	return removed, nil
+}
+
+// Companions returns copies of the companions in the order they were added
+func (c *Character) Companions() []*Character {
+	companions := make([]*Character, len(c.companions))
+	for i, companion := range c.companions {
+		companions[i] = companion.Clone()
+	}
+	return companions
+}
+
+// IsCompanion checks if the character is owned by another character
+func (c *Character) IsCompanion() bool {
+	return c.owned
+}
+
+// CountsCompanionWeight checks if the inventory weight of the companions
+// counts against the owner's carry capacity
+func (c *Character) CountsCompanionWeight() bool {
+	return c.shareLoad
+}
+
+// SetCountCompanionWeight sets whether the inventory weight of the
+// companions counts against the owner's carry capacity, e.g. when the owner
+// carries a familiar's pack
+func (c *Character) SetCountCompanionWeight(count bool) {
+	c.shareLoad = count
+}
+
+// companionIndex returns the position of the companion with the given name
+func (c *Character) companionIndex(name string) (int, bool) {
+	for i, companion := range c.companions {
+		if companion.name == name {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+// carriedWeight returns the weight counted against the carry capacity: the
+// inventory, plus the companions' inventories if CountsCompanionWeight
+func (c *Character) carriedWeight() float64 {
+	weight := c.inventory.GetTotalWeight()
+	if c.shareLoad {
+		for _, companion := range c.companions {
+			weight += companion.inventory.GetTotalWeight()
+		}
+	}
+	return weight
+}
@@ -0,0 +1,97 @@
+package character
+
+import (
+	"dnd-helper/src/inventory"
+	"fmt"
+	"log/slog"
+)
+
+// NotConsumableError is returned when using an item that is not consumable
+type NotConsumableError struct {
+	Item string
+}
+
+func (e *NotConsumableError) Error() string {
+	return fmt.Sprintf("%s is not consumable", e.Item)
+}
+
+// OutOfItemError is returned when using an item with no units left
+type OutOfItemError struct {
+	Item string
+}
+
+func (e *OutOfItemError) Error() string {
+	return fmt.Sprintf("no %s left", e.Item)
+}
+
+// UseResult describes what using an item changed
+type UseResult struct {
+	Item   string                `json:"item"`
+	Effect *inventory.ItemEffect `json:"effect,omitempty"`
+	// Remaining is the quantity of the item left over all its stacks
+	Remaining     int `json:"remaining"`
+	HitPoints     int `json:"hitPoints"`
+	ManaPoints    int `json:"manaPoints"`
+	MaxManaPoints int `json:"maxManaPoints"`
+}
+
+// UseItem uses up one unit of a consumable item and applies its effect:
+// healing, restoring mana or an ability modifier lasting the effect's
+// rounds. Everything is checked first, so a failed use changes nothing.
+// Items that are not consumable give a *NotConsumableError, items with no
+// units left an *OutOfItemError and healing a dead character a *DeadError
+func (c *Character) UseItem(name string) (UseResult, error) {
+	item := c.inventory.GetItem(name)
+	if item == nil {
+		return UseResult{}, fmt.Errorf("item %s not found in inventory", name)
+	}
+	if !item.IsConsumable() {
+		return UseResult{}, &NotConsumableError{Item: name}
+	}
+	if c.itemQuantity(name) == 0 {
+		return UseResult{}, &OutOfItemError{Item: name}
+	}
+	effect := item.GetEffect()
+	if effect != nil {
+		switch effect.Type {
+		case inventory.ItemEffectHeal:
+			if c.IsDead() {
+				return UseResult{}, &DeadError{Name: c.name}
+			}
+		case inventory.ItemEffectAbility:
+			if _, err := c.abilities.CurrentValue(effect.Ability); err != nil {
+				return UseResult{}, fmt.Errorf("item %s: %w", name, err)
+			}
+		}
+	}
+
+	if err := c.RemoveItem(name, 1); err != nil {
+		return UseResult{}, err
+	}
+	if effect != nil {
+		switch effect.Type {
+		case inventory.ItemEffectHeal:
+			// The character is alive, so healing cannot fail
+			_ = c.Heal(effect.Amount)
+		case inventory.ItemEffectMana:
+			c.RestoreMana(effect.Amount)
+		case inventory.ItemEffectAbility:
+			// The ability and duration are valid, so applying cannot fail
+			_ = c.ApplyEffect(Effect{
+				Name:        name,
+				Duration:    effect.Duration,
+				AbilityMods: map[string]int{effect.Ability: effect.Amount},
+			})
+		}
+	}
+
+	slog.Info("Item used", "character", c.name, "item", name)
+	return UseResult{
+		Item:          name,
+		Effect:        effect,
+		Remaining:     c.itemQuantity(name),
+		HitPoints:     c.hitPoints,
+		ManaPoints:    c.manaPoints,
+		MaxManaPoints: c.GetMaxManaPoints(),
+	}, nil
+}
@@ -0,0 +1,136 @@
+package character
+
+import (
+	"dnd-helper/src/condition"
+	"fmt"
+	"log/slog"
+)
+
+// PermanentEffect is the duration of an effect that never expires
+const PermanentEffect = -1
+
+// Effect is a status effect lasting a number of rounds, e.g. "Stunned" for
+// 2 rounds. While active its ability modifiers count towards the effective
+// abilities and its ConditionOverride, if any, is an active condition
+type Effect struct {
+	Name string `json:"name"`
+	// Duration is the rounds left, PermanentEffect for effects that never expire
+	Duration          int                 `json:"duration"`
+	AbilityMods       map[string]int      `json:"abilityMods,omitempty"`
+	ConditionOverride condition.Condition `json:"conditionOverride,omitempty"`
+}
+
+// clone returns a copy of the effect that shares no modifiers with the original
+func (e Effect) clone() Effect {
+	if e.AbilityMods != nil {
+		mods := make(map[string]int, len(e.AbilityMods))
+		for name, amount := range e.AbilityMods {
+			mods[name] = amount
+		}
+		e.AbilityMods = mods
+	}
+	return e
+}
+
+// ApplyEffect starts a status effect. Applying an active effect again
+// refreshes its duration instead of stacking it
+func (c *Character) ApplyEffect(effect Effect) error {
+	if effect.Name == "" {
+		return fmt.Errorf("effect name cannot be empty")
+	}
+	if effect.Duration <= 0 && effect.Duration != PermanentEffect {
+		return fmt.Errorf("effect %s: duration %d must be positive or %d for permanent effects", effect.Name, effect.Duration, PermanentEffect)
+	}
+	for name := range effect.AbilityMods {
+		if _, err := c.abilities.CurrentValue(name); err != nil {
+			return fmt.Errorf("effect %s: %w", effect.Name, err)
+		}
+	}
+	if effect.ConditionOverride != "" {
+		if _, ok := condition.DefaultRegistry.Lookup(effect.ConditionOverride.String()); !ok {
+			return fmt.Errorf("effect %s: unknown condition %s", effect.Name, effect.ConditionOverride)
+		}
+	}
+
+	if effect.ConditionOverride != "" {
+		before := c.conditions.String()
+		c.conditions.Add(effect.ConditionOverride)
+		c.record("conditions", before, c.conditions.String())
+	}
+	for i := range c.effects {
+		if c.effects[i].Name == effect.Name {
+			c.effects[i].Duration = effect.Duration
+			slog.Debug("Effect refreshed", "character", c.name, "effect", effect.Name, "rounds", effect.Duration)
+			return nil
+		}
+	}
+	c.effects = append(c.effects, effect.clone())
+	c.RecalculateDerivedStats()
+	slog.Debug("Effect applied", "character", c.name, "effect", effect.Name, "rounds", effect.Duration)
+	return nil
+}
+
+// GetEffects returns the active status effects in the order they were applied
+func (c *Character) GetEffects() []Effect {
+	effects := make([]Effect, len(c.effects))
+	for i, effect := range c.effects {
+		effects[i] = effect.clone()
+	}
+	return effects
+}
+
+// EffectBonuses sums the ability modifiers of every active effect
+func (c *Character) EffectBonuses() map[string]int {
+	bonuses := map[string]int{}
+	for _, effect := range c.effects {
+		for name, amount := range effect.AbilityMods {
+			bonuses[name] += amount
+		}
+	}
+	return bonuses
+}
+
+// TickRound advances the character by one round: timed conditions and
+// effects count down, and expired effects are removed together with their
+// condition, unless another active effect still carries it. It returns the
+// effects that expired
+func (c *Character) TickRound() []Effect {
+	c.TickConditions()
+
+	var expired []Effect
+	active := c.effects[:0]
+	for _, effect := range c.effects {
+		if effect.Duration != PermanentEffect {
+			effect.Duration--
+			if effect.Duration <= 0 {
+				expired = append(expired, effect)
+				continue
+			}
+		}
+		active = append(active, effect)
+	}
+	c.effects = active
+
+	before := c.conditions.String()
+	for _, effect := range expired {
+		slog.Debug("Effect expired", "character", c.name, "effect", effect.Name)
+		if effect.ConditionOverride != "" && !c.hasEffectCondition(effect.ConditionOverride) {
+			c.conditions.Remove(effect.ConditionOverride)
+		}
+	}
+	c.record("conditions", before, c.conditions.String())
+	if len(expired) > 0 {
+		c.RecalculateDerivedStats()
+	}
+	return expired
+}
+
+// hasEffectCondition checks if an active effect carries cond
+func (c *Character) hasEffectCondition(cond condition.Condition) bool {
+	for _, effect := range c.effects {
+		if effect.ConditionOverride == cond {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,80 @@
+package character
+
+import (
+	"dnd-helper/src/inventory"
+	"errors"
+	"fmt"
+	"log/slog"
+)
+
+// CarryCapacityPerStrength is the weight a character can carry per point of
+// strength
+const CarryCapacityPerStrength = 10
+
+// HeavyLoadPercent is the share of the carry capacity above which a load
+// counts as heavy
+const HeavyLoadPercent = 80
+
+// Encumbrance describes how loaded down a character is
+type Encumbrance string
+
+const (
+	EncumbranceLight      Encumbrance = "Light"
+	EncumbranceHeavy      Encumbrance = "Heavy"
+	EncumbranceOverloaded Encumbrance = "Overloaded"
+)
+
+// ErrOverEncumbered is returned when an item would take a character over
+// their carry capacity
+var ErrOverEncumbered = errors.New("over encumbered")
+
+// CarryCapacityFor returns the carry capacity of a strength score
+func CarryCapacityFor(strength int) int {
+	return strength * CarryCapacityPerStrength
+}
+
+// GetCarryCapacity returns the weight the character can carry, derived from
+// the effective strength so it follows strength changes immediately
+func (c *Character) GetCarryCapacity() int {
+	effective := c.GetEffectiveAbilities()
+	strength, _ := effective.CurrentValue("strength")
+	return CarryCapacityFor(strength)
+}
+
+// GetEncumbrance returns Light up to HeavyLoadPercent of the carry capacity,
+// Heavy up to the full capacity and Overloaded above it
+func (c *Character) GetEncumbrance() Encumbrance {
+	weight, capacity := c.carriedWeight(), float64(c.GetCarryCapacity())
+	switch {
+	case weight > capacity:
+		return EncumbranceOverloaded
+	case weight*100 > capacity*HeavyLoadPercent:
+		return EncumbranceHeavy
+	default:
+		return EncumbranceLight
+	}
+}
+
+// checkCapacity fails with ErrOverEncumbered if extra weight of the item
+// name does not fit
+func (c *Character) checkCapacity(name string, extra float64) error {
+	weight, capacity := c.carriedWeight()+extra, float64(c.GetCarryCapacity())
+	if weight > capacity {
+		return fmt.Errorf("%w: %s would bring the load to %.1f, %.1f over the capacity of %.0f",
+			ErrOverEncumbered, name, weight, weight-capacity, capacity)
+	}
+	return nil
+}
+
+// AddItem adds an item to the character's inventory unless it would exceed
+// the carry capacity
+func (c *Character) AddItem(item inventory.Item) error {
+	if err := c.checkCapacity(item.GetName(), item.GetTotalWeight()); err != nil {
+		return err
+	}
+	before := c.itemQuantity(item.GetName())
+	c.inventory.AddItem(item)
+	c.recordItem(item.GetName(), before)
+	slog.Info("Item added", "character", c.name, "item", item.GetName(), "quantity", item.GetQuantity(), "load", c.carriedWeight(), "capacity", c.GetCarryCapacity())
+	return nil
+}
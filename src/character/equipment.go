@@ -0,0 +1,234 @@
+package character
+
+import (
+	"dnd-helper/src/abilities"
+	"dnd-helper/src/condition"
+	"dnd-helper/src/inventory"
+	"fmt"
+	"log/slog"
+	"sort"
+)
+
+// Equipment slots. Rings go on either hand, so a character wears two
+const (
+	SlotHead     = inventory.SlotHead
+	SlotChest    = inventory.SlotChest
+	SlotMainHand = inventory.SlotMainHand
+	SlotOffHand  = inventory.SlotOffHand
+	SlotRing1    = "ring1"
+	SlotRing2    = "ring2"
+)
+
+// equipmentSlots lists every valid slot in sheet order
+var equipmentSlots = []string{SlotHead, SlotChest, SlotMainHand, SlotOffHand, SlotRing1, SlotRing2}
+
+func isEquipmentSlot(slot string) bool {
+	for _, s := range equipmentSlots {
+		if s == slot {
+			return true
+		}
+	}
+	return false
+}
+
+// slotsFor returns the equipment slots an item with the given item slot
+// fits, none for inventory.SlotNone
+func slotsFor(itemSlot string) []string {
+	switch itemSlot {
+	case inventory.SlotRing:
+		return []string{SlotRing1, SlotRing2}
+	case inventory.SlotNone:
+		return nil
+	}
+	return []string{itemSlot}
+}
+
+// itemSlotOf returns the item slot that fits an equipment slot
+func itemSlotOf(slot string) string {
+	if slot == SlotRing1 || slot == SlotRing2 {
+		return inventory.SlotRing
+	}
+	return slot
+}
+
+// Equip puts an inventory item into the first free slot its item slot fits,
+// so its ability bonuses apply to the character's effective abilities.
+// Equipping an item that is already equipped, one that cannot be equipped or
+// one whose slots are all taken is an error
+func (c *Character) Equip(itemName string) error {
+	return c.equip(itemName, "", false)
+}
+
+// EquipSlot puts an inventory item into a specific slot, which must fit the
+// item and be empty
+func (c *Character) EquipSlot(itemName string, slot string) error {
+	return c.equip(itemName, slot, false)
+}
+
+// ForceEquip is EquipSlot that unequips the item currently in the slot. An
+// empty slot picks the first free slot the item fits, or the first one if
+// they are all taken
+func (c *Character) ForceEquip(itemName string, slot string) error {
+	return c.equip(itemName, slot, true)
+}
+
+func (c *Character) equip(itemName string, slot string, force bool) error {
+	item := c.inventory.GetItem(itemName)
+	if item == nil {
+		return fmt.Errorf("item %s not found in inventory", itemName)
+	}
+	fits := slotsFor(item.GetSlot())
+	if len(fits) == 0 {
+		return fmt.Errorf("item %s cannot be equipped", itemName)
+	}
+	if slot == "" {
+		slot = fits[0]
+		for _, s := range fits {
+			if _, occupied := c.equipment[s]; !occupied {
+				slot = s
+				break
+			}
+		}
+	}
+	if !isEquipmentSlot(slot) {
+		return fmt.Errorf("unknown equipment slot %s", slot)
+	}
+	if itemSlotOf(slot) != item.GetSlot() {
+		return fmt.Errorf("item %s goes in the %s slot, not %s", itemName, item.GetSlot(), slot)
+	}
+	for s, equipped := range c.equipment {
+		if equipped == itemName && (s != slot || !force) {
+			return fmt.Errorf("item %s is already equipped in slot %s", itemName, s)
+		}
+	}
+	if current, occupied := c.equipment[slot]; occupied {
+		if !force {
+			return fmt.Errorf("slot %s is already occupied by %s", slot, current)
+		}
+		slog.Debug("Item unequipped, slot is needed", "character", c.name, "item", current, "slot", slot)
+	}
+
+	if c.equipment == nil {
+		c.equipment = map[string]string{}
+	}
+	c.equipment[slot] = itemName
+	slog.Debug("Item equipped", "character", c.name, "item", itemName, "slot", slot)
+	return nil
+}
+
+// Unequip takes an equipped item off, removing its bonuses
+func (c *Character) Unequip(itemName string) error {
+	slot, equipped := c.SlotOf(itemName)
+	if !equipped {
+		return fmt.Errorf("item %s is not equipped", itemName)
+	}
+	return c.UnequipSlot(slot)
+}
+
+// UnequipSlot empties a slot, removing the bonuses of the item in it
+func (c *Character) UnequipSlot(slot string) error {
+	itemName, occupied := c.equipment[slot]
+	if !occupied {
+		return fmt.Errorf("slot %s is empty", slot)
+	}
+	delete(c.equipment, slot)
+	c.RecalculateDerivedStats()
+	slog.Debug("Item unequipped", "character", c.name, "item", itemName, "slot", slot)
+	return nil
+}
+
+// SlotOf returns the slot an item is equipped in
+func (c *Character) SlotOf(itemName string) (string, bool) {
+	for slot, equipped := range c.equipment {
+		if equipped == itemName {
+			return slot, true
+		}
+	}
+	return "", false
+}
+
+// IsEquipped reports whether an item is equipped in any slot
+func (c *Character) IsEquipped(itemName string) bool {
+	_, equipped := c.SlotOf(itemName)
+	return equipped
+}
+
+// GetEquipment returns the name of the item in every occupied slot
+func (c *Character) GetEquipment() map[string]string {
+	equipment := make(map[string]string, len(c.equipment))
+	for slot, itemName := range c.equipment {
+		equipment[slot] = itemName
+	}
+	return equipment
+}
+
+// EquipmentBonuses sums the ability bonuses of all equipped items
+func (c *Character) EquipmentBonuses() map[string]int {
+	bonuses := map[string]int{}
+	slots := make([]string, 0, len(c.equipment))
+	for slot := range c.equipment {
+		slots = append(slots, slot)
+	}
+	sort.Strings(slots)
+	for _, slot := range slots {
+		item := c.inventory.GetItem(c.equipment[slot])
+		if item == nil || item.GetAbilities() == nil {
+			continue
+		}
+		for name, value := range item.GetAbilities().GetAllAbilities() {
+			bonuses[name] += value
+		}
+	}
+	return bonuses
+}
+
+// GetBaseAbilities returns a copy of the stored abilities, the ones point
+// buying and leveling work on
+func (c *Character) GetBaseAbilities() abilities.Abilities {
+	return c.abilities.Clone()
+}
+
+// GetEffectiveAbilities returns the abilities combat should use: the current
+// (drain-aware) values plus the bonuses of equipped items, spell buffs and
+// status effects, then the effects of the active conditions, each step
+// clamped to [MinAbilityValue, MaxAbilityValue]. Conditions come last, so
+// equipment cannot offset e.g. the agility of a stunned character
+func (c *Character) GetEffectiveAbilities() abilities.Abilities {
+	bonuses := c.EquipmentBonuses()
+	for name, amount := range c.buffs {
+		bonuses[name] += amount
+	}
+	for name, amount := range c.EffectBonuses() {
+		bonuses[name] += amount
+	}
+	effective := c.abilities.WithBonuses(bonuses)
+	return effective.WithBonuses(c.ConditionEffects())
+}
+
+// ConditionEffects sums the ability effects of every active condition
+func (c *Character) ConditionEffects() map[string]int {
+	effects := map[string]int{}
+	for _, cond := range c.conditions.List() {
+		for name, amount := range condition.ConditionEffect(cond) {
+			effects[name] += amount
+		}
+	}
+	return effects
+}
+
+// GetEffectiveAbility returns a single effective ability value
+func (c *Character) GetEffectiveAbility(abilityName string) (int, error) {
+	effective := c.GetEffectiveAbilities()
+	return effective.CurrentValue(abilityName)
+}
+
+// dropMissingEquipment unequips items that are no longer in the inventory
+func (c *Character) dropMissingEquipment() {
+	for slot, itemName := range c.equipment {
+		if c.inventory.GetItem(itemName) == nil {
+			delete(c.equipment, slot)
+			slog.Debug("Item unequipped, item is gone", "character", c.name, "item", itemName, "slot", slot)
+		}
+	}
+	c.RecalculateDerivedStats()
+}
@@ -0,0 +1,128 @@
+package character
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+)
+
+// Alignments are the nine valid alignments. An empty alignment means none
+// was chosen
+var Alignments = []string{
+	"Lawful Good", "Neutral Good", "Chaotic Good",
+	"Lawful Neutral", "True Neutral", "Chaotic Neutral",
+	"Lawful Evil", "Neutral Evil", "Chaotic Evil",
+}
+
+// Flavor limits
+const (
+	// MaxBiographyBytes is the longest biography a character can have
+	MaxBiographyBytes = 4096
+	// MaxTags is the most tags a character can have
+	MaxTags = 10
+)
+
+// NormalizeAlignment returns the canonical spelling of one of Alignments,
+// matched case-insensitively. An empty alignment stays empty
+func NormalizeAlignment(alignment string) (string, error) {
+	if alignment == "" {
+		return "", nil
+	}
+	for _, valid := range Alignments {
+		if strings.EqualFold(alignment, valid) {
+			return valid, nil
+		}
+	}
+	return "", fmt.Errorf("unknown alignment %q, must be one of %s", alignment, strings.Join(Alignments, ", "))
+}
+
+// ValidateBiography checks a biography fits in MaxBiographyBytes
+func ValidateBiography(biography string) error {
+	if len(biography) > MaxBiographyBytes {
+		return fmt.Errorf("biography is %d bytes, the limit is %d bytes", len(biography), MaxBiographyBytes)
+	}
+	return nil
+}
+
+// NormalizeTags lowercases and trims tags and drops duplicates, keeping the
+// first occurrence. Empty tags and more than MaxTags distinct tags are errors
+func NormalizeTags(tags []string) ([]string, error) {
+	normalized := []string{}
+	seen := map[string]bool{}
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag == "" {
+			return nil, fmt.Errorf("tags cannot be empty")
+		}
+		if seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		normalized = append(normalized, tag)
+	}
+	if len(normalized) > MaxTags {
+		return nil, fmt.Errorf("%d tags given, the limit is %d", len(normalized), MaxTags)
+	}
+	return normalized, nil
+}
+
+// GetAlignment returns the alignment, empty if none was chosen
+func (c *Character) GetAlignment() string {
+	return c.alignment
+}
+
+// SetAlignment sets one of Alignments, matched case-insensitively. An
+// empty alignment clears it
+func (c *Character) SetAlignment(alignment string) error {
+	normalized, err := NormalizeAlignment(alignment)
+	if err != nil {
+		return err
+	}
+	c.record("alignment", c.alignment, normalized)
+	c.alignment = normalized
+	slog.Debug("Alignment changed", "character", c.name, "alignment", normalized)
+	return nil
+}
+
+// GetBiography returns the free-text biography
+func (c *Character) GetBiography() string {
+	return c.biography
+}
+
+// SetBiography replaces the biography, at most MaxBiographyBytes long
+func (c *Character) SetBiography(biography string) error {
+	if err := ValidateBiography(biography); err != nil {
+		return err
+	}
+	c.biography = biography
+	slog.Debug("Biography changed", "character", c.name, "bytes", len(biography))
+	return nil
+}
+
+// GetTags returns the tags in the order they were first given
+func (c *Character) GetTags() []string {
+	return append([]string{}, c.tags...)
+}
+
+// HasTag checks if the character has a tag, ignoring case
+func (c *Character) HasTag(tag string) bool {
+	tag = strings.ToLower(strings.TrimSpace(tag))
+	for _, t := range c.tags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// SetTags replaces the tags, normalized by NormalizeTags
+func (c *Character) SetTags(tags []string) error {
+	normalized, err := NormalizeTags(tags)
+	if err != nil {
+		return err
+	}
+	c.record("tags", strings.Join(c.tags, ","), strings.Join(normalized, ","))
+	c.tags = normalized
+	slog.Debug("Tags changed", "character", c.name, "tags", strings.Join(normalized, ", "))
+	return nil
+}
@@ -0,0 +1,106 @@
+package character
+
+import (
+	"dnd-helper/src/condition"
+	"fmt"
+	"log/slog"
+)
+
+// HitPointsPerStrength is the max hit points a character gets per point of strength
+var HitPointsPerStrength = 10
+
+// Conditions driven by hit points
+const (
+	ConditionHealthy     = condition.Condition("Healthy")
+	ConditionUnconscious = condition.Condition("Unconscious")
+	ConditionDead        = condition.Condition("Dead")
+)
+
+// maxHitPointsFor derives max hit points from strength
+func maxHitPointsFor(strength int) int {
+	return strength * HitPointsPerStrength
+}
+
+func (c *Character) GetHitPoints() int {
+	return c.hitPoints
+}
+
+// GetMaxHitPoints derives the max hit points from the effective strength,
+// the same strength GetCarryCapacity uses, so equipment, buffs, effects and
+// conditions count. Changes that lower it clamp the current hit points
+// through RecalculateDerivedStats
+func (c *Character) GetMaxHitPoints() int {
+	effective := c.GetEffectiveAbilities()
+	strength, _ := effective.CurrentValue("strength")
+	return maxHitPointsFor(strength)
+}
+
+// GetCurrentHP is GetHitPoints
+func (c *Character) GetCurrentHP() int {
+	return c.GetHitPoints()
+}
+
+// GetMaxHP is GetMaxHitPoints
+func (c *Character) GetMaxHP() int {
+	return c.GetMaxHitPoints()
+}
+
+// IsDead checks if the character has the Dead condition
+func (c *Character) IsDead() bool {
+	return c.conditions.Has(ConditionDead)
+}
+
+// TakeDamage lowers hit points, clamped at 0. Dropping to 0 knocks the
+// character Unconscious; damage left over after reaching 0 that equals or
+// exceeds max hit points kills the character outright
+func (c *Character) TakeDamage(n int) error {
+	if n < 0 {
+		return fmt.Errorf("damage %d cannot be negative", n)
+	}
+	if c.IsDead() {
+		return &DeadError{Name: c.name}
+	}
+
+	overflow := n - c.hitPoints
+	c.hitPoints = max(c.hitPoints-n, 0)
+	slog.Debug("Damage taken", "character", c.name, "damage", n, "hitPoints", c.hitPoints)
+
+	if c.hitPoints == 0 {
+		before := c.conditions.String()
+		c.conditions.Remove(ConditionHealthy)
+		if overflow >= c.GetMaxHitPoints() {
+			c.conditions.Remove(ConditionUnconscious)
+			c.conditions.Add(ConditionDead)
+			slog.Info("Character died", "character", c.name)
+		} else {
+			c.conditions.Add(ConditionUnconscious)
+			slog.Info("Character fell unconscious", "character", c.name)
+		}
+		c.record("conditions", before, c.conditions.String())
+	}
+	return nil
+}
+
+// Heal raises hit points, clamped at max. Healing an unconscious character
+// above 0 wakes them up. The dead cannot be healed
+func (c *Character) Heal(n int) error {
+	if n < 0 {
+		return fmt.Errorf("healing %d cannot be negative", n)
+	}
+	if c.IsDead() {
+		return &DeadError{Name: c.name}
+	}
+
+	c.hitPoints = min(c.hitPoints+n, c.GetMaxHitPoints())
+	slog.Debug("Healed", "character", c.name, "amount", n, "hitPoints", c.hitPoints)
+
+	before := c.conditions.String()
+	if c.hitPoints > 0 && c.conditions.Remove(ConditionUnconscious) {
+		if len(c.conditions.List()) == 0 {
+			c.conditions.Add(ConditionHealthy)
+		}
+		c.record("conditions", before, c.conditions.String())
+		slog.Info("Character regained consciousness", "character", c.name)
+	}
+	return nil
+}
@@ -0,0 +1,215 @@
+package character
+
+import (
+	"dnd-helper/src/condition"
+	"errors"
+	"testing"
+)
+
+func TestTakeDamage(t *testing.T) {
+	tests := []struct {
+		name string
+		// damage and wantHP are given relative to the max hit points
+		damage func(maxHP int) []int
+		wantHP func(maxHP int) int
+		want   condition.Condition
+	}{
+		{"partial", func(maxHP int) []int { return []int{10} }, func(maxHP int) int { return maxHP - 10 }, ConditionHealthy},
+		{"down to 1", func(maxHP int) []int { return []int{maxHP - 1} }, func(int) int { return 1 }, ConditionHealthy},
+		{"exactly 0", func(maxHP int) []int { return []int{maxHP} }, func(int) int { return 0 }, ConditionUnconscious},
+		{"in steps", func(maxHP int) []int { return []int{maxHP - 5, 5} }, func(int) int { return 0 }, ConditionUnconscious},
+		{"overflow below max", func(maxHP int) []int { return []int{2*maxHP - 1} }, func(int) int { return 0 }, ConditionUnconscious},
+		{"overflow of max", func(maxHP int) []int { return []int{2 * maxHP} }, func(int) int { return 0 }, ConditionDead},
+		{"overkill clamps at 0", func(maxHP int) []int { return []int{10 * maxHP} }, func(int) int { return 0 }, ConditionDead},
+		{"unconscious hit again", func(maxHP int) []int { return []int{maxHP, maxHP} }, func(int) int { return 0 }, ConditionDead},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewDefaultCharacter("human", "Ann", "warrior")
+			maxHP := c.GetMaxHP()
+			for _, n := range tt.damage(maxHP) {
+				if err := c.TakeDamage(n); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if want := tt.wantHP(maxHP); c.GetCurrentHP() != want {
+				t.Errorf("hit points %d, want %d", c.GetCurrentHP(), want)
+			}
+			if list := c.conditions.List(); len(list) != 1 || list[0] != tt.want {
+				t.Errorf("conditions %s, want only %s", c.conditions.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestDeadCharacters(t *testing.T) {
+	c := NewDefaultCharacter("human", "Ann", "warrior")
+	if err := c.TakeDamage(2 * c.GetMaxHP()); err != nil {
+		t.Fatal(err)
+	}
+	var deadErr *DeadError
+	if err := c.Heal(10); !errors.As(err, &deadErr) {
+		t.Errorf("Heal: got %v, want a DeadError", err)
+	}
+	if err := c.TakeDamage(1); !errors.As(err, &deadErr) {
+		t.Errorf("TakeDamage: got %v, want a DeadError", err)
+	}
+	if c.GetCurrentHP() != 0 {
+		t.Errorf("hit points %d, want 0", c.GetCurrentHP())
+	}
+}
+
+func TestHealClampsAtMax(t *testing.T) {
+	c := NewDefaultCharacter("human", "Ann", "warrior")
+	if err := c.TakeDamage(20); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Heal(100); err != nil {
+		t.Fatal(err)
+	}
+	if c.GetCurrentHP() != c.GetMaxHP() {
+		t.Errorf("hit points %d, want the max of %d", c.GetCurrentHP(), c.GetMaxHP())
+	}
+	if err := c.Heal(-1); err == nil {
+		t.Error("Heal accepted a negative amount")
+	}
+}
+
+// strengthSources checks max hit points and carry capacity follow the same
+// effective strength
+func strengthSources(t *testing.T, c *Character) int {
+	t.Helper()
+	strength, err := c.GetEffectiveAbility("strength")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := c.GetMaxHP(), maxHitPointsFor(strength); got != want {
+		t.Errorf("max hit points %d, want %d for effective strength %d", got, want, strength)
+	}
+	if got, want := c.GetCarryCapacity(), CarryCapacityFor(strength); got != want {
+		t.Errorf("carry capacity %d, want %d for effective strength %d", got, want, strength)
+	}
+	return strength
+}
+
+func TestMaxHitPointsFollowEffectiveStrength(t *testing.T) {
+	// The warrior kit has a longsword with +1 strength
+	c := NewDefaultCharacter("human", "Ann", "warrior")
+	base := strengthSources(t, c)
+	if err := c.Equip("Longsword"); err != nil {
+		t.Fatal(err)
+	}
+	if got := strengthSources(t, c); got != base+1 {
+		t.Fatalf("strength %d with the longsword, want %d", got, base+1)
+	}
+	if err := c.Heal(c.GetMaxHP()); err != nil {
+		t.Fatal(err)
+	}
+	full := c.GetCurrentHP()
+
+	// Unequipping lowers the max and clamps the current hit points
+	if err := c.Unequip("Longsword"); err != nil {
+		t.Fatal(err)
+	}
+	strengthSources(t, c)
+	if got, want := c.GetCurrentHP(), full-HitPointsPerStrength; got != want {
+		t.Errorf("hit points %d after unequipping, want %d", got, want)
+	}
+
+	// Poisoned lowers strength by 2 while it lasts
+	if err := c.SetCondition(condition.NewCondition("Poisoned")); err != nil {
+		t.Fatal(err)
+	}
+	if got := strengthSources(t, c); got != base-2 {
+		t.Errorf("strength %d while poisoned, want %d", got, base-2)
+	}
+	if got, want := c.GetCurrentHP(), c.GetMaxHP(); got != want {
+		t.Errorf("hit points %d while poisoned, want the clamped max %d", got, want)
+	}
+	poisoned := c.GetCurrentHP()
+	if !c.RemoveCondition(condition.NewCondition("Poisoned")) {
+		t.Fatal("Poisoned was not active")
+	}
+	strengthSources(t, c)
+	if c.GetCurrentHP() != poisoned {
+		t.Errorf("hit points %d after the poison wore off, want them to stay at %d", c.GetCurrentHP(), poisoned)
+	}
+}
+
+func TestHealingWakesUnconscious(t *testing.T) {
+	c := NewDefaultCharacter("human", "Ann", "warrior")
+	if err := c.TakeDamage(c.GetMaxHP()); err != nil {
+		t.Fatal(err)
+	}
+	if !c.conditions.Has(ConditionUnconscious) {
+		t.Fatalf("conditions %s at 0 hit points, want Unconscious", c.conditions.String())
+	}
+	if err := c.Heal(5); err != nil {
+		t.Fatal(err)
+	}
+	if c.conditions.Has(ConditionUnconscious) || !c.conditions.Has(ConditionHealthy) {
+		t.Errorf("conditions %s after healing, want Healthy", c.conditions.String())
+	}
+}
+
+func TestPoisonDealsDamageEachTurn(t *testing.T) {
+	poisoned := condition.NewCondition("Poisoned")
+	c := NewDefaultCharacter("human", "Ann", "warrior")
+	c.AddTimedCondition(poisoned, 2)
+	start := c.GetCurrentHP()
+
+	c.TickConditions()
+	if got := c.GetCurrentHP(); got != start-1 {
+		t.Fatalf("hit points %d after a poisoned turn, want %d", got, start-1)
+	}
+	// Poisoned refreshes its duration instead of stacking
+	c.AddTimedCondition(poisoned, 3)
+	if got := c.GetConditions(); got.Remaining(poisoned) != 3 || got.Stacks(poisoned) != 1 {
+		t.Fatalf("%d turns and %d stacks after poisoning again, want 3 and 1", got.Remaining(poisoned), got.Stacks(poisoned))
+	}
+	for range 3 {
+		c.TickConditions()
+	}
+	if got := c.GetCurrentHP(); got != start-4 {
+		t.Errorf("hit points %d after the poison wore off, want %d", got, start-4)
+	}
+	c.TickConditions()
+	if got := c.GetCurrentHP(); got != start-4 {
+		t.Errorf("hit points %d once no longer poisoned, want %d", got, start-4)
+	}
+}
+
+func TestConditionDamageKnocksOut(t *testing.T) {
+	c := NewDefaultCharacter("human", "Ann", "warrior")
+	if err := c.TakeDamage(c.GetMaxHP() - 1); err != nil {
+		t.Fatal(err)
+	}
+	c.AddCondition(condition.NewCondition("Poisoned"))
+	c.TickConditions()
+	if !c.conditions.Has(ConditionUnconscious) || c.IsDead() {
+		t.Fatalf("conditions %s at %d hit points, want Unconscious", c.conditions.String(), c.GetCurrentHP())
+	}
+	c.TickConditions()
+}
+
+func TestSetConditionFollowsTransitions(t *testing.T) {
+	c := NewDefaultCharacter("human", "Ann", "warrior")
+	steps := []struct {
+		to      condition.Condition
+		wantErr bool
+	}{
+		{condition.NewCondition("Critical"), false},
+		{ConditionHealthy, true},
+		{condition.NewCondition("Injured"), false},
+		{ConditionHealthy, false},
+		{ConditionDead, false},
+		{condition.NewCondition("Injured"), true},
+	}
+	for _, step := range steps {
+		from := c.conditions.String()
+		err := c.SetCondition(step.to)
+		if (err != nil) != step.wantErr {
+			t.Fatalf("SetCondition from %s to %s: error %v, want error %v", from, step.to, err, step.wantErr)
+		}
+	}
+}
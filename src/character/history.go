@@ -0,0 +1,112 @@
+package character
+
+import (
+	"context"
+	"dnd-helper/src/abilities"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// SystemActor is recorded for changes made without a known actor
+const SystemActor = "system"
+
+// HistoryLimit is how many history entries a character keeps. Older entries
+// are dropped first
+var HistoryLimit = 100
+
+// HistoryEntry records a single change of a character
+type HistoryEntry struct {
+	Timestamp time.Time `json:"timestamp"`
+	Actor     string    `json:"actor"`
+	Field     string    `json:"field"`
+	Old       string    `json:"old"`
+	New       string    `json:"new"`
+}
+
+type actorKey struct{}
+
+// WithActor returns a context carrying the actor that changes are
+// attributed to, e.g. the authenticated user of a request
+func WithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// ActorFrom returns the actor stored by WithActor, or SystemActor
+func ActorFrom(ctx context.Context) string {
+	if actor, ok := ctx.Value(actorKey{}).(string); ok && actor != "" {
+		return actor
+	}
+	return SystemActor
+}
+
+// SetActor attributes the following changes to actor. The actor is not part
+// of the character's state, so copies made by Clone start without one and
+// record SystemActor
+func (c *Character) SetActor(actor string) {
+	c.actor = actor
+}
+
+// GetHistory returns up to limit of the most recent changes, oldest first.
+// A limit of 0 or less returns the whole history
+func (c *Character) GetHistory(limit int) []HistoryEntry {
+	start := 0
+	if limit > 0 && limit < len(c.history) {
+		start = len(c.history) - limit
+	}
+	return append([]HistoryEntry{}, c.history[start:]...)
+}
+
+// record appends a change to the history, dropping the oldest entries past
+// HistoryLimit
+func (c *Character) record(field string, old string, new string) {
+	if old == new {
+		return
+	}
+	actor := c.actor
+	if actor == "" {
+		actor = SystemActor
+	}
+	c.history = append(c.history, HistoryEntry{
+		Timestamp: time.Now().UTC(),
+		Actor:     actor,
+		Field:     field,
+		Old:       old,
+		New:       new,
+	})
+	if excess := len(c.history) - HistoryLimit; excess > 0 {
+		c.history = append([]HistoryEntry{}, c.history[excess:]...)
+	}
+}
+
+// recordAbilities records every ability that differs from before
+func (c *Character) recordAbilities(before abilities.Abilities) {
+	old := before.GetAllAbilities()
+	deltas := before.Diff(&c.abilities)
+	names := make([]string, 0, len(deltas))
+	for name, delta := range deltas {
+		if delta != 0 {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		c.record("abilities."+name, strconv.Itoa(old[name]), strconv.Itoa(old[name]+deltas[name]))
+	}
+}
+
+// recordItem records the change of the carried quantity of an item
+func (c *Character) recordItem(name string, before int) {
+	c.record("inventory."+name, strconv.Itoa(before), strconv.Itoa(c.itemQuantity(name)))
+}
+
+// itemQuantity returns the quantity of an item over all its stacks
+func (c *Character) itemQuantity(name string) int {
+	quantity := 0
+	for _, item := range c.inventory.GetAllItems() {
+		if item.GetName() == name {
+			quantity += item.GetQuantity()
+		}
+	}
+	return quantity
+}
@@ -0,0 +1,44 @@
+package character
+
+import (
+	"log/slog"
+	"math/rand"
+	"sort"
+
+	"dnd-helper/src/dice"
+)
+
+// RollInitiative rolls d20 + (agility - 5) with the effective agility and
+// stores the result as the character's initiative for the current encounter
+func (c *Character) RollInitiative(rng *rand.Rand) int {
+	natural, _ := dice.Expression{Count: 1, Sides: 20}.Roll(rng)
+	agility, _ := c.GetEffectiveAbility("agility")
+	c.initiative = natural + agility - 5
+	slog.Debug("Initiative rolled", "character", c.name, "initiative", c.initiative, "natural", natural)
+	return c.initiative
+}
+
+// GetInitiative returns the initiative of the current encounter, 0 before
+// the first roll
+func (c *Character) GetInitiative() int {
+	return c.initiative
+}
+
+// SortByInitiative orders characters by initiative, highest first. Ties go
+// to the higher agility, then the higher luck
+func SortByInitiative(characters []*Character) {
+	sort.SliceStable(characters, func(i, j int) bool {
+		a, b := characters[i], characters[j]
+		if a.initiative != b.initiative {
+			return a.initiative > b.initiative
+		}
+		agilityA, _ := a.GetEffectiveAbility("agility")
+		agilityB, _ := b.GetEffectiveAbility("agility")
+		if agilityA != agilityB {
+			return agilityA > agilityB
+		}
+		luckA, _ := a.GetEffectiveAbility("luck")
+		luckB, _ := b.GetEffectiveAbility("luck")
+		return luckA > luckB
+	})
+}
@@ -0,0 +1,54 @@
+package character
+
+import (
+	"dnd-helper/src/abilities"
+	"dnd-helper/src/condition"
+	"dnd-helper/src/inventory"
+	"math/rand"
+	"testing"
+)
+
+func TestRollInitiativeIsReproducible(t *testing.T) {
+	roll := func(seed int64) []int {
+		rng := rand.New(rand.NewSource(seed))
+		var rolls []int
+		for _, name := range []string{"Ann", "Bo", "Cy"} {
+			rolls = append(rolls, NewDefaultCharacter("human", name, "warrior").RollInitiative(rng))
+		}
+		return rolls
+	}
+	first, second := roll(42), roll(42)
+	for i := range first {
+		if first[i] != second[i] {
+			t.Fatalf("rolls %v and %v under the same seed", first, second)
+		}
+	}
+}
+
+func TestSortByInitiativeBreaksTies(t *testing.T) {
+	combatant := func(name string, agility, luck, initiative int) *Character {
+		t.Helper()
+		// Strength takes whatever is left of the budget
+		abs, err := abilities.NewAbilities(20-agility-luck, luck, 5, agility, 5, 5)
+		if err != nil {
+			t.Fatal(err)
+		}
+		c := newCharacter("human", name, "warrior", abs, *inventory.NewInventory(), condition.NewCondition("Healthy"))
+		c.initiative = initiative
+		return c
+	}
+	characters := []*Character{
+		combatant("Slow", 5, 7, 12),
+		combatant("Lucky", 6, 8, 12),
+		combatant("Quick", 7, 5, 12),
+		combatant("Unlucky", 6, 5, 12),
+		combatant("First", 5, 5, 15),
+	}
+	SortByInitiative(characters)
+	want := []string{"First", "Quick", "Lucky", "Unlucky", "Slow"}
+	for i, c := range characters {
+		if c.GetName() != want[i] {
+			t.Fatalf("position %d is %s, want %s", i, c.GetName(), want[i])
+		}
+	}
+}
@@ -0,0 +1,247 @@
+package character
+
+import (
+	"dnd-helper/src/abilities"
+	"dnd-helper/src/condition"
+	"dnd-helper/src/inventory"
+	"encoding/json"
+	"fmt"
+)
+
+// conditionJSON is an active condition with its remaining turns, 0 for
+// indefinite conditions, and its stacks, 0 for a single instance
+type conditionJSON struct {
+	Name   string `json:"name"`
+	Turns  int    `json:"turns,omitempty"`
+	Stacks int    `json:"stacks,omitempty"`
+}
+
+// characterJSON is the canonical JSON document of a character. Class is the
+// primary class and Classes every class with its levels, primary first. Condition,
+// EffectiveAbilities, Modifiers, MaxManaPoints, CarryCapacity, Encumbrance,
+// ArmorClass, AbilityBreakdown, IsCompanion and Hash are derived and ignored
+// when unmarshaling. Companions are nested documents of the same form. Gold
+// is the gold of the inventory, kept at the top level for older clients
+type characterJSON struct {
+	ID                 string                  `json:"id"`
+	Name               string                  `json:"name"`
+	IsNPC              bool                    `json:"isNPC"`
+	IsCompanion        bool                    `json:"isCompanion"`
+	Race               string                  `json:"race"`
+	Class              string                  `json:"class"`
+	Classes            []ClassLevel            `json:"classes"`
+	Abilities          abilities.Abilities     `json:"abilities"`
+	EffectiveAbilities abilities.AbilityValues `json:"effectiveAbilities"`
+	Modifiers          abilities.AbilityValues `json:"modifiers"`
+	ManaPoints         int                     `json:"manaPoints"`
+	MaxManaPoints      int                     `json:"maxManaPoints"`
+	ManaBonus          int                     `json:"manaBonus"`
+	Level              int                     `json:"level"`
+	Experience         int                     `json:"experience"`
+	Gold               int                     `json:"gold"`
+	CarryCapacity      int                     `json:"carryCapacity"`
+	Encumbrance        Encumbrance             `json:"encumbrance"`
+	ArmorClass         int                     `json:"armorClass"`
+	HitPoints          int                     `json:"hitPoints"`
+	MaxHitPoints       int                     `json:"maxHitPoints"`
+	Condition          string                  `json:"condition"`
+	Conditions         []conditionJSON         `json:"conditions"`
+	Equipment          map[string]string       `json:"equipment"`
+	RaceModifiers      map[string]int          `json:"raceModifiers"`
+	AbilityBreakdown   map[string]string       `json:"abilityBreakdown"`
+	Spellbook          []string                `json:"spellbook"`
+	Buffs              map[string]int          `json:"buffs"`
+	Effects            []Effect                `json:"effects"`
+	Initiative         int                     `json:"initiative"`
+	Alignment          string                  `json:"alignment"`
+	Biography          string                  `json:"biography"`
+	Tags               []string                `json:"tags"`
+	Companions         []*Character            `json:"companions"`
+	ShareLoad          bool                    `json:"countCompanionWeight"`
+	Relationships      []Relationship          `json:"relationships"`
+	Inventory          inventory.Inventory     `json:"inventory"`
+	Hash               string                  `json:"hash"`
+}
+
+// legacySlots maps the equipment slots of documents from before item slots
+// to the current ones
+var legacySlots = map[string]string{"weapon": SlotMainHand, "armor": SlotChest, "trinket": SlotRing1}
+
+// MarshalJSON encodes the character as its canonical document
+func (c *Character) MarshalJSON() ([]byte, error) {
+	effective := c.GetEffectiveAbilities()
+	doc := characterJSON{
+		ID:                 c.id,
+		Name:               c.name,
+		IsNPC:              c.isNPC,
+		IsCompanion:        c.owned,
+		Race:               c.race,
+		Class:              c.class,
+		Classes:            c.Classes(),
+		Abilities:          c.abilities,
+		EffectiveAbilities: effective.OrderedAbilities(),
+		Modifiers:          effective.OrderedModifiers(),
+		ManaPoints:         c.manaPoints,
+		MaxManaPoints:      c.GetMaxManaPoints(),
+		ManaBonus:          c.manaBonus,
+		Level:              c.level,
+		Experience:         c.experience,
+		Gold:               c.inventory.GetGold(),
+		CarryCapacity:      c.GetCarryCapacity(),
+		Encumbrance:        c.GetEncumbrance(),
+		ArmorClass:         c.ArmorClass(),
+		HitPoints:          c.hitPoints,
+		MaxHitPoints:       c.GetMaxHitPoints(),
+		Condition:          c.conditions.String(),
+		Equipment:          c.GetEquipment(),
+		RaceModifiers:      c.GetRaceModifiers(),
+		AbilityBreakdown:   c.AbilityBreakdown(),
+		Spellbook:          c.GetSpellbook(),
+		Buffs:              c.GetBuffs(),
+		Effects:            c.GetEffects(),
+		Initiative:         c.initiative,
+		Alignment:          c.alignment,
+		Biography:          c.biography,
+		Tags:               c.GetTags(),
+		Companions:         c.Companions(),
+		ShareLoad:          c.shareLoad,
+		Relationships:      c.Relationships(),
+		Hash:               c.Hash(),
+	}
+	doc.Conditions = c.conditionDocs()
+	doc.Inventory = *c.inventory.Clone()
+	return json.Marshal(doc)
+}
+
+// conditionDocs returns the active conditions with their turns and stacks
+func (c *Character) conditionDocs() []conditionJSON {
+	docs := []conditionJSON{}
+	for _, cond := range c.conditions.List() {
+		condDoc := conditionJSON{Name: cond.String(), Turns: c.conditions.Remaining(cond)}
+		if stacks := c.conditions.Stacks(cond); stacks > 1 {
+			condDoc.Stacks = stacks
+		}
+		docs = append(docs, condDoc)
+	}
+	return docs
+}
+
+// UnmarshalJSON rebuilds a character from its canonical document. Abilities
+// and items go through the same validation as NewAbilities and NewItem, and
+// the result must pass ValidateCharacter. Derived fields are recomputed and
+// racial bonuses are not applied again
+func (c *Character) UnmarshalJSON(b []byte) error {
+	var doc characterJSON
+	if err := json.Unmarshal(b, &doc); err != nil {
+		return err
+	}
+
+	var first condition.Condition
+	if len(doc.Conditions) > 0 {
+		first = condition.NewCondition(doc.Conditions[0].Name)
+	}
+	restored := newCharacter(doc.Race, doc.Name, doc.Class, doc.Abilities, doc.Inventory, first)
+	for _, condDoc := range doc.Conditions {
+		restored.conditions.AddTimed(condition.NewCondition(condDoc.Name), condDoc.Turns)
+		restored.conditions.SetStacks(condition.NewCondition(condDoc.Name), condDoc.Stacks)
+	}
+	if err := restored.ValidateCharacter(); err != nil {
+		return err
+	}
+
+	if doc.ID != "" {
+		restored.id = doc.ID
+	}
+	if doc.Level < StartingLevel {
+		return fmt.Errorf("level %d must be at least %d", doc.Level, StartingLevel)
+	}
+	if doc.Experience < 0 || doc.Gold < 0 || doc.ManaPoints < 0 || doc.ManaBonus < 0 {
+		return fmt.Errorf("experience, gold and mana cannot be negative")
+	}
+	restored.level = doc.Level
+	if err := restored.restoreMulticlass(doc.Classes); err != nil {
+		return err
+	}
+	restored.experience = doc.Experience
+	// Gold moved into the inventory, older documents only have it at the top
+	if restored.inventory.GetGold() == 0 {
+		restored.inventory.AddGold(doc.Gold)
+	}
+	restored.manaBonus = doc.ManaBonus
+	restored.initiative = doc.Initiative
+	restored.isNPC = doc.IsNPC
+	restored.raceMods = doc.RaceModifiers
+	if err := restored.SetAlignment(doc.Alignment); err != nil {
+		return err
+	}
+	if err := restored.SetBiography(doc.Biography); err != nil {
+		return err
+	}
+	if err := restored.SetTags(doc.Tags); err != nil {
+		return err
+	}
+	for _, companion := range doc.Companions {
+		if err := restored.AddCompanion(companion); err != nil {
+			return err
+		}
+	}
+	restored.shareLoad = doc.ShareLoad
+	for _, relation := range doc.Relationships {
+		if err := restored.AddRelationship(relation.Target, relation.Kind); err != nil {
+			return err
+		}
+	}
+
+	for _, spellName := range doc.Spellbook {
+		if err := restored.LearnSpell(spellName); err != nil {
+			return err
+		}
+	}
+	for name, amount := range doc.Buffs {
+		if _, err := restored.abilities.CurrentValue(name); err != nil {
+			return fmt.Errorf("buff: %w", err)
+		}
+		if amount != 0 {
+			if restored.buffs == nil {
+				restored.buffs = map[string]int{}
+			}
+			restored.buffs[name] = amount
+		}
+	}
+
+	for _, effect := range doc.Effects {
+		if err := restored.ApplyEffect(effect); err != nil {
+			return err
+		}
+	}
+
+	for slot, itemName := range doc.Equipment {
+		if migrated, ok := legacySlots[slot]; ok {
+			// The item predates item slots, so it takes the slot it was in
+			slot = migrated
+			if item := restored.inventory.GetItem(itemName); item != nil && item.GetSlot() == inventory.SlotNone {
+				item.SetSlot(itemSlotOf(slot))
+			}
+		}
+		if err := restored.equip(itemName, slot, false); err != nil {
+			return err
+		}
+	}
+	// Effects and equipment clamp the hit points and mana while they are
+	// restored, so the decoded values are only set and checked once the
+	// maximums are final. Max hit points follow from strength, so only the
+	// current value is kept
+	if doc.HitPoints < 0 || doc.HitPoints > restored.GetMaxHitPoints() {
+		return fmt.Errorf("hit points %d must be in range [0, %d]", doc.HitPoints, restored.GetMaxHitPoints())
+	}
+	restored.hitPoints = doc.HitPoints
+	if doc.ManaPoints > restored.GetMaxManaPoints() {
+		return fmt.Errorf("mana points %d exceed the max of %d", doc.ManaPoints, restored.GetMaxManaPoints())
+	}
+	restored.manaPoints = doc.ManaPoints
+
+	// Restoring is not a change, so nothing above belongs in the history
+	restored.history = nil
+	*c = *restored
+	return nil
+}
@@ -0,0 +1,169 @@
+package character
+
+import (
+	"bytes"
+	"dnd-helper/src/abilities"
+	"dnd-helper/src/condition"
+	"dnd-helper/src/inventory"
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+// marshalIndent encodes c the way the golden files are written
+func marshalIndent(t *testing.T, c *Character) []byte {
+	t.Helper()
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	return append(data, '\n')
+}
+
+// TestCharacterJSONGolden decodes testdata/character.json and compares its
+// canonical document with testdata/character.golden byte for byte. Run with
+// -update after an intended change to the document
+func TestCharacterJSONGolden(t *testing.T) {
+	input, err := os.ReadFile(filepath.Join("testdata", "character.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var c Character
+	if err := json.Unmarshal(input, &c); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	got := marshalIndent(t, &c)
+
+	golden := filepath.Join("testdata", "character.golden")
+	if *update {
+		if err := os.WriteFile(golden, got, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want, err := os.ReadFile(golden)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Fatalf("document differs from %s, run go test -update after an intended change\ngot:\n%s", golden, got)
+	}
+
+	// The golden document is canonical, so it must survive a round trip unchanged
+	var decoded Character
+	if err := json.Unmarshal(want, &decoded); err != nil {
+		t.Fatalf("unmarshal golden: %v", err)
+	}
+	if again := marshalIndent(t, &decoded); !bytes.Equal(again, want) {
+		t.Errorf("golden document changed after a round trip:\n%s", again)
+	}
+}
+
+func TestCharacterUnmarshalRejectsInvalid(t *testing.T) {
+	input, err := os.ReadFile(filepath.Join("testdata", "character.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name, old, new string
+	}{
+		{"empty name", `"name": "Lira"`, `"name": ""`},
+		{"ability over budget", `"strength": 5, "luck": 5`, `"strength": 9, "luck": 5`},
+		{"level 0", `"level": 1,`, `"level": 0,`},
+		{"hit points above max", `"hitPoints": 45`, `"hitPoints": 500`},
+		{"mana above max", `"manaPoints": 450`, `"manaPoints": 9000`},
+		{"unknown spell", `["Light"]`, `["Nope"]`},
+		{"equipped item not carried", `{"mainHand": "Staff"}`, `{"mainHand": "Sword"}`},
+		{"invalid item", `"quantity": 2, "condition": "Pristine", "description": "A day`, `"quantity": -2, "condition": "Pristine", "description": "A day`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if !strings.Contains(string(input), tt.old) {
+				t.Fatalf("fixture does not contain %s", tt.old)
+			}
+			doc := strings.Replace(string(input), tt.old, tt.new, 1)
+			var c Character
+			if err := json.Unmarshal([]byte(doc), &c); err == nil {
+				t.Errorf("unmarshal accepted the document")
+			}
+		})
+	}
+}
+
+func TestConditionStacksRoundTrip(t *testing.T) {
+	bleeding := condition.NewCondition("Bleeding")
+	err := condition.DefaultRegistry.Register(condition.Definition{Name: bleeding.String(), Severity: 1,
+		Category: condition.CategoryCharacter, Stacking: condition.StackAdd, DamagePerTurn: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := NewDefaultCharacter("human", "Ann", "warrior")
+	c.AddTimedCondition(bleeding, 3)
+	c.AddTimedCondition(bleeding, 2)
+
+	data, err := json.Marshal(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded Character
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	conds := decoded.GetConditions()
+	if conds.Stacks(bleeding) != 2 || conds.Remaining(bleeding) != 3 {
+		t.Fatalf("%d stacks and %d turns after a round trip, want 2 and 3", conds.Stacks(bleeding), conds.Remaining(bleeding))
+	}
+	start := decoded.GetCurrentHP()
+	decoded.TickConditions()
+	if got := decoded.GetCurrentHP(); got != start-2 {
+		t.Errorf("hit points %d after a turn with 2 stacks, want %d", got, start-2)
+	}
+}
+
+// TestClampedRacialBonusRoundTrip covers an orc created with strength 10:
+// the strength bonus is clamped away while the intelligence penalty still
+// applies, so the budget shrinks below the starting one
+func TestClampedRacialBonusRoundTrip(t *testing.T) {
+	abs, err := abilities.NewAbilities(10, 5, 5, 5, 5, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := NewCharacter("orc", "Grusk", "warrior", abs, *inventory.NewInventory(), condition.NewCondition("Healthy"))
+	orig := c.GetAbilities()
+	if orig.GetStrength() != 10 || orig.GetIntelligence() != 4 {
+		t.Fatalf("strength %d and intelligence %d, want 10 and 4", orig.GetStrength(), orig.GetIntelligence())
+	}
+
+	data, err := json.Marshal(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded Character
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("json round trip: %v", err)
+	}
+	if again := decoded.GetAbilities(); again.TotalBudget() != orig.TotalBudget() {
+		t.Errorf("budget %d after the json round trip, want %d", again.TotalBudget(), orig.TotalBudget())
+	}
+
+	text, err := orig.MarshalText()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var fromText abilities.Abilities
+	if err := fromText.UnmarshalText(text); err != nil {
+		t.Fatalf("text round trip of %s: %v", text, err)
+	}
+
+	doc, err := orig.ToYAML()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := abilities.AbilitiesFromYAML(doc); err != nil {
+		t.Fatalf("yaml round trip: %v\n%s", err, doc)
+	}
+}
@@ -0,0 +1,74 @@
+package character
+
+import (
+	"fmt"
+	"log/slog"
+)
+
+const (
+	// StartingLevel is the level of a newly created character
+	StartingLevel = 1
+	// PointsPerLevel is the number of ability points granted on each level up
+	PointsPerLevel = 1
+	// ManaPerLevel is the max mana gained on each level up
+	ManaPerLevel = 20
+)
+
+// ExperienceForLevel returns the total experience needed to reach level.
+// Level N requires N*1000 experience, so level 2 needs 2000 and level 3 needs
+// 3000. It can be replaced at startup for homebrew progression
+var ExperienceForLevel = func(level int) int {
+	if level <= StartingLevel {
+		return 0
+	}
+	return level * 1000
+}
+
+func (c *Character) GetLevel() int {
+	return c.level
+}
+
+func (c *Character) GetExperience() int {
+	return c.experience
+}
+
+// ExperienceToNextLevel returns how much experience is still needed to reach
+// the next level
+func (c *Character) ExperienceToNextLevel() int {
+	return ExperienceForLevel(c.level+1) - c.experience
+}
+
+// AddXP adds n to the character's total experience and levels the character
+// up for every threshold of ExperienceForLevel crossed, so a single large
+// award can grant several levels. Each level up grants PointsPerLevel ability
+// points into the points pool and ManaPerLevel max mana
+func (c *Character) AddXP(n int) (leveledUp bool, err error) {
+	if n <= 0 {
+		return false, fmt.Errorf("experience amount %d must be positive", n)
+	}
+	c.experience += n
+	slog.Debug("Experience gained", "character", c.name, "amount", n, "experience", c.experience)
+
+	for c.experience >= ExperienceForLevel(c.level+1) {
+		c.levelUp()
+		leveledUp = true
+	}
+	return leveledUp, nil
+}
+
+// levelUp raises the level by one, granting PointsPerLevel ability points
+// and ManaPerLevel max mana
+func (c *Character) levelUp() {
+	c.level++
+	c.abilities.GrantPoints(PointsPerLevel)
+	c.manaBonus += ManaPerLevel
+	c.manaPoints += ManaPerLevel
+	slog.Info("Level up", "character", c.name, "level", c.level, "abilityPoints", PointsPerLevel, "maxMana", ManaPerLevel)
+}
+
+// AddExperience is AddXP for callers that do not need the outcome
+func (c *Character) AddExperience(xp int) {
+	if _, err := c.AddXP(xp); err != nil {
+		slog.Warn("Experience not added", "character", c.name, "error", err)
+	}
+}
@@ -0,0 +1,80 @@
+package character
+
+import (
+	"fmt"
+	"log/slog"
+)
+
+// ManaPerIntelligence is the max mana granted by each point of intelligence
+// for classes without their own scaling
+const ManaPerIntelligence = 50
+
+// ManaForClass returns the max mana a class gets from intelligence, before
+// level ups. Unknown classes use ManaPerIntelligence
+func ManaForClass(class string, intelligence int) int {
+	perPoint := ManaPerIntelligence
+	if registered, ok := LookupClass(class); ok && registered.ManaPerIntelligence > 0 {
+		perPoint = registered.ManaPerIntelligence
+	}
+	return intelligence * perPoint
+}
+
+// InsufficientManaError is returned when a character cannot pay a mana cost
+type InsufficientManaError struct {
+	Have int
+	Need int
+}
+
+func (e *InsufficientManaError) Error() string {
+	return fmt.Sprintf("insufficient mana: have %d, need %d", e.Have, e.Need)
+}
+
+// GetMaxManaPoints returns the max mana, derived from the classes and the
+// effective intelligence (so item bonuses count) plus the mana gained from
+// level ups. It is the sum over the classes of ManaForClass times the levels
+// in the class, so every level adds the mana of its class, e.g. a mage 2 /
+// warrior 1 gets twice the mage mana plus the warrior mana
+func (c *Character) GetMaxManaPoints() int {
+	effective := c.GetEffectiveAbilities()
+	intelligence, _ := effective.CurrentValue("intelligence")
+	classes := c.Classes()
+	if classes[0].Level < 1 {
+		// Characters built without a level count as starting characters
+		return ManaForClass(c.class, intelligence) + c.manaBonus
+	}
+	sum := 0
+	for _, class := range classes {
+		sum += ManaForClass(class.Class, intelligence) * class.Level
+	}
+	return sum + c.manaBonus
+}
+
+// SpendMana pays cost mana. Spending more than the current mana fails with
+// an *InsufficientManaError and leaves the mana unchanged
+func (c *Character) SpendMana(cost int) error {
+	if cost < 0 {
+		return fmt.Errorf("mana cost %d cannot be negative", cost)
+	}
+	if c.manaPoints < cost {
+		return &InsufficientManaError{Have: c.manaPoints, Need: cost}
+	}
+	c.manaPoints -= cost
+	slog.Debug("Mana spent", "character", c.name, "cost", cost, "mana", c.manaPoints)
+	return nil
+}
+
+// RestoreMana adds amount mana, clamped at GetMaxManaPoints
+func (c *Character) RestoreMana(amount int) {
+	if amount < 0 {
+		slog.Debug("Mana not restored, amount is negative", "character", c.name, "amount", amount)
+		return
+	}
+	c.manaPoints = min(c.manaPoints+amount, c.GetMaxManaPoints())
+	slog.Debug("Mana restored", "character", c.name, "mana", c.manaPoints, "maxMana", c.GetMaxManaPoints())
+}
+
+// RecalculateManaPoints lowers the current mana after the max mana dropped,
+// e.g. when an intelligence item is unequipped or the class changes
+func (c *Character) RecalculateManaPoints() {
+	c.manaPoints = min(c.manaPoints, c.GetMaxManaPoints())
+}
@@ -0,0 +1,79 @@
+package character
+
+import (
+	"testing"
+)
+
+func TestGetMaxManaPointsMulticlass(t *testing.T) {
+	tests := []struct {
+		name    string
+		classes []ClassLevel // added with AddClass after creating a mage
+		// want is the max mana for a given intelligence
+		want func(intelligence int) int
+	}{
+		{"single class", nil, func(i int) int { return 50 * i }},
+		{"single class leveled", []ClassLevel{{"mage", 3}}, func(i int) int { return 4*50*i + 3*ManaPerLevel }},
+		{"one warrior level", []ClassLevel{{"warrior", 1}}, func(i int) int { return 50*i + 20*i + ManaPerLevel }},
+		{"mage 2 / warrior 1", []ClassLevel{{"mage", 1}, {"warrior", 1}}, func(i int) int { return 2*50*i + 20*i + 2*ManaPerLevel }},
+		{"mage 1 / warrior 1 / rogue 2", []ClassLevel{{"warrior", 1}, {"rogue", 2}}, func(i int) int { return 50*i + 20*i + 2*20*i + 3*ManaPerLevel }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewDefaultCharacter("human", "Ann", "mage")
+			for _, class := range tt.classes {
+				if err := c.AddClass(class.Class, class.Level); err != nil {
+					t.Fatal(err)
+				}
+			}
+			effective := c.GetEffectiveAbilities()
+			intelligence, _ := effective.CurrentValue("intelligence")
+			if got, want := c.GetMaxManaPoints(), tt.want(intelligence); got != want {
+				t.Errorf("max mana %d, want %d for %s", got, want, c.classSummary())
+			}
+		})
+	}
+}
+
+func TestMaxManaGrowsWithPrimaryLevels(t *testing.T) {
+	c := NewDefaultCharacter("human", "Ann", "mage")
+	if err := c.AddClass("warrior", 1); err != nil {
+		t.Fatal(err)
+	}
+	before := c.GetMaxManaPoints()
+	if err := c.AddClass("mage", 1); err != nil {
+		t.Fatal(err)
+	}
+	if got := c.GetMaxManaPoints(); got <= before {
+		t.Errorf("max mana %d after a mage level, want more than %d", got, before)
+	}
+}
+
+func TestSingleClassMatchesManaForClass(t *testing.T) {
+	for _, class := range []string{"warrior", "mage", "rogue", "ranger", "homebrew"} {
+		c := NewDefaultCharacter("human", "Ann", class)
+		effective := c.GetEffectiveAbilities()
+		intelligence, _ := effective.CurrentValue("intelligence")
+		if got, want := c.GetMaxManaPoints(), ManaForClass(class, intelligence); got != want {
+			t.Errorf("%s: max mana %d, want ManaForClass %d", class, got, want)
+		}
+	}
+}
+
+func TestModifyIntelligenceRaisesMaxMana(t *testing.T) {
+	for _, class := range []string{"mage", "warrior"} {
+		c := NewDefaultCharacter("human", "Ann", class)
+		if err := c.AbilitiesRef().GrantPoints(1); err != nil {
+			t.Fatal(err)
+		}
+		before := c.GetMaxManaPoints()
+		if err := c.ModifyAbility("intelligence", 1); err != nil {
+			t.Fatal(err)
+		}
+		if got, want := c.GetMaxManaPoints()-before, ManaForClass(class, 1); got != want {
+			t.Errorf("%s: +1 intelligence raised max mana by %d, want %d", class, got, want)
+		}
+		if class == "mage" && c.GetMaxManaPoints()-before != 50 {
+			t.Errorf("mage: +1 intelligence raised max mana by %d, want 50", c.GetMaxManaPoints()-before)
+		}
+	}
+}
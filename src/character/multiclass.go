@@ -0,0 +1,104 @@
+package character
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+)
+
+// ClassLevel is one class of a character with the levels taken in it
+type ClassLevel struct {
+	Class string `json:"class"`
+	Level int    `json:"level"`
+}
+
+// Classes returns the primary class followed by the other classes in the
+// order they were taken. The levels add up to GetLevel
+func (c *Character) Classes() []ClassLevel {
+	primary := c.level
+	for _, other := range c.multiclass {
+		primary -= other.Level
+	}
+	return append([]ClassLevel{{Class: c.class, Level: primary}}, c.multiclass...)
+}
+
+// AddClass takes levels levels in class, each with the rewards of a level
+// up. Adding the primary class raises its level; any other class is added
+// after the primary one, or raised if already taken. Class kits and ability
+// presets only ever come from the primary class
+func (c *Character) AddClass(class string, levels int) error {
+	if class == "" {
+		return fmt.Errorf("class cannot be empty")
+	}
+	if levels < 1 {
+		return fmt.Errorf("levels %d must be at least 1", levels)
+	}
+
+	before := c.classSummary()
+	if !strings.EqualFold(class, c.class) {
+		i := c.multiclassIndex(class)
+		if i < 0 {
+			c.multiclass = append(c.multiclass, ClassLevel{Class: class})
+			i = len(c.multiclass) - 1
+		}
+		c.multiclass[i].Level += levels
+	}
+	for range levels {
+		c.levelUp()
+	}
+	c.RecalculateDerivedStats()
+	c.record("classes", before, c.classSummary())
+	slog.Info("Class levels added", "character", c.name, "class", class, "levels", levels, "level", c.level)
+	return nil
+}
+
+// classSummary returns the class for single-class characters and every
+// class with its levels otherwise, e.g. "warrior 3 / mage 2"
+func (c *Character) classSummary() string {
+	if len(c.multiclass) == 0 {
+		return c.class
+	}
+	parts := []string{}
+	for _, class := range c.Classes() {
+		parts = append(parts, fmt.Sprintf("%s %d", class.Class, class.Level))
+	}
+	return strings.Join(parts, " / ")
+}
+
+// multiclassIndex returns the position of a non-primary class, or -1
+func (c *Character) multiclassIndex(class string) int {
+	for i, other := range c.multiclass {
+		if strings.EqualFold(other.Class, class) {
+			return i
+		}
+	}
+	return -1
+}
+
+// restoreMulticlass sets the non-primary classes of a decoded character,
+// whose level already includes them
+func (c *Character) restoreMulticlass(classes []ClassLevel) error {
+	if len(classes) == 0 {
+		return nil
+	}
+	if !strings.EqualFold(classes[0].Class, c.class) {
+		return fmt.Errorf("first class %s must be the primary class %s", classes[0].Class, c.class)
+	}
+	total := 0
+	for _, cl := range classes {
+		if cl.Class == "" || cl.Level < 1 {
+			return fmt.Errorf("class %q must have a name and a level of at least 1", cl.Class)
+		}
+		total += cl.Level
+	}
+	if total != c.level {
+		return fmt.Errorf("class levels add up to %d, but the character is level %d", total, c.level)
+	}
+	for _, cl := range classes[1:] {
+		if strings.EqualFold(cl.Class, c.class) || c.multiclassIndex(cl.Class) >= 0 {
+			return fmt.Errorf("class %s is listed twice", cl.Class)
+		}
+		c.multiclass = append(c.multiclass, cl)
+	}
+	return nil
+}
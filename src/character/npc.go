@@ -0,0 +1,102 @@
+package character
+
+import (
+	"dnd-helper/src/abilities"
+	"dnd-helper/src/condition"
+	"dnd-helper/src/inventory"
+	"log/slog"
+	"math/rand"
+	"sort"
+)
+
+// npcNames are the built-in NPC names per race. Races without a list use
+// the "" entry
+var npcNames = map[string][]string{
+	"":       {"Alden", "Brenna", "Corin", "Dara", "Edric", "Fenna", "Garrick", "Hilde"},
+	"human":  {"Aldous", "Beatrix", "Cedric", "Marta", "Roland", "Sybil", "Tobias", "Wilma"},
+	"elf":    {"Aerendil", "Caelynn", "Elarion", "Faelar", "Lirael", "Naivara", "Sylvar", "Thalion"},
+	"dwarf":  {"Bofrek", "Dagna", "Durin", "Gunnloda", "Helja", "Kildrak", "Torbera", "Vondal"},
+	"orc":    {"Dench", "Grusk", "Kansif", "Mhurren", "Ront", "Shautha", "Vola", "Yevelda"},
+	"goblin": {"Blik", "Gnatch", "Krib", "Nix", "Snag", "Tizzle", "Vrek", "Zook"},
+}
+
+// MaxNPCItems is the most item stacks a generated NPC carries
+const MaxNPCItems = 3
+
+// NPCOptions narrows down what NewRandomNPC generates. Empty fields are
+// picked at random
+type NPCOptions struct {
+	Race  string `json:"race,omitempty"`
+	Class string `json:"class,omitempty"`
+}
+
+// NewRandomNPC generates a Healthy NPC from the registered races and classes
+// with valid random abilities, up to MaxNPCItems items taken from the class
+// starting kits and a name from the list of its race. The same rng state
+// always generates the same NPC
+func NewRandomNPC(rng *rand.Rand, opts NPCOptions) *Character {
+	race := opts.Race
+	if race == "" {
+		registered := Races()
+		race = registered[rng.Intn(len(registered))].Name
+	}
+	class := opts.Class
+	if class == "" {
+		registered := Classes()
+		class = registered[rng.Intn(len(registered))].Name
+	}
+
+	names, ok := npcNames[race]
+	if !ok {
+		names = npcNames[""]
+	}
+	name := names[rng.Intn(len(names))]
+
+	// Any class kit item fits any NPC, so the kits double as the item pool
+	var pool []inventory.Item
+	for _, registered := range Classes() {
+		items, _ := ClassKit(registered.Name)
+		pool = append(pool, items...)
+	}
+	inv := inventory.NewInventory()
+	for n := rng.Intn(MaxNPCItems + 1); n > 0 && len(pool) > 0; n-- {
+		i := rng.Intn(len(pool))
+		inv.AddItem(pool[i])
+		pool = append(pool[:i], pool[i+1:]...)
+	}
+
+	c := NewCharacter(race, name, class, randomAbilitiesFor(rng, race), *inv, condition.NewCondition("Healthy"))
+	c.isNPC = true
+	slog.Info("NPC generated", "character", c.name, "race", race, "class", class)
+	return c
+}
+
+// IsNPC checks if the character was generated as an NPC
+func (c *Character) IsNPC() bool {
+	return c.isNPC
+}
+
+// randomAbilitiesFor spends the point budget at random while keeping every
+// ability within [MinAbilityValue, MaxAbilityValue] and the racial limits
+func randomAbilitiesFor(rng *rand.Rand, race string) abilities.Abilities {
+	abs := abilities.NewDefaultAbilities()
+
+	// Move the defaults into the racial limits first
+	limits := LimitsForRace(race)
+	limited := make([]string, 0, len(limits))
+	for name := range limits {
+		limited = append(limited, name)
+	}
+	sort.Strings(limited)
+	for _, name := range limited {
+		value, _ := abs.CurrentValue(name)
+		_ = abs.SetAbility(name, min(max(value, limits[name].Min), limits[name].Max))
+	}
+	if err := ApplyRaceLimits(&abs, race); err != nil {
+		slog.Warn("NPC abilities without racial limits", "race", race, "error", err)
+		return abilities.NewDefaultAbilities()
+	}
+
+	abs.Randomize(rng)
+	return abs
+}
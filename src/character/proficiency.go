@@ -0,0 +1,74 @@
+package character
+
+import (
+	"dnd-helper/src/rules"
+	"math/rand"
+)
+
+// ProficiencyStep grants Bonus from MinLevel on
+type ProficiencyStep struct {
+	MinLevel int `json:"minLevel"`
+	Bonus    int `json:"bonus"`
+}
+
+// ProficiencyTable is the proficiency bonus by level, sorted by MinLevel: +1
+// at levels 1-4, +2 at 5-8 and +3 from 9 on. It can be replaced at startup
+// for homebrew scaling
+var ProficiencyTable = []ProficiencyStep{
+	{MinLevel: 1, Bonus: 1},
+	{MinLevel: 5, Bonus: 2},
+	{MinLevel: 9, Bonus: 3},
+}
+
+// ProficiencyBonusFor returns the bonus of the last step of
+// ProficiencyTable reached at level, 0 below the first step
+func ProficiencyBonusFor(level int) int {
+	bonus := 0
+	for _, step := range ProficiencyTable {
+		if level < step.MinLevel {
+			break
+		}
+		bonus = step.Bonus
+	}
+	return bonus
+}
+
+// ProficiencyBonus returns the proficiency bonus of the character's level
+func (c *Character) ProficiencyBonus() int {
+	return ProficiencyBonusFor(c.level)
+}
+
+// Proficiencies returns the abilities the character's class is proficient in
+func (c *Character) Proficiencies() []string {
+	class, ok := LookupClass(c.class)
+	if !ok {
+		return nil
+	}
+	return append([]string{}, class.Proficiencies...)
+}
+
+// IsProficient checks if the character's class is proficient in ability
+func (c *Character) IsProficient(ability string) bool {
+	for _, proficiency := range c.Proficiencies() {
+		if proficiency == ability {
+			return true
+		}
+	}
+	return false
+}
+
+// proficiencyFor returns the proficiency bonus for checks of ability, 0 if
+// the character is not proficient in it
+func (c *Character) proficiencyFor(ability string) int {
+	if !c.IsProficient(ability) {
+		return 0
+	}
+	return c.ProficiencyBonus()
+}
+
+// SkillCheck rolls a check of ability against dc with the effective
+// abilities, adding the proficiency bonus if the character is proficient
+func (c *Character) SkillCheck(ruleset *rules.Ruleset, ability string, dc int, rng *rand.Rand) (rules.SkillCheckResult, error) {
+	effective := c.GetEffectiveAbilities()
+	return ruleset.SkillCheckWithProficiency(&effective, ability, c.proficiencyFor(ability), dc, rng)
+}
@@ -0,0 +1,83 @@
+package character
+
+import (
+	"dnd-helper/src/rules"
+	"math/rand"
+	"testing"
+)
+
+func TestProficiencyBonusFor(t *testing.T) {
+	tests := []struct{ level, want int }{
+		{0, 0}, {1, 1}, {4, 1}, {5, 2}, {8, 2}, {9, 3}, {20, 3},
+	}
+	for _, tt := range tests {
+		if got := ProficiencyBonusFor(tt.level); got != tt.want {
+			t.Errorf("level %d: bonus %d, want %d", tt.level, got, tt.want)
+		}
+	}
+}
+
+// characterAtLevel returns a warrior leveled up to level
+func characterAtLevel(t *testing.T, level int) *Character {
+	t.Helper()
+	c := NewDefaultCharacter("human", "Ann", "warrior")
+	if level > StartingLevel {
+		if err := c.AddClass("warrior", level-StartingLevel); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if c.GetLevel() != level {
+		t.Fatalf("level %d, want %d", c.GetLevel(), level)
+	}
+	return c
+}
+
+func TestProficiencyBoundary(t *testing.T) {
+	ruleset := rules.DefaultRuleset()
+	for _, tt := range []struct{ level, want int }{{4, 1}, {5, 2}} {
+		c := characterAtLevel(t, tt.level)
+		if got := c.ProficiencyBonus(); got != tt.want {
+			t.Errorf("level %d: ProficiencyBonus %d, want %d", tt.level, got, tt.want)
+		}
+
+		// Warriors are proficient in strength and agility, not intelligence
+		check, err := c.SkillCheck(ruleset, "strength", 10, rand.New(rand.NewSource(1)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if check.Proficiency != tt.want {
+			t.Errorf("level %d: strength check proficiency %d, want %d", tt.level, check.Proficiency, tt.want)
+		}
+		if check.Total != check.Natural+check.Modifier+tt.want {
+			t.Errorf("level %d: total %d does not include the proficiency bonus", tt.level, check.Total)
+		}
+		check, err = c.SkillCheck(ruleset, "intelligence", 10, rand.New(rand.NewSource(1)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if check.Proficiency != 0 {
+			t.Errorf("level %d: intelligence check proficiency %d, want 0", tt.level, check.Proficiency)
+		}
+
+		attack, err := ResolveAttack(c, NewDefaultCharacter("elf", "Bo", "mage"), ruleset, rand.New(rand.NewSource(1)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if attack.Proficiency != tt.want {
+			t.Errorf("level %d: attack proficiency %d, want %d", tt.level, attack.Proficiency, tt.want)
+		}
+	}
+}
+
+func TestProficiencyTableOverride(t *testing.T) {
+	original := ProficiencyTable
+	t.Cleanup(func() { ProficiencyTable = original })
+	ProficiencyTable = []ProficiencyStep{{MinLevel: 1, Bonus: 2}, {MinLevel: 4, Bonus: 4}}
+
+	if got := characterAtLevel(t, 3).ProficiencyBonus(); got != 2 {
+		t.Errorf("level 3: bonus %d, want 2", got)
+	}
+	if got := characterAtLevel(t, 4).ProficiencyBonus(); got != 4 {
+		t.Errorf("level 4: bonus %d, want 4", got)
+	}
+}
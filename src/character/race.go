@@ -0,0 +1,192 @@
+package character
+
+import (
+	"dnd-helper/src/abilities"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Race describes the mechanics of a playable race. AbilityMods are free
+// bonuses (or penalties) applied on top of the point-buy abilities at
+// creation; Limits are racial caps and floors
+type Race struct {
+	Name        string                     `json:"name"`
+	Description string                     `json:"description"`
+	AbilityMods map[string]int             `json:"abilityMods"`
+	Limits      map[string]abilities.Limit `json:"limits,omitempty"`
+}
+
+var (
+	racesMu sync.RWMutex
+	// races holds the registered races keyed by lower-case name
+	races = map[string]Race{}
+)
+
+func init() {
+	defaults := []Race{
+		{Name: "human", Description: "Adaptable and ambitious", AbilityMods: map[string]int{"charisma": 1}},
+		{Name: "elf", Description: "Keen-eyed and graceful", AbilityMods: map[string]int{"perception": 2}},
+		{Name: "dwarf", Description: "Stout and watchful", AbilityMods: map[string]int{"strength": 1, "perception": 1}},
+		{Name: "orc", Description: "Fierce and strong", AbilityMods: map[string]int{"strength": 2, "intelligence": -1}},
+		{
+			Name:        "goblin",
+			Description: "Small, quick and weak",
+			AbilityMods: map[string]int{"agility": 1},
+			Limits: map[string]abilities.Limit{
+				"strength": {Min: abilities.MinAbilityValue, Max: 3},
+				"agility":  {Min: 6, Max: abilities.MaxAbilityValue},
+			},
+		},
+	}
+	for _, race := range defaults {
+		if err := RegisterRace(race); err != nil {
+			panic(err)
+		}
+	}
+}
+
+// RegisterRace adds a race or replaces the race with the same name
+func RegisterRace(race Race) error {
+	if race.Name == "" {
+		return fmt.Errorf("race name cannot be empty")
+	}
+	for name, limit := range race.Limits {
+		if limit.Min < abilities.MinAbilityValue || limit.Max > abilities.MaxAbilityValue || limit.Min > limit.Max {
+			return fmt.Errorf("race %s: invalid limit [%d, %d] for %s", race.Name, limit.Min, limit.Max, name)
+		}
+	}
+
+	racesMu.Lock()
+	defer racesMu.Unlock()
+	races[strings.ToLower(race.Name)] = race
+	slog.Debug("Race registered", "race", race.Name)
+	return nil
+}
+
+// LookupRace returns a registered race by name, ignoring case
+func LookupRace(name string) (Race, bool) {
+	racesMu.RLock()
+	defer racesMu.RUnlock()
+	race, ok := races[strings.ToLower(name)]
+	return race, ok
+}
+
+// Races returns every registered race sorted by name
+func Races() []Race {
+	racesMu.RLock()
+	defer racesMu.RUnlock()
+	list := make([]Race, 0, len(races))
+	for _, race := range races {
+		list = append(list, race)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list
+}
+
+// LimitsForRace returns the ability limits of a race, or nil for races
+// without any
+func LimitsForRace(race string) map[string]abilities.Limit {
+	registered, ok := LookupRace(race)
+	if !ok || len(registered.Limits) == 0 {
+		return nil
+	}
+	limits := make(map[string]abilities.Limit, len(registered.Limits))
+	for name, limit := range registered.Limits {
+		limits[name] = limit
+	}
+	return limits
+}
+
+// ApplyRaceLimits sets the racial limits of race on abs. It fails if the
+// current scores break a racial cap or floor
+func ApplyRaceLimits(abs *abilities.Abilities, race string) error {
+	limits := LimitsForRace(race)
+	if limits == nil {
+		return nil
+	}
+	if err := abs.SetLimits(limits); err != nil {
+		return fmt.Errorf("racial limit for %s: %w", race, err)
+	}
+	return nil
+}
+
+// BonusesForRace returns the ability modifiers of a race, or nil for
+// unknown races
+func BonusesForRace(race string) map[string]int {
+	registered, ok := LookupRace(race)
+	if !ok {
+		return nil
+	}
+	bonuses := make(map[string]int, len(registered.AbilityMods))
+	for name, bonus := range registered.AbilityMods {
+		bonuses[name] = bonus
+	}
+	return bonuses
+}
+
+// ApplyRaceBonus adds the racial modifiers of race to abs. Modifiers are
+// free, they are not charged to the points pool, and are clamped to 1–10
+// and the racial limits. Unknown races get no modifiers
+func ApplyRaceBonus(abs *abilities.Abilities, race string) error {
+	_, err := applyRaceBonus(abs, race)
+	return err
+}
+
+// applyRaceBonus is ApplyRaceBonus that returns the modifiers actually applied
+func applyRaceBonus(abs *abilities.Abilities, race string) (map[string]int, error) {
+	bonuses := BonusesForRace(race)
+	if bonuses == nil {
+		slog.Warn("Unknown race, no racial modifiers applied", "race", race)
+		return nil, nil
+	}
+	names := make([]string, 0, len(bonuses))
+	for name := range bonuses {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	applied := map[string]int{}
+	for _, name := range names {
+		delta, err := abs.ApplyFreeBonus(name, bonuses[name])
+		if err != nil {
+			return nil, fmt.Errorf("racial modifier for %s: %w", race, err)
+		}
+		if delta != bonuses[name] {
+			slog.Debug("Racial modifier clamped", "race", race, "ability", name, "modifier", bonuses[name], "applied", delta)
+		}
+		if delta != 0 {
+			applied[name] = delta
+		}
+	}
+	return applied, nil
+}
+
+// GetRaceModifiers returns the racial modifiers applied at creation
+func (c *Character) GetRaceModifiers() map[string]int {
+	mods := make(map[string]int, len(c.raceMods))
+	for name, delta := range c.raceMods {
+		mods[name] = delta
+	}
+	return mods
+}
+
+// AbilityBreakdown explains every ability changed by a racial modifier,
+// e.g. "agility 6 (5 + 1 elf)"
+func (c *Character) AbilityBreakdown() map[string]string {
+	breakdown := map[string]string{}
+	for name, delta := range c.raceMods {
+		value, ok := c.abilities.GetAllAbilities()[name]
+		if !ok {
+			continue
+		}
+		sign := "+"
+		if delta < 0 {
+			sign = "-"
+		}
+		breakdown[name] = fmt.Sprintf("%s %d (%d %s %d %s)", name, value, value-delta, sign, max(delta, -delta), c.race)
+	}
+	return breakdown
+}
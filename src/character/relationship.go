@@ -0,0 +1,175 @@
+package character
+
+import (
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Relationship is a directed edge to another character of the roster: the
+// character is Kind of the character with the ID Target, e.g. "enemy" of.
+// Every kind is mutual, so Relate and Unrelate keep each edge mirrored by
+// one in the other direction
+type Relationship struct {
+	Target string `json:"target"`
+	Kind   string `json:"kind"`
+}
+
+var (
+	relationshipKindsMu sync.RWMutex
+	// relationshipKinds holds the registered kinds keyed by lower-case name
+	relationshipKinds = map[string]string{}
+)
+
+func init() {
+	for _, kind := range []string{"ally", "enemy", "family"} {
+		if err := RegisterRelationshipKind(kind); err != nil {
+			panic(err)
+		}
+	}
+}
+
+// RegisterRelationshipKind adds a kind of relationship, e.g. "rival"
+func RegisterRelationshipKind(kind string) error {
+	kind = strings.TrimSpace(kind)
+	if kind == "" {
+		return fmt.Errorf("relationship kind cannot be empty")
+	}
+	relationshipKindsMu.Lock()
+	defer relationshipKindsMu.Unlock()
+	relationshipKinds[strings.ToLower(kind)] = strings.ToLower(kind)
+	return nil
+}
+
+// RelationshipKinds returns every registered kind sorted by name
+func RelationshipKinds() []string {
+	relationshipKindsMu.RLock()
+	defer relationshipKindsMu.RUnlock()
+	kinds := make([]string, 0, len(relationshipKinds))
+	for _, kind := range relationshipKinds {
+		kinds = append(kinds, kind)
+	}
+	sort.Strings(kinds)
+	return kinds
+}
+
+// NormalizeRelationshipKind returns the registered spelling of kind,
+// matched case-insensitively
+func NormalizeRelationshipKind(kind string) (string, error) {
+	relationshipKindsMu.RLock()
+	normalized, ok := relationshipKinds[strings.ToLower(strings.TrimSpace(kind))]
+	relationshipKindsMu.RUnlock()
+	if !ok {
+		return "", fmt.Errorf("unknown relationship kind %q, must be one of %s", kind, strings.Join(RelationshipKinds(), ", "))
+	}
+	return normalized, nil
+}
+
+// AddRelationship makes the character kind of the character with the ID
+// otherID, one direction only. The kind must be registered. The character
+// package does not know the roster, so callers check that otherID exists;
+// use Relate to add both directions
+func (c *Character) AddRelationship(otherID string, kind string) error {
+	if otherID == "" {
+		return fmt.Errorf("relationship target cannot be empty")
+	}
+	if otherID == c.id {
+		return fmt.Errorf("%s cannot have a relationship with themselves", c.name)
+	}
+	kind, err := NormalizeRelationshipKind(kind)
+	if err != nil {
+		return err
+	}
+	for _, existing := range c.relations {
+		if existing.Target == otherID && existing.Kind == kind {
+			return fmt.Errorf("%s is already %s of %s", c.name, kind, otherID)
+		}
+	}
+	c.relations = append(c.relations, Relationship{Target: otherID, Kind: kind})
+	c.record("relationships", "", kind+" of "+otherID)
+	slog.Info("Relationship added", "character", c.name, "kind", kind, "target", otherID)
+	return nil
+}
+
+// hasRelationship checks if the character is kind of the character with the
+// ID otherID
+func (c *Character) hasRelationship(otherID string, kind string) bool {
+	for _, existing := range c.relations {
+		if existing.Target == otherID && existing.Kind == kind {
+			return true
+		}
+	}
+	return false
+}
+
+// Relate makes a and b kind of each other: the edge from a to b is mirrored
+// by one from b to a. Either both edges are added or neither. An edge from b
+// to a that already exists, e.g. from before edges were mirrored, is kept
+func Relate(a, b *Character, kind string) error {
+	if a.id == b.id {
+		return fmt.Errorf("%s cannot have a relationship with themselves", a.name)
+	}
+	kind, err := NormalizeRelationshipKind(kind)
+	if err != nil {
+		return err
+	}
+	if err := a.AddRelationship(b.id, kind); err != nil {
+		return err
+	}
+	if !b.hasRelationship(a.id, kind) {
+		// The target, kind and duplicates were checked above, so this cannot fail
+		_ = b.AddRelationship(a.id, kind)
+	}
+	return nil
+}
+
+// RemoveRelationship removes the edge of kind to the character with the ID
+// otherID, one direction only, and reports whether it existed
+func (c *Character) RemoveRelationship(otherID string, kind string) bool {
+	for i, relation := range c.relations {
+		if relation.Target == otherID && relation.Kind == kind {
+			c.relations = append(c.relations[:i:i], c.relations[i+1:]...)
+			c.record("relationships", kind+" of "+otherID, "")
+			slog.Info("Relationship removed", "character", c.name, "kind", kind, "target", otherID)
+			return true
+		}
+	}
+	return false
+}
+
+// Unrelate removes the relationship of kind between a and b in both
+// directions and reports whether any edge was removed
+func Unrelate(a, b *Character, kind string) (bool, error) {
+	kind, err := NormalizeRelationshipKind(kind)
+	if err != nil {
+		return false, err
+	}
+	removed := a.RemoveRelationship(b.id, kind)
+	if b.RemoveRelationship(a.id, kind) {
+		removed = true
+	}
+	return removed, nil
+}
+
+// Relationships returns the relationships in the order they were added
+func (c *Character) Relationships() []Relationship {
+	return append([]Relationship{}, c.relations...)
+}
+
+// RemoveRelationshipsTo removes every relationship with the character with
+// the ID otherID, e.g. after it was deleted, and returns how many were removed
+func (c *Character) RemoveRelationshipsTo(otherID string) int {
+	kept := []Relationship{}
+	for _, relation := range c.relations {
+		if relation.Target == otherID {
+			c.record("relationships", relation.Kind+" of "+otherID, "")
+			continue
+		}
+		kept = append(kept, relation)
+	}
+	removed := len(c.relations) - len(kept)
+	c.relations = kept
+	return removed
+}
@@ -0,0 +1,74 @@
+package character
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRelateMirrors(t *testing.T) {
+	a := NewDefaultCharacter("human", "Ann", "warrior")
+	b := NewDefaultCharacter("elf", "Bo", "mage")
+	if err := Relate(a, b, "Enemy"); err != nil {
+		t.Fatal(err)
+	}
+	if want := []Relationship{{Target: b.GetID(), Kind: "enemy"}}; !reflect.DeepEqual(a.Relationships(), want) {
+		t.Errorf("a: got %v, want %v", a.Relationships(), want)
+	}
+	if want := []Relationship{{Target: a.GetID(), Kind: "enemy"}}; !reflect.DeepEqual(b.Relationships(), want) {
+		t.Errorf("b: got %v, want %v", b.Relationships(), want)
+	}
+
+	removed, err := Unrelate(b, a, "enemy")
+	if err != nil || !removed {
+		t.Fatalf("Unrelate: got %v, %v", removed, err)
+	}
+	if len(a.Relationships()) != 0 || len(b.Relationships()) != 0 {
+		t.Errorf("edges left after Unrelate: %v and %v", a.Relationships(), b.Relationships())
+	}
+	if removed, _ := Unrelate(a, b, "enemy"); removed {
+		t.Error("Unrelate reported removing a relationship that did not exist")
+	}
+}
+
+func TestRelateKeepsExistingMirror(t *testing.T) {
+	a := NewDefaultCharacter("human", "Ann", "warrior")
+	b := NewDefaultCharacter("elf", "Bo", "mage")
+	// An edge in one direction only, as stored before edges were mirrored
+	if err := b.AddRelationship(a.GetID(), "ally"); err != nil {
+		t.Fatal(err)
+	}
+	if err := Relate(a, b, "ally"); err != nil {
+		t.Fatal(err)
+	}
+	if len(a.Relationships()) != 1 || len(b.Relationships()) != 1 {
+		t.Errorf("got %v and %v, want one edge each", a.Relationships(), b.Relationships())
+	}
+}
+
+func TestRelateRejectsInvalid(t *testing.T) {
+	a := NewDefaultCharacter("human", "Ann", "warrior")
+	b := NewDefaultCharacter("elf", "Bo", "mage")
+	if err := Relate(a, b, "family"); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name string
+		a, b *Character
+		kind string
+	}{
+		{"duplicate", a, b, "family"},
+		{"duplicate from the other side", b, a, "family"},
+		{"self", a, a, "ally"},
+		{"unknown kind", a, b, "nemesis"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := Relate(tt.a, tt.b, tt.kind); err == nil {
+				t.Error("Relate accepted the relationship")
+			}
+			if len(a.Relationships()) != 1 || len(b.Relationships()) != 1 {
+				t.Errorf("a failed Relate changed the edges: %v and %v", a.Relationships(), b.Relationships())
+			}
+		})
+	}
+}
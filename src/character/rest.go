@@ -0,0 +1,102 @@
+package character
+
+import (
+	"dnd-helper/src/condition"
+	"fmt"
+	"log/slog"
+	"sort"
+)
+
+// RestKind is the length of a rest
+type RestKind string
+
+const (
+	ShortRest RestKind = "short"
+	LongRest  RestKind = "long"
+)
+
+// ShortRestManaPercent is the share of max mana a short rest restores
+const ShortRestManaPercent = 25
+
+// restRecovery maps a condition to the one a long rest improves it to
+var restRecovery = map[condition.Condition]condition.Condition{
+	condition.Condition("Injured"):  ConditionHealthy,
+	condition.Condition("Critical"): condition.Condition("Injured"),
+}
+
+// DeadError is returned when a dead character is asked to do something only
+// the living can
+type DeadError struct {
+	Name string
+}
+
+func (e *DeadError) Error() string {
+	return fmt.Sprintf("%s is dead", e.Name)
+}
+
+// RestSummary describes what a rest changed
+type RestSummary struct {
+	Kind            RestKind       `json:"kind"`
+	ManaRestored    int            `json:"manaRestored"`
+	ManaPoints      int            `json:"manaPoints"`
+	MaxManaPoints   int            `json:"maxManaPoints"`
+	DrainRestored   map[string]int `json:"drainRestored"`
+	ConditionBefore string         `json:"conditionBefore"`
+	ConditionAfter  string         `json:"conditionAfter"`
+}
+
+// Rest recovers the character and ends spell buffs. A short rest restores ShortRestManaPercent of
+// max mana. A long rest restores all mana, clears ability drain and improves
+// every condition in restRecovery by one step. Resting at full resources
+// changes nothing. Dead characters cannot rest and get a *DeadError
+func (c *Character) Rest(kind RestKind) (RestSummary, error) {
+	if kind != ShortRest && kind != LongRest {
+		return RestSummary{}, fmt.Errorf("unknown rest type %q, expected %q or %q", kind, ShortRest, LongRest)
+	}
+	if c.IsDead() {
+		return RestSummary{}, &DeadError{Name: c.name}
+	}
+
+	summary := RestSummary{
+		Kind:            kind,
+		DrainRestored:   map[string]int{},
+		ConditionBefore: c.conditions.String(),
+	}
+	manaBefore := c.manaPoints
+
+	c.clearBuffs()
+	switch kind {
+	case ShortRest:
+		c.RestoreMana(c.GetMaxManaPoints() * ShortRestManaPercent / 100)
+	case LongRest:
+		c.RestoreMana(c.GetMaxManaPoints())
+		for name, amount := range c.abilities.DrainedAbilities() {
+			if err := c.abilities.RestoreDrain(name, amount); err != nil {
+				return RestSummary{}, err
+			}
+			summary.DrainRestored[name] = amount
+		}
+
+		// Collect first, so a condition is never improved twice
+		var improved []condition.Condition
+		for _, cond := range c.conditions.List() {
+			if _, ok := restRecovery[cond]; ok {
+				improved = append(improved, cond)
+			}
+		}
+		sort.Slice(improved, func(i, j int) bool { return improved[i] < improved[j] })
+		for _, cond := range improved {
+			c.conditions.Remove(cond)
+			c.conditions.Add(restRecovery[cond])
+		}
+	}
+
+	summary.ManaRestored = c.manaPoints - manaBefore
+	summary.ManaPoints = c.manaPoints
+	summary.MaxManaPoints = c.GetMaxManaPoints()
+	summary.ConditionAfter = c.conditions.String()
+	c.record("conditions", summary.ConditionBefore, summary.ConditionAfter)
+	slog.Debug("Rest taken", "character", c.name, "kind", kind, "manaRestored", summary.ManaRestored,
+		"drainRestored", len(summary.DrainRestored), "conditionBefore", summary.ConditionBefore, "conditionAfter", summary.ConditionAfter)
+	return summary, nil
+}
@@ -0,0 +1,168 @@
+package character
+
+import (
+	"dnd-helper/src/abilities"
+	"fmt"
+	"strings"
+)
+
+// sheetWrapWidth is the width long text is wrapped at in text sheets
+const sheetWrapWidth = 40
+
+// equipmentBonuses adapts the equipment bonuses to abilities.BonusSource
+type equipmentBonuses map[string]int
+
+func (b equipmentBonuses) AbilityBonuses() map[string]int {
+	return b
+}
+
+// wrap breaks text into lines of at most width runes at word boundaries.
+// Words longer than width get a line of their own
+func wrap(text string, width int) []string {
+	var lines []string
+	line := ""
+	for _, word := range strings.Fields(text) {
+		if line != "" && len([]rune(line))+1+len([]rune(word)) > width {
+			lines = append(lines, line)
+			line = ""
+		}
+		if line != "" {
+			line += " "
+		}
+		line += word
+	}
+	if line != "" || len(lines) == 0 {
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// equippedGroup is the items equipped in the slots of one item slot
+type equippedGroup struct {
+	slot  string
+	items []string
+}
+
+// equippedBySlot groups the equipped items by item slot in sheet order, so
+// both rings are listed together
+func (c *Character) equippedBySlot() []equippedGroup {
+	var groups []equippedGroup
+	for _, slot := range equipmentSlots {
+		itemName, occupied := c.equipment[slot]
+		if !occupied {
+			continue
+		}
+		itemSlot := itemSlotOf(slot)
+		if len(groups) > 0 && groups[len(groups)-1].slot == itemSlot {
+			groups[len(groups)-1].items = append(groups[len(groups)-1].items, itemName)
+			continue
+		}
+		groups = append(groups, equippedGroup{slot: itemSlot, items: []string{itemName}})
+	}
+	return groups
+}
+
+// RenderSheet renders a full character sheet: a name/race/class header with
+// the alignment, tags and biography, the abilities with modifiers and
+// equipment bonuses, mana, hit points, armor class, proficiency bonus,
+// conditions, gold, the equipped items by slot and the inventory. format is abilities.FormatText or
+// abilities.FormatMarkdown
+func (c *Character) RenderSheet(format string) (string, error) {
+	var b strings.Builder
+	var bonuses abilities.BonusSource
+	if len(c.equipment) > 0 {
+		bonuses = equipmentBonuses(c.EquipmentBonuses())
+	}
+
+	switch format {
+	case abilities.FormatText:
+		fmt.Fprintf(&b, "%s\n%s %s, level %d\n", c.name, c.race, c.classSummary(), c.level)
+		if c.alignment != "" {
+			fmt.Fprintf(&b, "Alignment: %s\n", c.alignment)
+		}
+		if len(c.tags) > 0 {
+			fmt.Fprintf(&b, "Tags: %s\n", strings.Join(c.tags, ", "))
+		}
+		if c.biography != "" {
+			fmt.Fprintln(&b)
+			for _, line := range wrap(c.biography, sheetWrapWidth) {
+				fmt.Fprintln(&b, line)
+			}
+		}
+		fmt.Fprintln(&b)
+		if err := c.abilities.RenderSheet(&b, format, bonuses); err != nil {
+			return "", err
+		}
+		fmt.Fprintf(&b, "\nMana: %d/%d\n", c.manaPoints, c.GetMaxManaPoints())
+		fmt.Fprintf(&b, "HP: %d/%d\n", c.hitPoints, c.GetMaxHitPoints())
+		fmt.Fprintf(&b, "AC: %d\n", c.ArmorClass())
+		fmt.Fprintf(&b, "Proficiency: %s (%s)\n", fmt.Sprintf("%+d", c.ProficiencyBonus()), strings.Join(c.Proficiencies(), ", "))
+		fmt.Fprintf(&b, "Conditions: %s\n", c.conditions.String())
+		fmt.Fprintf(&b, "Gold: %d\n", c.inventory.GetGold())
+		fmt.Fprintln(&b, "\nEquipped:")
+		equipped := c.equippedBySlot()
+		for _, group := range equipped {
+			fmt.Fprintf(&b, "  %-10s %s\n", group.slot, strings.Join(group.items, ", "))
+		}
+		if len(equipped) == 0 {
+			fmt.Fprintln(&b, "  nothing")
+		}
+		fmt.Fprintf(&b, "\n%-20s %4s %-10s %s\n", "Item", "Qty", "Condition", "Description")
+		for _, item := range c.inventory.GetAllItems() {
+			lines := wrap(item.GetDescription(), sheetWrapWidth)
+			fmt.Fprintf(&b, "%-20s %4d %-10s %s\n", item.GetName(), item.GetQuantity(), item.GetCondition().String(), lines[0])
+			for _, line := range lines[1:] {
+				fmt.Fprintf(&b, "%-20s %4s %-10s %s\n", "", "", "", line)
+			}
+		}
+	case abilities.FormatMarkdown:
+		fmt.Fprintf(&b, "## %s\n*%s %s, level %d*\n\n", c.name, c.race, c.classSummary(), c.level)
+		if c.alignment != "" {
+			fmt.Fprintf(&b, "**Alignment:** %s  \n", c.alignment)
+		}
+		if len(c.tags) > 0 {
+			fmt.Fprintf(&b, "**Tags:** %s  \n", strings.Join(c.tags, ", "))
+		}
+		if c.biography != "" {
+			fmt.Fprintf(&b, "\n%s\n", c.biography)
+		}
+		if c.alignment != "" || len(c.tags) > 0 || c.biography != "" {
+			fmt.Fprintln(&b)
+		}
+		if err := c.abilities.RenderSheet(&b, format, bonuses); err != nil {
+			return "", err
+		}
+		fmt.Fprintf(&b, "\n**Mana:** %d/%d  \n", c.manaPoints, c.GetMaxManaPoints())
+		fmt.Fprintf(&b, "**HP:** %d/%d  \n", c.hitPoints, c.GetMaxHitPoints())
+		fmt.Fprintf(&b, "**AC:** %d  \n", c.ArmorClass())
+		fmt.Fprintf(&b, "**Proficiency:** %s (%s)  \n", fmt.Sprintf("%+d", c.ProficiencyBonus()), strings.Join(c.Proficiencies(), ", "))
+		fmt.Fprintf(&b, "**Conditions:** %s  \n", c.conditions.String())
+		fmt.Fprintf(&b, "**Gold:** %d\n", c.inventory.GetGold())
+		fmt.Fprintln(&b, "\n**Equipped:**")
+		equipped := c.equippedBySlot()
+		for _, group := range equipped {
+			fmt.Fprintf(&b, "- %s: %s\n", group.slot, strings.Join(group.items, ", "))
+		}
+		if len(equipped) == 0 {
+			fmt.Fprintln(&b, "- nothing")
+		}
+		fmt.Fprintln(&b, "\n| Item | Qty | Condition | Description |")
+		fmt.Fprintln(&b, "|---|---:|---|---|")
+		for _, item := range c.inventory.GetAllItems() {
+			// Markdown renderers wrap cells themselves, but a pipe would end the cell
+			description := strings.ReplaceAll(item.GetDescription(), "|", `\|`)
+			fmt.Fprintf(&b, "| %s | %d | %s | %s |\n", item.GetName(), item.GetQuantity(), item.GetCondition().String(), description)
+		}
+	default:
+		return "", fmt.Errorf("unknown sheet format %q, supported formats are %q and %q", format, abilities.FormatText, abilities.FormatMarkdown)
+	}
+	return b.String(), nil
+}
+
+// Sheet renders the text sheet of RenderSheet for printing to a terminal.
+// It does not log, so it is safe to call as often as needed
+func (c *Character) Sheet() string {
+	// The text format is always supported
+	sheet, _ := c.RenderSheet(abilities.FormatText)
+	return sheet
+}
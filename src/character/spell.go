@@ -0,0 +1,269 @@
+package character
+
+import (
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// EffectKind is what a spell does to its target
+type EffectKind string
+
+const (
+	// EffectNone spells only cost mana, e.g. utility or flavor spells
+	EffectNone EffectKind = ""
+	// EffectHeal restores Amount hit points and improves the condition one
+	// step, as a long rest does
+	EffectHeal EffectKind = "heal"
+	// EffectBuff adds Amount to Ability until the target rests
+	EffectBuff EffectKind = "buff"
+	// EffectDamage deals Amount damage and needs a target other than the caster
+	EffectDamage EffectKind = "damage"
+)
+
+// SpellEffect is the effect a spell applies to its target
+type SpellEffect struct {
+	Kind    EffectKind `json:"kind,omitempty"`
+	Amount  int        `json:"amount,omitempty"`
+	Ability string     `json:"ability,omitempty"`
+}
+
+// Spell is a castable spell
+type Spell struct {
+	Name        string      `json:"name"`
+	ManaCost    int         `json:"manaCost"`
+	Description string      `json:"description"`
+	Effect      SpellEffect `json:"effect"`
+}
+
+// CastResult describes what a cast changed
+type CastResult struct {
+	Spell         string     `json:"spell"`
+	Caster        string     `json:"caster"`
+	Target        string     `json:"target"`
+	Effect        EffectKind `json:"effect,omitempty"`
+	ManaCost      int        `json:"manaCost"`
+	ManaPoints    int        `json:"manaPoints"`
+	MaxManaPoints int        `json:"maxManaPoints"`
+	// TargetHitPoints and TargetCondition are the state of the target after
+	// the effect
+	TargetHitPoints int    `json:"targetHitPoints"`
+	TargetCondition string `json:"targetCondition"`
+}
+
+var (
+	spellsMu sync.RWMutex
+	// spells holds the registered spells keyed by lower-case name
+	spells = map[string]Spell{}
+)
+
+func init() {
+	defaults := []Spell{
+		{Name: "Light", ManaCost: 10, Description: "A glowing orb lights the area"},
+		{Name: "Cure Wounds", ManaCost: 60, Description: "Closes wounds and eases injuries",
+			Effect: SpellEffect{Kind: EffectHeal, Amount: 20}},
+		{Name: "Bull's Strength", ManaCost: 80, Description: "Grants the strength of a bull until the next rest",
+			Effect: SpellEffect{Kind: EffectBuff, Amount: 2, Ability: "strength"}},
+		{Name: "Magic Missile", ManaCost: 50, Description: "Darts of force that never miss",
+			Effect: SpellEffect{Kind: EffectDamage, Amount: 10}},
+	}
+	for _, spell := range defaults {
+		if err := RegisterSpell(spell); err != nil {
+			panic(err)
+		}
+	}
+}
+
+// RegisterSpell adds a spell or replaces the spell with the same name
+func RegisterSpell(spell Spell) error {
+	if spell.Name == "" {
+		return fmt.Errorf("spell name cannot be empty")
+	}
+	if spell.ManaCost < 0 {
+		return fmt.Errorf("spell %s: mana cost %d cannot be negative", spell.Name, spell.ManaCost)
+	}
+	switch spell.Effect.Kind {
+	case EffectNone:
+	case EffectHeal, EffectDamage:
+		if spell.Effect.Amount <= 0 {
+			return fmt.Errorf("spell %s: %s amount %d must be positive", spell.Name, spell.Effect.Kind, spell.Effect.Amount)
+		}
+	case EffectBuff:
+		if spell.Effect.Amount == 0 || spell.Effect.Ability == "" {
+			return fmt.Errorf("spell %s: buff needs an ability and a non-zero amount", spell.Name)
+		}
+	default:
+		return fmt.Errorf("spell %s: unknown effect %q", spell.Name, spell.Effect.Kind)
+	}
+
+	spellsMu.Lock()
+	defer spellsMu.Unlock()
+	spells[strings.ToLower(spell.Name)] = spell
+	slog.Debug("Spell registered", "spell", spell.Name)
+	return nil
+}
+
+// LookupSpell returns a registered spell by name, ignoring case
+func LookupSpell(name string) (Spell, bool) {
+	spellsMu.RLock()
+	defer spellsMu.RUnlock()
+	spell, ok := spells[strings.ToLower(name)]
+	return spell, ok
+}
+
+// Spells returns every registered spell sorted by name
+func Spells() []Spell {
+	spellsMu.RLock()
+	defer spellsMu.RUnlock()
+	list := make([]Spell, 0, len(spells))
+	for _, spell := range spells {
+		list = append(list, spell)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list
+}
+
+// LearnSpell adds a registered spell to the spellbook. Learning a known
+// spell again changes nothing
+func (c *Character) LearnSpell(spellName string) error {
+	spell, ok := LookupSpell(spellName)
+	if !ok {
+		return fmt.Errorf("unknown spell: %s", spellName)
+	}
+	if c.KnowsSpell(spell.Name) {
+		return nil
+	}
+	c.spellbook = append(c.spellbook, spell.Name)
+	sort.Strings(c.spellbook)
+	slog.Debug("Spell learned", "character", c.name, "spell", spell.Name)
+	return nil
+}
+
+// ForgetSpell removes a spell from the spellbook
+func (c *Character) ForgetSpell(spellName string) error {
+	for i, known := range c.spellbook {
+		if strings.EqualFold(known, spellName) {
+			c.spellbook = append(c.spellbook[:i], c.spellbook[i+1:]...)
+			slog.Debug("Spell forgotten", "character", c.name, "spell", known)
+			return nil
+		}
+	}
+	return fmt.Errorf("%s does not know %s", c.name, spellName)
+}
+
+// KnowsSpell checks if the spell is in the spellbook, ignoring case
+func (c *Character) KnowsSpell(spellName string) bool {
+	for _, known := range c.spellbook {
+		if strings.EqualFold(known, spellName) {
+			return true
+		}
+	}
+	return false
+}
+
+// GetSpellbook returns the names of the known spells, sorted
+func (c *Character) GetSpellbook() []string {
+	return append([]string{}, c.spellbook...)
+}
+
+// GetBuffs returns the ability modifiers granted by spells until the next rest
+func (c *Character) GetBuffs() map[string]int {
+	buffs := make(map[string]int, len(c.buffs))
+	for name, amount := range c.buffs {
+		buffs[name] = amount
+	}
+	return buffs
+}
+
+// Cast casts a known spell on the caster itself
+func (c *Character) Cast(spellName string) (CastResult, error) {
+	return c.CastOn(spellName, nil)
+}
+
+// CastOn casts a known spell on target, or on the caster when target is nil.
+// Everything is checked before mana is spent, so a failed cast changes
+// nothing. A caster without enough mana gets an *InsufficientManaError, and
+// a dead caster or healed target a *DeadError
+func (c *Character) CastOn(spellName string, target *Character) (CastResult, error) {
+	if !c.KnowsSpell(spellName) {
+		return CastResult{}, fmt.Errorf("%s does not know %s", c.name, spellName)
+	}
+	spell, ok := LookupSpell(spellName)
+	if !ok {
+		return CastResult{}, fmt.Errorf("unknown spell: %s", spellName)
+	}
+	if c.IsDead() {
+		return CastResult{}, &DeadError{Name: c.name}
+	}
+	if target == nil {
+		target = c
+	}
+	switch spell.Effect.Kind {
+	case EffectHeal:
+		if target.IsDead() {
+			return CastResult{}, &DeadError{Name: target.name}
+		}
+	case EffectBuff:
+		if _, err := target.abilities.CurrentValue(spell.Effect.Ability); err != nil {
+			return CastResult{}, fmt.Errorf("spell %s: %w", spell.Name, err)
+		}
+	case EffectDamage:
+		if target == c {
+			return CastResult{}, fmt.Errorf("spell %s needs a target", spell.Name)
+		}
+		if target.IsDead() {
+			return CastResult{}, &DeadError{Name: target.name}
+		}
+	}
+
+	if err := c.SpendMana(spell.ManaCost); err != nil {
+		return CastResult{}, err
+	}
+
+	switch spell.Effect.Kind {
+	case EffectHeal:
+		// The target is alive, so healing cannot fail
+		_ = target.Heal(spell.Effect.Amount)
+		for _, cond := range target.conditions.List() {
+			if improved, ok := restRecovery[cond]; ok {
+				before := target.conditions.String()
+				target.conditions.Remove(cond)
+				target.conditions.Add(improved)
+				target.record("conditions", before, target.conditions.String())
+				break
+			}
+		}
+	case EffectBuff:
+		if target.buffs == nil {
+			target.buffs = map[string]int{}
+		}
+		target.buffs[spell.Effect.Ability] += spell.Effect.Amount
+	case EffectDamage:
+		_ = target.TakeDamage(spell.Effect.Amount)
+	}
+
+	slog.Debug("Spell cast", "character", c.name, "spell", spell.Name, "target", target.name, "mana", spell.ManaCost)
+	return CastResult{
+		Spell:           spell.Name,
+		Caster:          c.name,
+		Target:          target.name,
+		Effect:          spell.Effect.Kind,
+		ManaCost:        spell.ManaCost,
+		ManaPoints:      c.manaPoints,
+		MaxManaPoints:   c.GetMaxManaPoints(),
+		TargetHitPoints: target.hitPoints,
+		TargetCondition: target.conditions.String(),
+	}, nil
+}
+
+// clearBuffs ends every spell buff, e.g. on rest
+func (c *Character) clearBuffs() {
+	if len(c.buffs) == 0 {
+		return
+	}
+	c.buffs = nil
+	c.RecalculateDerivedStats()
+	slog.Debug("Buffs wore off", "character", c.name)
+}
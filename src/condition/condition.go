@@ -1,8 +1,9 @@
 package condition
 
 import (
-	_ "fmt"
-	_ "log"
+	"fmt"
+	"log/slog"
+	"strings"
 )
 
 // Condition represents the condition state of a character
@@ -17,3 +18,198 @@ func NewCondition(cond string) Condition {
 func (c Condition) String() string {
 	return string(c)
 }
+
+// Conditions is a set of conditions active at the same time. Conditions
+// keep the order they were added in and may carry a remaining-turns counter
+// and a number of stacks
+type Conditions struct {
+	active []Condition
+	turns  map[Condition]int // remaining turns of timed conditions, absent means indefinite
+	stacks map[Condition]int // instances of stacked conditions, absent means 1
+}
+
+// NewConditions creates a set from the given conditions, dropping duplicates
+func NewConditions(conds ...Condition) Conditions {
+	set := Conditions{}
+	for _, c := range conds {
+		set.Add(c)
+	}
+	return set
+}
+
+// Add inserts an indefinite condition into the set. Adding a condition that
+// is already present or an empty condition does nothing
+func (cs *Conditions) Add(c Condition) {
+	if c == "" || cs.Has(c) {
+		return
+	}
+	cs.active = append(cs.active, c)
+}
+
+// AddTimed inserts a condition that expires after the given number of turns.
+// A duration of 0 means indefinite. Re-adding an active condition resets
+// its duration
+func (cs *Conditions) AddTimed(c Condition, turns int) {
+	if c == "" {
+		return
+	}
+	cs.Add(c)
+	if turns <= 0 {
+		delete(cs.turns, c)
+		return
+	}
+	if cs.turns == nil {
+		cs.turns = map[Condition]int{}
+	}
+	cs.turns[c] = turns
+}
+
+// Apply adds a condition following a stacking policy when it is already
+// active: StackIgnore keeps the active one as it is, StackRefresh resets its
+// duration and StackAdd adds a stack and keeps the longer duration. A
+// duration of 0 means indefinite
+func (cs *Conditions) Apply(c Condition, turns int, policy StackingPolicy) {
+	if c == "" {
+		return
+	}
+	if !cs.Has(c) {
+		cs.AddTimed(c, turns)
+		return
+	}
+	switch policy {
+	case StackIgnore:
+	case StackAdd:
+		cs.SetStacks(c, cs.Stacks(c)+1)
+		if remaining, timed := cs.turns[c]; timed && (turns <= 0 || turns > remaining) {
+			cs.AddTimed(c, turns)
+		}
+	default:
+		cs.AddTimed(c, turns)
+	}
+}
+
+// Stacks returns how many instances of an active condition are stacked, or
+// 0 for inactive conditions
+func (cs Conditions) Stacks(c Condition) int {
+	if !cs.Has(c) {
+		return 0
+	}
+	if stacks, ok := cs.stacks[c]; ok {
+		return stacks
+	}
+	return 1
+}
+
+// SetStacks sets the number of stacks of an active condition. Inactive
+// conditions and counts below 1 are ignored
+func (cs *Conditions) SetStacks(c Condition, stacks int) {
+	if stacks < 1 || !cs.Has(c) {
+		return
+	}
+	if stacks == 1 {
+		delete(cs.stacks, c)
+		return
+	}
+	if cs.stacks == nil {
+		cs.stacks = map[Condition]int{}
+	}
+	cs.stacks[c] = stacks
+}
+
+// Remove deletes a condition from the set and reports whether it was present
+func (cs *Conditions) Remove(c Condition) bool {
+	for i, existing := range cs.active {
+		if existing == c {
+			cs.active = append(cs.active[:i:i], cs.active[i+1:]...)
+			delete(cs.turns, c)
+			delete(cs.stacks, c)
+			return true
+		}
+	}
+	return false
+}
+
+// Has checks if a condition is in the set
+func (cs Conditions) Has(c Condition) bool {
+	for _, existing := range cs.active {
+		if existing == c {
+			return true
+		}
+	}
+	return false
+}
+
+// List returns the active conditions in the order they were added
+func (cs Conditions) List() []Condition {
+	list := make([]Condition, len(cs.active))
+	copy(list, cs.active)
+	return list
+}
+
+// Remaining returns the turns left for a timed condition, or 0 for
+// indefinite and inactive conditions
+func (cs Conditions) Remaining(c Condition) int {
+	return cs.turns[c]
+}
+
+// Tick decrements every timed condition and removes those reaching zero.
+// Indefinite conditions are left untouched. It returns the expired conditions
+func (cs *Conditions) Tick() []Condition {
+	var expired []Condition
+	for _, c := range cs.List() {
+		turns, timed := cs.turns[c]
+		if !timed {
+			continue
+		}
+		if turns <= 1 {
+			cs.Remove(c)
+			expired = append(expired, c)
+			continue
+		}
+		cs.turns[c] = turns - 1
+	}
+	return expired
+}
+
+// Clone returns a copy of the set that shares no storage with the original
+func (cs Conditions) Clone() Conditions {
+	clone := Conditions{active: cs.List()}
+	if cs.turns != nil {
+		clone.turns = make(map[Condition]int, len(cs.turns))
+		for c, turns := range cs.turns {
+			clone.turns[c] = turns
+		}
+	}
+	if cs.stacks != nil {
+		clone.stacks = make(map[Condition]int, len(cs.stacks))
+		for c, stacks := range cs.stacks {
+			clone.stacks[c] = stacks
+		}
+	}
+	return clone
+}
+
+// String returns the conditions joined by commas
+func (cs Conditions) String() string {
+	names := make([]string, len(cs.active))
+	for i, c := range cs.active {
+		names[i] = c.String()
+	}
+	return strings.Join(names, ", ")
+}
+
+// LogState logs the active conditions with their remaining turns for
+// debugging, so String can stay free of side effects
+func (cs Conditions) LogState() {
+	parts := make([]string, len(cs.active))
+	for i, c := range cs.active {
+		parts[i] = c.String()
+		if turns, ok := cs.turns[c]; ok {
+			parts[i] = fmt.Sprintf("%s (%d turns)", c, turns)
+		}
+		if stacks, ok := cs.stacks[c]; ok {
+			parts[i] = fmt.Sprintf("%s x%d", parts[i], stacks)
+		}
+	}
+	slog.Debug("Conditions", "conditions", strings.Join(parts, ", "))
+}
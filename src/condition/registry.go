@@ -0,0 +1,371 @@
+package condition
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"dnd-helper/src/abilities"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Category tells whether a condition applies to characters or items
+type Category string
+
+const (
+	CategoryCharacter Category = "character"
+	CategoryItem      Category = "item"
+)
+
+// StackingPolicy defines what happens when a condition is applied twice
+type StackingPolicy string
+
+const (
+	StackRefresh StackingPolicy = "refresh" // reapplying resets the duration
+	StackAdd     StackingPolicy = "stack"   // reapplying adds another instance
+	StackIgnore  StackingPolicy = "ignore"  // reapplying has no effect
+)
+
+// Definition describes the mechanics of a condition
+type Definition struct {
+	Name           string         `json:"name" yaml:"name"`
+	Severity       int            `json:"severity" yaml:"severity"`
+	Category       Category       `json:"category" yaml:"category"`
+	Stacking       StackingPolicy `json:"stacking" yaml:"stacking"`
+	AbilityEffects map[string]int `json:"abilityEffects,omitempty" yaml:"abilityEffects,omitempty"`
+	DamagePerTurn  int            `json:"damagePerTurn" yaml:"damagePerTurn"`
+	Transitions    []string       `json:"transitions,omitempty" yaml:"transitions,omitempty"`
+	// Overrides allows a custom definition to replace a built-in one
+	Overrides bool `json:"overrides,omitempty" yaml:"overrides,omitempty"`
+	Builtin   bool `json:"builtin" yaml:"-"`
+}
+
+// builtinDefinitions are registered into every new Registry
+var builtinDefinitions = []Definition{
+	{Name: "Healthy", Severity: 0, Category: CategoryCharacter, Stacking: StackIgnore, Transitions: []string{"Injured", "Poisoned", "Stunned"}},
+	{Name: "Injured", Severity: 1, Category: CategoryCharacter, Stacking: StackIgnore, Transitions: []string{"Healthy", "Critical"}},
+	{Name: "Critical", Severity: 2, Category: CategoryCharacter, Stacking: StackIgnore, Transitions: []string{"Injured", "Unconscious"}},
+	{Name: "Unconscious", Severity: 3, Category: CategoryCharacter, Stacking: StackIgnore, Transitions: []string{"Critical", "Dead"}},
+	{Name: "Dead", Severity: 4, Category: CategoryCharacter, Stacking: StackIgnore},
+	{Name: "Poisoned", Severity: 1, Category: CategoryCharacter, Stacking: StackRefresh, AbilityEffects: map[string]int{"strength": -2}, DamagePerTurn: 1, Transitions: []string{"Healthy"}},
+	// Stunned drops agility to abilities.MinAbilityValue whatever it was
+	{Name: "Stunned", Severity: 1, Category: CategoryCharacter, Stacking: StackRefresh, AbilityEffects: map[string]int{"agility": -abilities.MaxAbilityValue}, Transitions: []string{"Healthy"}},
+	{Name: "N/A", Severity: 0, Category: CategoryItem, Stacking: StackIgnore},
+	{Name: "Pristine", Severity: 0, Category: CategoryItem, Stacking: StackIgnore, Transitions: []string{"Worn"}},
+	{Name: "Worn", Severity: 1, Category: CategoryItem, Stacking: StackIgnore, Transitions: []string{"Broken"}},
+	{Name: "Broken", Severity: 2, Category: CategoryItem, Stacking: StackIgnore, Transitions: []string{"Worn"}},
+}
+
+// Registry holds built-in and custom condition definitions. It is safe for
+// concurrent use
+type Registry struct {
+	mu    sync.RWMutex
+	defs  map[string]Definition
+	order []string
+}
+
+// DefaultRegistry is the registry shared by the server
+var DefaultRegistry = NewRegistry()
+
+// NewRegistry creates a registry pre-populated with the built-in conditions
+func NewRegistry() *Registry {
+	r := &Registry{defs: map[string]Definition{}}
+	for _, def := range builtinDefinitions {
+		def.Builtin = true
+		r.defs[def.Name] = def
+		r.order = append(r.order, def.Name)
+	}
+	return r
+}
+
+// Register validates and adds a custom definition. Replacing a built-in
+// requires def.Overrides to be set; redefining a custom condition always
+// replaces it
+func (r *Registry) Register(def Definition) error {
+	return r.RegisterAll([]Definition{def})
+}
+
+// SaveError is returned by RegisterAllAndSave when the definitions could not
+// be written. The registry is left unchanged
+type SaveError struct {
+	Path string
+	Err  error
+}
+
+func (e *SaveError) Error() string {
+	return fmt.Sprintf("saving conditions to %s: %v", e.Path, e.Err)
+}
+
+func (e *SaveError) Unwrap() error {
+	return e.Err
+}
+
+// RegisterAll validates a batch of definitions before registering any of
+// them, so transitions may reference conditions defined in the same batch
+func (r *Registry) RegisterAll(defs []Definition) error {
+	return r.registerAll(defs, "")
+}
+
+// RegisterAllAndSave registers a batch like RegisterAll and writes the
+// resulting custom definitions to path before they take effect, so the
+// registry never holds conditions that would be lost on restart
+func (r *Registry) RegisterAllAndSave(defs []Definition, path string) error {
+	return r.registerAll(defs, path)
+}
+
+// registerAll registers defs, saving them to path first unless path is empty
+func (r *Registry) registerAll(defs []Definition, path string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	batch := map[string]bool{}
+	for _, def := range defs {
+		batch[def.Name] = true
+	}
+	for _, def := range defs {
+		if err := r.validate(def, batch); err != nil {
+			return err
+		}
+	}
+
+	next := make(map[string]Definition, len(r.defs)+len(defs))
+	for name, def := range r.defs {
+		next[name] = def
+	}
+	order := append([]string{}, r.order...)
+	for _, def := range defs {
+		def.Builtin = false
+		if _, exists := next[def.Name]; !exists {
+			order = append(order, def.Name)
+		}
+		next[def.Name] = def
+	}
+	if path != "" {
+		if err := saveFile(path, customDefinitions(next, order)); err != nil {
+			return &SaveError{Path: path, Err: err}
+		}
+	}
+	r.defs, r.order = next, order
+	return nil
+}
+
+func (r *Registry) validate(def Definition, batch map[string]bool) error {
+	if def.Name == "" {
+		return fmt.Errorf("condition name cannot be empty")
+	}
+	if existing, ok := r.defs[def.Name]; ok && existing.Builtin && !def.Overrides {
+		return fmt.Errorf("condition %s collides with a built-in condition, set overrides to replace it", def.Name)
+	}
+	if def.Severity < 0 {
+		return fmt.Errorf("condition %s severity %d cannot be negative", def.Name, def.Severity)
+	}
+	if def.Category != CategoryCharacter && def.Category != CategoryItem {
+		return fmt.Errorf("condition %s category %q must be %q or %q", def.Name, def.Category, CategoryCharacter, CategoryItem)
+	}
+	switch def.Stacking {
+	case StackRefresh, StackAdd, StackIgnore:
+	default:
+		return fmt.Errorf("condition %s stacking policy %q must be %q, %q or %q", def.Name, def.Stacking, StackRefresh, StackAdd, StackIgnore)
+	}
+	if def.DamagePerTurn < 0 {
+		return fmt.Errorf("condition %s damage per turn %d cannot be negative", def.Name, def.DamagePerTurn)
+	}
+	standard := abilities.NewDefaultAbilities()
+	for ability := range def.AbilityEffects {
+		if _, err := standard.CurrentValue(ability); err != nil {
+			return fmt.Errorf("condition %s ability effect: %w", def.Name, err)
+		}
+	}
+	for _, target := range def.Transitions {
+		if _, ok := r.defs[target]; !ok && !batch[target] {
+			return fmt.Errorf("condition %s transitions to unknown condition %s", def.Name, target)
+		}
+	}
+	return nil
+}
+
+// Lookup returns the definition registered under name
+func (r *Registry) Lookup(name string) (Definition, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	def, ok := r.defs[name]
+	return def, ok
+}
+
+// Effect returns a copy of the ability effects of cond, empty for conditions
+// without effects and unknown conditions
+func (r *Registry) Effect(cond Condition) map[string]int {
+	def, _ := r.Lookup(cond.String())
+	effect := make(map[string]int, len(def.AbilityEffects))
+	for ability, amount := range def.AbilityEffects {
+		effect[ability] = amount
+	}
+	return effect
+}
+
+// Stacking returns the stacking policy of cond. Unknown conditions are
+// refreshed when applied again
+func (r *Registry) Stacking(cond Condition) StackingPolicy {
+	def, ok := r.Lookup(cond.String())
+	if !ok {
+		return StackRefresh
+	}
+	return def.Stacking
+}
+
+// DamagePerTurn returns the damage a single stack of cond deals every turn,
+// 0 for unknown conditions
+func (r *Registry) DamagePerTurn(cond Condition) int {
+	def, _ := r.Lookup(cond.String())
+	return def.DamagePerTurn
+}
+
+// CanTransition reports whether a character or item in condition from may
+// change to condition to. Changing to a condition of the same or a higher
+// severity is always allowed; a less severe condition must be listed in the
+// transitions of from. Unknown conditions allow any change
+func (r *Registry) CanTransition(from, to Condition) bool {
+	fromDef, ok := r.Lookup(from.String())
+	if !ok || from == to {
+		return true
+	}
+	toDef, ok := r.Lookup(to.String())
+	if !ok || toDef.Severity >= fromDef.Severity {
+		return true
+	}
+	for _, target := range fromDef.Transitions {
+		if target == to.String() {
+			return true
+		}
+	}
+	return false
+}
+
+// ConditionEffect returns the ability effects of cond in DefaultRegistry,
+// e.g. -2 strength for Poisoned. Unknown conditions have no effect
+func ConditionEffect(cond Condition) map[string]int {
+	return DefaultRegistry.Effect(cond)
+}
+
+// List returns every registered definition in registration order
+func (r *Registry) List() []Definition {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	defs := make([]Definition, 0, len(r.order))
+	for _, name := range r.order {
+		defs = append(defs, r.defs[name])
+	}
+	return defs
+}
+
+// Custom returns only the definitions that were registered at runtime
+func (r *Registry) Custom() []Definition {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return customDefinitions(r.defs, r.order)
+}
+
+// customDefinitions returns the non built-in definitions of defs in order
+func customDefinitions(defs map[string]Definition, order []string) []Definition {
+	var custom []Definition
+	for _, name := range order {
+		if def := defs[name]; !def.Builtin {
+			custom = append(custom, def)
+		}
+	}
+	return custom
+}
+
+// ParseYAML decodes a list of condition definitions
+func ParseYAML(data []byte) ([]Definition, error) {
+	var defs []Definition
+	if err := yaml.Unmarshal(data, &defs); err != nil {
+		return nil, fmt.Errorf("invalid condition YAML: %w", err)
+	}
+	return defs, nil
+}
+
+// LoadFile registers the definitions stored in a YAML file. A missing file
+// is not an error
+func (r *Registry) LoadFile(path string) error {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defs, err := ParseYAML(data)
+	if err != nil {
+		return err
+	}
+	return r.RegisterAll(defs)
+}
+
+// SaveFile writes the custom definitions to a YAML file. The file is
+// replaced atomically so a failed write never leaves it half-written
+func (r *Registry) SaveFile(path string) error {
+	return saveFile(path, r.Custom())
+}
+
+// writeData writes the data of a conditions file. Tests replace it to
+// simulate failing disks
+var writeData = func(f *os.File, data []byte) error {
+	_, err := f.Write(data)
+	return err
+}
+
+// saveFile atomically writes custom definitions to a YAML file. The data is
+// written to a temporary file, read back and compared before it replaces
+// path, and the directory is synced so the rename survives a crash
+func saveFile(path string, custom []Definition) error {
+	if custom == nil {
+		custom = []Definition{}
+	}
+	data, err := yaml.Marshal(custom)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if err := writeData(tmp, data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	written, err := os.ReadFile(tmp.Name())
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(written, data) {
+		return fmt.Errorf("verifying %s: wrote %d of %d bytes", tmp.Name(), len(written), len(data))
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return err
+	}
+	return syncDir(filepath.Dir(path))
+}
+
+// syncDir flushes a directory, making a rename in it durable
+func syncDir(dir string) error {
+	d, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	defer d.Close()
+	return d.Sync()
+}
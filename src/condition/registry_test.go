@@ -0,0 +1,182 @@
+package condition
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestApplyStackingPolicies(t *testing.T) {
+	tests := []struct {
+		policy     StackingPolicy
+		turns      int
+		wantTurns  int
+		wantStacks int
+	}{
+		{StackIgnore, 5, 2, 1},
+		{StackRefresh, 5, 5, 1},
+		{StackRefresh, 1, 1, 1},
+		{StackAdd, 5, 5, 2},
+		{StackAdd, 1, 2, 2},
+		{StackAdd, 0, 0, 2},
+	}
+	for _, tt := range tests {
+		cs := NewConditions()
+		cs.Apply("Burning", 2, tt.policy)
+		cs.Apply("Burning", tt.turns, tt.policy)
+		if got := cs.Remaining("Burning"); got != tt.wantTurns {
+			t.Errorf("%s reapplied for %d turns: %d turns left, want %d", tt.policy, tt.turns, got, tt.wantTurns)
+		}
+		if got := cs.Stacks("Burning"); got != tt.wantStacks {
+			t.Errorf("%s reapplied for %d turns: %d stacks, want %d", tt.policy, tt.turns, got, tt.wantStacks)
+		}
+	}
+}
+
+func TestStacksAreClearedAndCloned(t *testing.T) {
+	cs := NewConditions()
+	cs.Apply("Bleeding", 0, StackAdd)
+	cs.Apply("Bleeding", 0, StackAdd)
+	clone := cs.Clone()
+	cs.Apply("Bleeding", 0, StackAdd)
+	if clone.Stacks("Bleeding") != 2 || cs.Stacks("Bleeding") != 3 {
+		t.Errorf("stacks %d and clone %d, want 3 and 2", cs.Stacks("Bleeding"), clone.Stacks("Bleeding"))
+	}
+	cs.Remove("Bleeding")
+	cs.Add("Bleeding")
+	if got := cs.Stacks("Bleeding"); got != 1 {
+		t.Errorf("stacks %d after removing and adding again, want 1", got)
+	}
+}
+
+func TestCanTransition(t *testing.T) {
+	r := NewRegistry()
+	tests := []struct {
+		from, to Condition
+		want     bool
+	}{
+		{"Healthy", "Critical", true},
+		{"Injured", "Healthy", true},
+		{"Critical", "Healthy", false},
+		{"Dead", "Healthy", false},
+		{"Unconscious", "Dead", true},
+		{"Broken", "Worn", true},
+		{"Broken", "Pristine", false},
+		{"Poisoned", "Stunned", true},
+		{"Homebrew", "Healthy", true},
+	}
+	for _, tt := range tests {
+		if got := r.CanTransition(tt.from, tt.to); got != tt.want {
+			t.Errorf("CanTransition(%s, %s) = %v, want %v", tt.from, tt.to, got, tt.want)
+		}
+	}
+}
+
+func TestRegisterValidatesAbilityEffects(t *testing.T) {
+	r := NewRegistry()
+	def := Definition{Name: "Frightened", Severity: 1, Category: CategoryCharacter, Stacking: StackIgnore,
+		AbilityEffects: map[string]int{"courage": -2}}
+	if err := r.Register(def); err == nil {
+		t.Fatal("registered an effect on an unknown ability")
+	}
+	def.AbilityEffects = map[string]int{"charisma": -2}
+	if err := r.Register(def); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRegisterAllAndSave(t *testing.T) {
+	def := Definition{Name: "Frightened", Severity: 1, Category: CategoryCharacter, Stacking: StackIgnore}
+
+	r := NewRegistry()
+	missing := filepath.Join(t.TempDir(), "missing", "conditions.yaml")
+	err := r.RegisterAllAndSave([]Definition{def}, missing)
+	var saveErr *SaveError
+	if !errors.As(err, &saveErr) {
+		t.Fatalf("error %v, want a SaveError", err)
+	}
+	if _, ok := r.Lookup("Frightened"); ok {
+		t.Error("condition registered although it could not be saved")
+	}
+
+	path := filepath.Join(t.TempDir(), "conditions.yaml")
+	if err := r.RegisterAllAndSave([]Definition{def}, path); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(path); err != nil {
+		t.Fatal(err)
+	}
+	reloaded := NewRegistry()
+	if err := reloaded.LoadFile(path); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := reloaded.Lookup("Frightened"); !ok {
+		t.Error("saved condition missing after reloading")
+	}
+}
+
+func TestFailedWritesLeaveFileIntact(t *testing.T) {
+	original := writeData
+	t.Cleanup(func() { writeData = original })
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "conditions.yaml")
+	r := NewRegistry()
+	saved := Definition{Name: "Frightened", Severity: 1, Category: CategoryCharacter, Stacking: StackIgnore}
+	if err := r.RegisterAllAndSave([]Definition{saved}, path); err != nil {
+		t.Fatal(err)
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	failures := map[string]func(f *os.File, data []byte) error{
+		// A short write the disk did not report is caught by reading it back
+		"silent short write": func(f *os.File, data []byte) error {
+			_, err := f.Write(data[:len(data)/2])
+			return err
+		},
+		"disk full mid-write": func(f *os.File, data []byte) error {
+			if _, err := f.Write(data[:len(data)/2]); err != nil {
+				return err
+			}
+			return errors.New("no space left on device")
+		},
+	}
+	for name, failure := range failures {
+		t.Run(name, func(t *testing.T) {
+			writeData = failure
+			unsaved := Definition{Name: "Cursed", Severity: 2, Category: CategoryCharacter, Stacking: StackAdd}
+			var saveErr *SaveError
+			if err := r.RegisterAllAndSave([]Definition{unsaved}, path); !errors.As(err, &saveErr) {
+				t.Fatalf("error %v, want a SaveError", err)
+			}
+			if _, ok := r.Lookup("Cursed"); ok {
+				t.Error("condition registered although the write failed")
+			}
+			got, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != string(want) {
+				t.Errorf("file changed by a failed write:\n%s", got)
+			}
+			entries, err := os.ReadDir(dir)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(entries) != 1 {
+				t.Errorf("%d files left in the directory, want only the conditions file", len(entries))
+			}
+			reloaded := NewRegistry()
+			if err := reloaded.LoadFile(path); err != nil {
+				t.Fatal(err)
+			}
+			if _, ok := reloaded.Lookup("Frightened"); !ok {
+				t.Error("saved condition lost after a failed write")
+			}
+		})
+	}
+}
@@ -0,0 +1,98 @@
+package dice
+
+import (
+	"fmt"
+	"math/rand"
+	"strconv"
+	"strings"
+)
+
+// Limits on a single roll, so a typo like "1000000d6" can't hog the server
+const (
+	MaxDice  = 100
+	MaxSides = 1000
+)
+
+// Expression is parsed dice notation such as "2d6+3"
+type Expression struct {
+	Count    int
+	Sides    int
+	Modifier int
+}
+
+// String formats the expression back into dice notation
+func (e Expression) String() string {
+	if e.Modifier == 0 {
+		return fmt.Sprintf("%dd%d", e.Count, e.Sides)
+	}
+	return fmt.Sprintf("%dd%d%+d", e.Count, e.Sides, e.Modifier)
+}
+
+// Parse reads standard dice notation: an optional dice count, "d", the
+// number of sides and an optional "+N" or "-N" modifier. "d20" is one die
+func Parse(notation string) (Expression, error) {
+	text := strings.ToLower(strings.TrimSpace(notation))
+	countText, rest, ok := strings.Cut(text, "d")
+	if !ok {
+		return Expression{}, fmt.Errorf("invalid dice notation %q: missing \"d\"", notation)
+	}
+
+	expr := Expression{Count: 1}
+	if countText != "" {
+		count, err := strconv.Atoi(countText)
+		if err != nil {
+			return Expression{}, fmt.Errorf("invalid dice count in %q", notation)
+		}
+		expr.Count = count
+	}
+
+	sidesText := rest
+	if i := strings.IndexAny(rest, "+-"); i >= 0 {
+		sidesText = rest[:i]
+		modifier, err := strconv.Atoi(rest[i:])
+		if err != nil {
+			return Expression{}, fmt.Errorf("invalid modifier in %q", notation)
+		}
+		expr.Modifier = modifier
+	}
+	sides, err := strconv.Atoi(sidesText)
+	if err != nil {
+		return Expression{}, fmt.Errorf("invalid number of sides in %q", notation)
+	}
+	expr.Sides = sides
+
+	if expr.Count < 1 || expr.Count > MaxDice {
+		return Expression{}, fmt.Errorf("dice count must be between 1 and %d, got %d", MaxDice, expr.Count)
+	}
+	if expr.Sides < 1 || expr.Sides > MaxSides {
+		return Expression{}, fmt.Errorf("number of sides must be between 1 and %d, got %d", MaxSides, expr.Sides)
+	}
+	return expr, nil
+}
+
+// Roll rolls the expression with rng and returns the total, modifier
+// included, and the individual dice
+func (e Expression) Roll(rng *rand.Rand) (int, []int) {
+	rolls := make([]int, e.Count)
+	total := e.Modifier
+	for i := range rolls {
+		rolls[i] = rng.Intn(e.Sides) + 1
+		total += rolls[i]
+	}
+	return total, rolls
+}
+
+// Roll parses notation such as "2d6+3" and rolls it with a fresh random source
+func Roll(notation string) (int, []int, error) {
+	return RollWith(notation, rand.New(rand.NewSource(rand.Int63())))
+}
+
+// RollWith is Roll with a caller supplied random source
+func RollWith(notation string, rng *rand.Rand) (int, []int, error) {
+	expr, err := Parse(notation)
+	if err != nil {
+		return 0, nil, err
+	}
+	total, rolls := expr.Roll(rng)
+	return total, rolls, nil
+}
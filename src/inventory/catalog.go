@@ -0,0 +1,221 @@
+package inventory
+
+import (
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"sync"
+
+	"dnd-helper/src/abilities"
+	"dnd-helper/src/condition"
+)
+
+// maxSuggestionDistance is the most edits a catalog name may be away from
+// an unknown name to be suggested instead
+const maxSuggestionDistance = 3
+
+// UnknownCatalogItemError is returned for names without a template, with the
+// closest registered names as suggestions
+type UnknownCatalogItemError struct {
+	Name        string
+	Suggestions []string
+}
+
+func (e *UnknownCatalogItemError) Error() string {
+	if len(e.Suggestions) == 0 {
+		return fmt.Sprintf("unknown catalog item %q", e.Name)
+	}
+	return fmt.Sprintf("unknown catalog item %q, did you mean %s?", e.Name, strings.Join(e.Suggestions, ", "))
+}
+
+// ItemTemplate describes a kind of item, e.g. common gear, so items can be
+// created by name and quantity. Abilities are item bonuses, as for NewItem
+type ItemTemplate struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	// Weight is the weight of a single unit, nil means DefaultItemWeight
+	Weight    *float64       `json:"weight,omitempty"`
+	Abilities map[string]int `json:"abilities,omitempty"`
+	// Rarity is one of Rarities, "" means DefaultItemRarity
+	Rarity string `json:"rarity,omitempty"`
+	// Value is the gold value of a single unit
+	Value int `json:"value,omitempty"`
+	// ArmorBonus is the armor class bonus while equipped
+	ArmorBonus int `json:"armorBonus,omitempty"`
+	// Slot is one of ItemSlots, "" means DefaultItemSlot
+	Slot string `json:"slot,omitempty"`
+	// Consumable items are used up one unit at a time, applying Effect
+	Consumable bool        `json:"consumable,omitempty"`
+	Effect     *ItemEffect `json:"effect,omitempty"`
+}
+
+// New creates quantity pristine units of the template through NewItem.
+// Every item gets its own abilities, so items never share state
+func (t ItemTemplate) New(quantity int) (Item, error) {
+	var itemAbilities *abilities.Abilities
+	if t.Abilities != nil {
+		abs := abilities.NewItemAbilities(t.Abilities["strength"], t.Abilities["luck"], t.Abilities["charisma"],
+			t.Abilities["agility"], t.Abilities["perception"], t.Abilities["intelligence"])
+		itemAbilities = &abs
+	}
+	rarity := t.Rarity
+	if rarity == "" {
+		rarity = DefaultItemRarity
+	}
+	slot := t.Slot
+	if slot == "" {
+		slot = DefaultItemSlot
+	}
+	item, err := NewItem(t.Name, quantity, itemAbilities, condition.NewCondition("Pristine"), t.Description, rarity, t.Value, t.ArmorBonus, slot)
+	if err != nil {
+		return Item{}, err
+	}
+	if t.Weight != nil {
+		if err := item.SetWeight(*t.Weight); err != nil {
+			return Item{}, err
+		}
+	}
+	item.SetConsumable(t.Consumable)
+	if err := item.SetEffect(t.Effect); err != nil {
+		return Item{}, err
+	}
+	return item, nil
+}
+
+// ItemCatalog holds item templates by name, so common gear can be created
+// without its full definition. It is safe for concurrent use
+type ItemCatalog struct {
+	mu sync.RWMutex
+	// templates holds the registered templates keyed by lower-case name
+	templates map[string]ItemTemplate
+}
+
+// starterTemplates are registered into every new ItemCatalog
+var starterTemplates = []ItemTemplate{
+	{Name: "Sword", Description: "A plain steel sword", Weight: Weight(3), Abilities: map[string]int{"strength": 1}, Value: 10, Slot: SlotMainHand},
+	{Name: "Shield", Description: "A round wooden shield", Weight: Weight(6), Value: 10, ArmorBonus: 1, Slot: SlotOffHand},
+	{Name: "Rope", Description: "Fifty feet of hempen rope", Weight: Weight(2), Value: 1},
+	{Name: "Torch", Description: "Burns for an hour", Weight: Weight(1), Value: 1},
+	{Name: "Healing potion", Description: "Heals 10 hit points", Weight: Weight(0.5), Rarity: RarityUncommon, Value: 25,
+		Consumable: true, Effect: &ItemEffect{Type: ItemEffectHeal, Amount: 10}},
+}
+
+// DefaultCatalog is the catalog shared by the server
+var DefaultCatalog = NewItemCatalog()
+
+// NewItemCatalog creates a catalog pre-populated with the starter gear
+func NewItemCatalog() *ItemCatalog {
+	c := &ItemCatalog{templates: map[string]ItemTemplate{}}
+	for _, template := range starterTemplates {
+		if err := c.Register(template); err != nil {
+			panic(err)
+		}
+	}
+	return c
+}
+
+// Register adds a template or replaces the template with the same name,
+// ignoring case. The template must create a valid item
+func (c *ItemCatalog) Register(template ItemTemplate) error {
+	if _, err := template.New(1); err != nil {
+		return fmt.Errorf("catalog item %s: %w", template.Name, err)
+	}
+	if template.Effect != nil {
+		effect := *template.Effect
+		template.Effect = &effect
+	}
+	if template.Abilities != nil {
+		abs := make(map[string]int, len(template.Abilities))
+		for name, value := range template.Abilities {
+			abs[name] = value
+		}
+		template.Abilities = abs
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.templates[strings.ToLower(template.Name)] = template
+	slog.Debug("Catalog item registered", "item", template.Name)
+	return nil
+}
+
+// Lookup returns the template registered under name, ignoring case
+func (c *ItemCatalog) Lookup(name string) (ItemTemplate, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	template, ok := c.templates[strings.ToLower(strings.TrimSpace(name))]
+	return template, ok
+}
+
+// Create creates quantity units of the template registered under name.
+// Unknown names give an *UnknownCatalogItemError
+func (c *ItemCatalog) Create(name string, quantity int) (Item, error) {
+	template, ok := c.Lookup(name)
+	if !ok {
+		return Item{}, &UnknownCatalogItemError{Name: name, Suggestions: c.Suggest(name)}
+	}
+	return template.New(quantity)
+}
+
+// Templates returns every registered template sorted by name
+func (c *ItemCatalog) Templates() []ItemTemplate {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	templates := make([]ItemTemplate, 0, len(c.templates))
+	for _, template := range c.templates {
+		templates = append(templates, template)
+	}
+	sort.Slice(templates, func(i, j int) bool { return templates[i].Name < templates[j].Name })
+	return templates
+}
+
+// Suggest returns the registered names close to name, closest first: names
+// containing it or at most maxSuggestionDistance edits away
+func (c *ItemCatalog) Suggest(name string) []string {
+	name = strings.ToLower(strings.TrimSpace(name))
+	distances := map[string]int{}
+	for _, template := range c.Templates() {
+		candidate := strings.ToLower(template.Name)
+		distance := editDistance(name, candidate)
+		if name != "" && strings.Contains(candidate, name) {
+			distance = 0
+		}
+		if distance <= maxSuggestionDistance {
+			distances[template.Name] = distance
+		}
+	}
+	matches := make([]string, 0, len(distances))
+	for match := range distances {
+		matches = append(matches, match)
+	}
+	sort.Slice(matches, func(i, j int) bool {
+		if distances[matches[i]] != distances[matches[j]] {
+			return distances[matches[i]] < distances[matches[j]]
+		}
+		return matches[i] < matches[j]
+	})
+	return matches
+}
+
+// editDistance returns the Levenshtein distance between a and b
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	previous := make([]int, len(rb)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		current := make([]int, len(rb)+1)
+		current[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous = current
+	}
+	return previous[len(rb)]
+}
@@ -0,0 +1,79 @@
+package inventory
+
+import "fmt"
+
+// Effect types of consumable items
+const (
+	// ItemEffectHeal heals Amount hit points
+	ItemEffectHeal = "heal"
+	// ItemEffectMana restores Amount mana
+	ItemEffectMana = "mana"
+	// ItemEffectAbility adds Amount to Ability for Duration rounds
+	ItemEffectAbility = "ability"
+)
+
+// ItemEffect describes what using a consumable item does
+type ItemEffect struct {
+	Type     string `json:"type"`
+	Amount   int    `json:"amount"`
+	Ability  string `json:"ability,omitempty"`
+	Duration int    `json:"duration,omitempty"`
+}
+
+// Validate checks the effect is complete for its type. The ability name is
+// checked against the character using the item
+func (e ItemEffect) Validate() error {
+	switch e.Type {
+	case ItemEffectHeal, ItemEffectMana:
+		if e.Amount <= 0 {
+			return fmt.Errorf("%s effect amount %d must be positive", e.Type, e.Amount)
+		}
+	case ItemEffectAbility:
+		if e.Ability == "" {
+			return fmt.Errorf("ability effect needs an ability")
+		}
+		if e.Amount == 0 {
+			return fmt.Errorf("ability effect amount cannot be 0")
+		}
+		if e.Duration <= 0 {
+			return fmt.Errorf("ability effect duration %d must be a positive number of rounds", e.Duration)
+		}
+	default:
+		return fmt.Errorf("unknown effect type %q, expected %s, %s or %s", e.Type, ItemEffectHeal, ItemEffectMana, ItemEffectAbility)
+	}
+	return nil
+}
+
+// SetConsumable marks the item as used up one unit at a time
+func (i *Item) SetConsumable(consumable bool) {
+	i.consumable = consumable
+}
+
+// IsConsumable reports whether the item can be used
+func (i *Item) IsConsumable() bool {
+	return i.consumable
+}
+
+// SetEffect sets what using the item does, nil for nothing, e.g. rations
+func (i *Item) SetEffect(effect *ItemEffect) error {
+	if effect == nil {
+		i.effect = nil
+		return nil
+	}
+	if err := effect.Validate(); err != nil {
+		return err
+	}
+	copied := *effect
+	i.effect = &copied
+	return nil
+}
+
+// GetEffect returns a copy of the effect of using the item, nil if it has
+// none
+func (i *Item) GetEffect() *ItemEffect {
+	if i.effect == nil {
+		return nil
+	}
+	copied := *i.effect
+	return &copied
+}
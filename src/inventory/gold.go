@@ -0,0 +1,47 @@
+package inventory
+
+import (
+	"fmt"
+	"log/slog"
+)
+
+// InsufficientFundsError is returned when an inventory cannot pay an amount
+// of gold
+type InsufficientFundsError struct {
+	Have int
+	Need int
+}
+
+func (e *InsufficientFundsError) Error() string {
+	return fmt.Sprintf("insufficient gold: have %d, need %d", e.Have, e.Need)
+}
+
+// GetGold returns the gold in the inventory
+func (inv *Inventory) GetGold() int {
+	return inv.gold
+}
+
+// AddGold adds n gold. Negative amounts are rejected, use SpendGold
+func (inv *Inventory) AddGold(n int) error {
+	if n < 0 {
+		return fmt.Errorf("cannot add a negative amount of gold (%d)", n)
+	}
+	inv.gold += n
+	slog.Debug("Gold added", "amount", n, "gold", inv.gold)
+	return nil
+}
+
+// SpendGold removes n gold. Gold never goes below zero, so spending more
+// than the inventory holds fails with an *InsufficientFundsError and leaves
+// the gold unchanged
+func (inv *Inventory) SpendGold(n int) error {
+	if n < 0 {
+		return fmt.Errorf("cannot spend a negative amount of gold (%d)", n)
+	}
+	if inv.gold < n {
+		return &InsufficientFundsError{Have: inv.gold, Need: n}
+	}
+	inv.gold -= n
+	slog.Debug("Gold spent", "amount", n, "gold", inv.gold)
+	return nil
+}
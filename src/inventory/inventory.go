@@ -2,10 +2,15 @@ package inventory
 
 import (
 	"fmt"
-	"log"
+	"log/slog"
+	"math"
+	"sort"
+	"strings"
 
 	"dnd-helper/src/abilities"
 	"dnd-helper/src/condition"
+	"dnd-helper/src/uuid"
+	"dnd-helper/src/validation"
 )
 
 const (
@@ -14,19 +19,91 @@ const (
 	DefaultItemQuantity    = 0.0
 	DefaultItemDescription = ""
 	DefaultItemCondition   = condition.Condition("N/A")
+	DefaultItemWeight      = 0.0
+	DefaultItemRarity      = RarityCommon
+	DefaultItemSlot        = SlotNone
 
 	// Ability settings for items
 	MinItemAbilityValue = 1
 	MaxItemAbilityValue = 4
+
+	// MaxArmorBonus is the highest armor class bonus an item can grant
+	MaxArmorBonus = 5
+)
+
+// Item rarities, from the most to the least common
+const (
+	RarityCommon    = "Common"
+	RarityUncommon  = "Uncommon"
+	RarityRare      = "Rare"
+	RarityEpic      = "Epic"
+	RarityLegendary = "Legendary"
 )
 
-// Item represents a single item in the inventory
+// Rarities lists the valid item rarities, from the most to the least common
+var Rarities = []string{RarityCommon, RarityUncommon, RarityRare, RarityEpic, RarityLegendary}
+
+// Item slots, where an item is worn or held when equipped
+const (
+	SlotHead     = "head"
+	SlotChest    = "chest"
+	SlotMainHand = "mainHand"
+	SlotOffHand  = "offHand"
+	SlotRing     = "ring"
+	SlotNone     = "none" // cannot be equipped
+)
+
+// ItemSlots lists the valid item slots
+var ItemSlots = []string{SlotHead, SlotChest, SlotMainHand, SlotOffHand, SlotRing, SlotNone}
+
+// validateSlot checks slot is one of ItemSlots
+func validateSlot(slot string) error {
+	for _, valid := range ItemSlots {
+		if slot == valid {
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown slot %q, expected one of %v", slot, ItemSlots)
+}
+
+// validateRarity checks rarity is one of Rarities
+func validateRarity(rarity string) error {
+	for _, valid := range Rarities {
+		if rarity == valid {
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown rarity %q, expected one of %v", rarity, Rarities)
+}
+
+// Item represents a single item in the inventory. Items are identified by
+// their ID; the name is for display and may be shared by distinct items
 type Item struct {
+	id          string // unique, assigned by NewItem
 	Name        string
 	quantity    int
 	abilities   *abilities.Abilities
 	condition   condition.Condition
 	description string
+	weight      float64 // weight of a single unit
+	rarity      string  // one of Rarities
+	value       int     // gold value of a single unit
+	armorBonus  int     // armor class bonus while equipped, 0 to MaxArmorBonus
+	slot        string  // one of ItemSlots
+	consumable  bool
+	effect      *ItemEffect // what using the item does, nil for nothing
+}
+
+// Weight returns a pointer to weight, for the optional weights of item
+// templates and kits where nil means DefaultItemWeight and 0 weightless
+func Weight(weight float64) *float64 {
+	return &weight
+}
+
+// GetID returns the unique ID assigned at creation. Renaming an item keeps
+// its ID
+func (i *Item) GetID() string {
+	return i.id
 }
 
 func (i *Item) SetName(name string) {
@@ -69,46 +146,164 @@ func (i *Item) GetDescription() string {
 	return i.description
 }
 
-// Inventory represents a collection of items
+// SetWeight sets the weight of a single unit of the item, which must be a
+// finite number of at least 0
+func (i *Item) SetWeight(weight float64) error {
+	if weight < 0 || math.IsNaN(weight) || math.IsInf(weight, 0) {
+		return fmt.Errorf("weight %g must be a number of at least 0", weight)
+	}
+	i.weight = weight
+	return nil
+}
+
+// GetWeight returns the weight of a single unit of the item
+func (i *Item) GetWeight() float64 {
+	return i.weight
+}
+
+// GetTotalWeight returns the weight of the whole stack, quantity × weight
+func (i *Item) GetTotalWeight() float64 {
+	return float64(i.quantity) * i.weight
+}
+
+// SetRarity sets the rarity, which must be one of Rarities
+func (i *Item) SetRarity(rarity string) error {
+	if err := validateRarity(rarity); err != nil {
+		return err
+	}
+	i.rarity = rarity
+	return nil
+}
+
+func (i *Item) GetRarity() string {
+	return i.rarity
+}
+
+// SetValue sets the gold value of a single unit of the item, which cannot
+// be negative
+func (i *Item) SetValue(value int) error {
+	if value < 0 {
+		return fmt.Errorf("item value %d cannot be negative", value)
+	}
+	i.value = value
+	return nil
+}
+
+// GetValue returns the gold value of a single unit of the item
+func (i *Item) GetValue() int {
+	return i.value
+}
+
+// SetArmorBonus sets the armor class bonus the item grants while equipped,
+// which must be in [0, MaxArmorBonus]
+func (i *Item) SetArmorBonus(bonus int) error {
+	if bonus < 0 || bonus > MaxArmorBonus {
+		return fmt.Errorf("armor bonus %d must be in range [0, %d]", bonus, MaxArmorBonus)
+	}
+	i.armorBonus = bonus
+	return nil
+}
+
+func (i *Item) GetArmorBonus() int {
+	return i.armorBonus
+}
+
+// SetSlot sets where the item is equipped, which must be one of ItemSlots
+func (i *Item) SetSlot(slot string) error {
+	if err := validateSlot(slot); err != nil {
+		return err
+	}
+	i.slot = slot
+	return nil
+}
+
+// GetSlot returns where the item is equipped, SlotNone if it cannot be
+func (i *Item) GetSlot() string {
+	return i.slot
+}
+
+// GetTotalValue returns the gold value of the whole stack
+func (i *Item) GetTotalValue() int {
+	return i.value * i.quantity
+}
+
+// Inventory represents a collection of items and a purse of gold
 type Inventory struct {
 	Items []Item
+	gold  int // never negative
 }
 
-// NewItem creates a new item with validation
-func NewItem(name string, quantity int, abilities *abilities.Abilities, condition condition.Condition, description string) (Item, error) {
-	if quantity <= 0 {
-		return Item{}, fmt.Errorf("item quantity cannot be negative or zero")
+// NewItem creates a new item with validation. All problems are returned at
+// once as validation.ValidationErrors. Items that go into an inventory must
+// be created here, so the quantity must be positive. rarity must be one of
+// Rarities, value, the gold value of a single unit, cannot be negative,
+// armorBonus must be in [0, MaxArmorBonus] and slot must be one of ItemSlots
+func NewItem(name string, quantity int, abilities *abilities.Abilities, condition condition.Condition, description string, rarity string, value int, armorBonus int, slot string) (Item, error) {
+	return newItem(name, quantity, 1, abilities, condition, description, rarity, value, armorBonus, slot)
+}
+
+// NewCatalogItem creates an item that is known but currently unowned, e.g.
+// an entry of a shop or item catalog, so quantity 0 is allowed. Catalog items
+// describe items rather than hold them; use NewItem for inventory items so
+// inventories never fill up with empty stacks by accident. An empty item
+// that does end up in an inventory counts as present but has nothing to remove
+func NewCatalogItem(name string, quantity int, abilities *abilities.Abilities, condition condition.Condition, description string, rarity string, value int, armorBonus int, slot string) (Item, error) {
+	return newItem(name, quantity, 0, abilities, condition, description, rarity, value, armorBonus, slot)
+}
+
+// newItem validates and creates an item with at least minQuantity units
+func newItem(name string, quantity int, minQuantity int, abilities *abilities.Abilities, condition condition.Condition, description string, rarity string, value int, armorBonus int, slot string) (Item, error) {
+	var errs validation.ValidationErrors
+	if strings.TrimSpace(name) == "" {
+		errs.Add("name", "item name cannot be empty")
+	}
+	if quantity < minQuantity {
+		if minQuantity > 0 {
+			errs.Add("quantity", "item quantity cannot be negative or zero")
+		} else {
+			errs.Add("quantity", "item quantity cannot be negative")
+		}
+	}
+
+	if err := validateRarity(rarity); err != nil {
+		errs.Add("rarity", "%v", err)
+	}
+	if value < 0 {
+		errs.Add("value", "item value %d cannot be negative", value)
+	}
+	if armorBonus < 0 || armorBonus > MaxArmorBonus {
+		errs.Add("armorBonus", "armor bonus %d must be in range [0, %d]", armorBonus, MaxArmorBonus)
+	}
+	if err := validateSlot(slot); err != nil {
+		errs.Add("slot", "%v", err)
 	}
 
 	// Validate abilities if provided
 	if abilities != nil {
 		abs := abilities.GetAllAbilities()
-		itemAbilities := []struct {
-			name  string
-			value int
-		}{
-			{"strength", abs["strength"]},
-			{"luck", abs["luck"]},
-			{"charisma", abs["charisma"]},
-			{"agility", abs["agility"]},
-			{"perception", abs["perception"]},
-			{"intelligence", abs["intelligence"]},
-		}
-
-		for _, ability := range itemAbilities {
-			if ability.value != 0 && (ability.value < MinItemAbilityValue || ability.value > MaxItemAbilityValue) {
-				return Item{}, fmt.Errorf("item ability %s value %d must be 0 or in range [%d, %d]",
-					ability.name, ability.value, MinItemAbilityValue, MaxItemAbilityValue)
+		for _, name := range []string{"strength", "luck", "charisma", "agility", "perception", "intelligence"} {
+			if value := abs[name]; value != 0 && (value < MinItemAbilityValue || value > MaxItemAbilityValue) {
+				errs.Add("abilities."+name, "item ability %s value %d must be 0 or in range [%d, %d]",
+					name, value, MinItemAbilityValue, MaxItemAbilityValue)
 			}
 		}
 	}
+	if err := errs.Err(); err != nil {
+		return Item{}, err
+	}
 
 	return Item{
+		id:          uuid.New(),
 		Name:        name,
 		quantity:    quantity,
 		abilities:   abilities,
 		condition:   condition,
 		description: description,
+		weight:      DefaultItemWeight,
+		rarity:      rarity,
+		value:       value,
+		armorBonus:  armorBonus,
+		slot:        slot,
 	}, nil
 }
 
@@ -119,44 +314,200 @@ func NewInventory() *Inventory {
 	}
 }
 
-// AddItem adds an item to the inventory
+// Clone returns a deep copy of the inventory. Items and their abilities are
+// copied, so changes to the copy never affect the original
+func (inv *Inventory) Clone() *Inventory {
+	clone := &Inventory{
+		Items: make([]Item, len(inv.Items)),
+		gold:  inv.gold,
+	}
+	for i, item := range inv.Items {
+		clone.Items[i] = item.clone()
+	}
+	return clone
+}
+
+// clone returns a copy of the item that shares no abilities or effect with
+// the original
+func (i Item) clone() Item {
+	if i.abilities != nil {
+		abs := i.abilities.Clone()
+		i.abilities = &abs
+	}
+	i.effect = i.GetEffect()
+	return i
+}
+
+// AddItem adds an item to the inventory. An item with the same name and
+// condition as an existing stack is merged into it, keeping the ID of the
+// stack. Otherwise it is appended with its own ID, or a new one if the
+// inventory already holds an item with that ID
 func (inv *Inventory) AddItem(item Item) {
 	// Check if item with same name already exists
 	for i := range inv.Items {
 		if inv.Items[i].Name == item.Name && inv.Items[i].condition == item.condition {
 			// Stack items by adding quantities
 			inv.Items[i].quantity += item.quantity
-			log.Printf("Added %d of %s to existing stack. New quantity: %d", item.quantity, item.Name, inv.Items[i].quantity)
+			slog.Debug("Item stacked", "item", item.Name, "added", item.quantity, "quantity", inv.Items[i].quantity)
 			return
 		}
 	}
 	// Add as new item
+	if item.id == "" || inv.GetItemByID(item.id) != nil {
+		item.id = uuid.New()
+	}
 	inv.Items = append(inv.Items, item)
-	log.Printf("Added new item: %s (quantity: %d)", item.Name, item.quantity)
+	slog.Debug("Item added", "item", item.Name, "quantity", item.quantity)
 }
 
-// RemoveItem removes a specific quantity of an item from inventory
+// Merge adds a copy of every item of other through AddItem, so items with
+// the same name and condition stack and the rest are appended. other is
+// left unchanged, e.g. when looting a body the caller clears it afterwards.
+// Gold is not merged
+func (inv *Inventory) Merge(other *Inventory) {
+	if other == nil || other == inv {
+		slog.Debug("Inventory not merged, source is empty or the inventory itself")
+		return
+	}
+	for _, item := range other.Items {
+		inv.AddItem(item.clone())
+	}
+}
+
+// RemoveItem removes a specific quantity of an item from inventory by name,
+// a convenience for callers without an ID; see RemoveItemByID. Stacks
+// with the same name but different conditions are drained in inventory order
+// (oldest first) until quantity units are removed. Nothing is removed unless
+// all stacks together hold enough. An empty (catalog) item is present but
+// cannot give up any units, and is only dropped when removing units brings
+// a stack down to 0
+//
+// RemoveItem takes from whichever stack comes first, so a pristine sword may
+// go before a broken one. Use RemoveItemWithCondition to pick the stack
 func (inv *Inventory) RemoveItem(name string, quantity int) error {
-	for i := range inv.Items {
-		if inv.Items[i].Name == name {
-			if inv.Items[i].quantity < quantity {
-				return fmt.Errorf("insufficient quantity: have %d, need %d", inv.Items[i].quantity, quantity)
-			}
-			inv.Items[i].quantity -= quantity
-			if inv.Items[i].quantity == 0 {
+	_, err := inv.removeItem(name, quantity, func(Item) bool { return true })
+	return err
+}
+
+// RemoveItemByID removes quantity units from the item with the given ID,
+// dropping it when no units are left
+func (inv *Inventory) RemoveItemByID(id string, quantity int) error {
+	item := inv.GetItemByID(id)
+	if item == nil {
+		return fmt.Errorf("item with ID %s not found in inventory", id)
+	}
+	_, err := inv.removeItem(item.Name, quantity, func(other Item) bool { return other.id == id })
+	return err
+}
+
+// RemoveItemByCondition removes quantity units from the stack of name in the
+// given condition only
+func (inv *Inventory) RemoveItemByCondition(name string, cond condition.Condition, quantity int) error {
+	_, err := inv.removeItem(name, quantity, func(item Item) bool { return item.condition == cond })
+	return err
+}
+
+// StackRemoval reports how many units were taken from one stack
+type StackRemoval struct {
+	Name      string              `json:"name"`
+	Condition condition.Condition `json:"condition"`
+	Removed   int                 `json:"removed"`
+	Remaining int                 `json:"remaining"` // 0 means the stack is gone
+}
+
+// RemoveItemWithCondition removes quantity units of name in the given
+// condition and returns the stacks they were taken from. The zero
+// condition means any condition, draining the stacks in inventory order
+// like RemoveItem
+func (inv *Inventory) RemoveItemWithCondition(name string, cond condition.Condition, quantity int) ([]StackRemoval, error) {
+	if cond == "" {
+		return inv.removeItem(name, quantity, func(Item) bool { return true })
+	}
+	return inv.removeItem(name, quantity, func(item Item) bool { return item.condition == cond })
+}
+
+// removeItem drains the stacks of name accepted by match in inventory order
+// and returns what was taken from each
+func (inv *Inventory) removeItem(name string, quantity int, match func(Item) bool) ([]StackRemoval, error) {
+	if quantity <= 0 {
+		return nil, fmt.Errorf("quantity %d to remove must be positive", quantity)
+	}
+	found, have := false, 0
+	for _, item := range inv.Items {
+		if item.Name == name && match(item) {
+			found = true
+			have += item.quantity
+		}
+	}
+	if !found {
+		return nil, fmt.Errorf("item %s not found in inventory", name)
+	}
+	if have < quantity {
+		return nil, fmt.Errorf("insufficient quantity: have %d, need %d", have, quantity)
+	}
+
+	var removals []StackRemoval
+	remaining := quantity
+	kept := inv.Items[:0]
+	for _, item := range inv.Items {
+		if remaining > 0 && item.quantity > 0 && item.Name == name && match(item) {
+			taken := min(item.quantity, remaining)
+			item.quantity -= taken
+			remaining -= taken
+			removals = append(removals, StackRemoval{Name: name, Condition: item.condition, Removed: taken, Remaining: item.quantity})
+			if item.quantity == 0 {
 				// Remove item from inventory if quantity reaches 0
-				inv.Items = append(inv.Items[:i], inv.Items[i+1:]...)
-				log.Printf("Removed %s from inventory (depleted)", name)
-			} else {
-				log.Printf("Removed %d of %s. Remaining: %d", quantity, name, inv.Items[i].quantity)
+				slog.Debug("Item removed, depleted", "item", name, "condition", item.condition)
+				continue
 			}
-			return nil
+			slog.Debug("Item removed", "item", name, "condition", item.condition, "removed", taken, "quantity", item.quantity)
 		}
+		kept = append(kept, item)
+	}
+	inv.Items = kept
+	return removals, nil
+}
+
+// SplitStack takes quantity units off the first stack of name and returns
+// them as an independent item with a new ID and the same name, condition,
+// abilities, description and weight, ready to be added to another
+// inventory. Splitting off the whole stack removes it
+func (inv *Inventory) SplitStack(name string, quantity int) (Item, error) {
+	if quantity <= 0 {
+		return Item{}, fmt.Errorf("quantity %d to split must be positive", quantity)
+	}
+	stack := inv.GetItem(name)
+	if stack == nil {
+		return Item{}, fmt.Errorf("item %s not found in inventory", name)
+	}
+	if stack.quantity < quantity {
+		return Item{}, fmt.Errorf("insufficient quantity: have %d, need %d", stack.quantity, quantity)
+	}
+
+	split := stack.clone()
+	split.id = uuid.New()
+	split.quantity = quantity
+	if err := inv.RemoveItemByCondition(name, stack.condition, quantity); err != nil {
+		return Item{}, err
 	}
-	return fmt.Errorf("item %s not found in inventory", name)
+	slog.Debug("Item split off its stack", "item", name, "quantity", quantity)
+	return split, nil
 }
 
-// GetItem returns a pointer to an item by name, or nil if not found
+// GetItemByID returns a pointer to the item with the given ID, or nil if not
+// found
+func (inv *Inventory) GetItemByID(id string) *Item {
+	for i := range inv.Items {
+		if inv.Items[i].id == id {
+			return &inv.Items[i]
+		}
+	}
+	return nil
+}
+
+// GetItem returns a pointer to the first item with the given name, or nil if
+// not found. Names are not unique, so this is a convenience for callers
+// without an ID; see GetItemByID
 func (inv *Inventory) GetItem(name string) *Item {
 	for i := range inv.Items {
 		if inv.Items[i].Name == name {
@@ -181,16 +532,21 @@ func (inv *Inventory) HasItem(name string, quantity int) bool {
 	return false
 }
 
-// ChangeItem modifies fields of an item identified by name
-func (inv *Inventory) ChangeItem(name string, fields []string, newVal any) *Item {
+// ChangeItem modifies fields of an item identified by its ID, or by name as
+// a convenience when no item has that ID. The item keeps its ID when renamed
+func (inv *Inventory) ChangeItem(ref string, fields []string, newVal any) *Item {
 	if len(inv.Items) == 0 {
 		return nil
 	}
 
-	item := inv.GetItem(name)
+	item := inv.GetItemByID(ref)
+	if item == nil {
+		item = inv.GetItem(ref)
+	}
 	if item == nil {
 		return nil
 	}
+	name := item.Name
 
 	for _, field := range fields {
 		switch field {
@@ -210,20 +566,80 @@ func (inv *Inventory) ChangeItem(name string, fields []string, newVal any) *Item
 			if v, ok := newVal.(string); ok {
 				item.SetDescription(v)
 			}
+		case "weight":
+			weight, ok := newVal.(float64)
+			if v, isInt := newVal.(int); isInt {
+				weight, ok = float64(v), true
+			}
+			if ok {
+				if err := item.SetWeight(weight); err != nil {
+					slog.Warn("Item not changed", "item", name, "error", err)
+					return nil
+				}
+			}
+		case "rarity":
+			if v, ok := newVal.(string); ok {
+				if err := item.SetRarity(v); err != nil {
+					slog.Warn("Item not changed", "item", name, "error", err)
+					return nil
+				}
+			}
+		case "value":
+			if v, ok := newVal.(int); ok {
+				if err := item.SetValue(v); err != nil {
+					slog.Warn("Item not changed", "item", name, "error", err)
+					return nil
+				}
+			}
+		case "slot":
+			if v, ok := newVal.(string); ok {
+				if err := item.SetSlot(v); err != nil {
+					slog.Warn("Item not changed", "item", name, "error", err)
+					return nil
+				}
+			}
+		case "consumable":
+			if v, ok := newVal.(bool); ok {
+				item.SetConsumable(v)
+			}
+		case "effect":
+			if v, ok := newVal.(*ItemEffect); ok {
+				if err := item.SetEffect(v); err != nil {
+					slog.Warn("Item not changed", "item", name, "error", err)
+					return nil
+				}
+			}
+		case "armorBonus":
+			if v, ok := newVal.(int); ok {
+				if err := item.SetArmorBonus(v); err != nil {
+					slog.Warn("Item not changed", "item", name, "error", err)
+					return nil
+				}
+			}
 		case "abilities":
 			if v, ok := newVal.(*abilities.Abilities); ok {
 				item.SetAbilities(v)
 			}
 		default:
-			log.Printf("Unknown field: %s", field)
+			slog.Warn("Item not changed, unknown field", "item", name, "field", field)
 			return nil
 		}
 	}
 	return item
 }
 
-// GetTotalWeight returns the total quantity of all items (if representing weight)
-func (inv *Inventory) GetTotalWeight() int {
+// GetTotalWeight returns the total weight of all items, the sum of quantity
+// × weight
+func (inv *Inventory) GetTotalWeight() float64 {
+	total := 0.0
+	for _, item := range inv.Items {
+		total += item.GetTotalWeight()
+	}
+	return total
+}
+
+// GetTotalQuantity returns the number of units of all items
+func (inv *Inventory) GetTotalQuantity() int {
 	total := 0
 	for _, item := range inv.Items {
 		total += item.quantity
@@ -231,18 +647,64 @@ func (inv *Inventory) GetTotalWeight() int {
 	return total
 }
 
-// Clear removes all items from the inventory
+// TotalValue returns the total gold value of all items
+func (inv *Inventory) TotalValue() int {
+	total := 0
+	for _, item := range inv.Items {
+		total += item.GetTotalValue()
+	}
+	return total
+}
+
+// AbilityBonuses sums the ability values of every item carrying abilities
+func (inv *Inventory) AbilityBonuses() map[string]int {
+	bonuses := map[string]int{}
+	for _, item := range inv.Items {
+		if item.abilities == nil {
+			continue
+		}
+		for name, value := range item.abilities.GetAllAbilities() {
+			bonuses[name] += value
+		}
+	}
+	return bonuses
+}
+
+// ItemsBoosting returns copies of the items with a positive bonus to ability,
+// the largest bonus first and in inventory order between equal bonuses.
+// Items without ability data never boost anything
+func (inv *Inventory) ItemsBoosting(ability string) []Item {
+	boosting := []Item{}
+	for _, item := range inv.Items {
+		if item.abilities != nil && item.abilities.GetAllAbilities()[ability] > 0 {
+			boosting = append(boosting, item.clone())
+		}
+	}
+	sort.SliceStable(boosting, func(i, j int) bool {
+		return boosting[i].abilities.GetAllAbilities()[ability] > boosting[j].abilities.GetAllAbilities()[ability]
+	})
+	return boosting
+}
+
+// Clear removes all items from the inventory, leaving the gold
 func (inv *Inventory) Clear() {
 	inv.Items = []Item{}
-	log.Printf("Inventory cleared")
+	slog.Debug("Inventory cleared")
 }
 
 func (inv *Inventory) String() string {
-	log.Printf("Inventory contains %d items", len(inv.Items))
 	result := "Inventory:\n"
 	for _, item := range inv.Items {
-		result += fmt.Sprintf("Name: %s, Quantity: %d, Condition: %s, Description: %s\n", item.Name, item.quantity, item.condition.String(), item.description)
+		result += fmt.Sprintf("Name: %s, Quantity: %d, Weight: %.1f (%.1f total), Condition: %s, Description: %s\n",
+			item.Name, item.quantity, item.weight, item.GetTotalWeight(), item.condition.String(), item.description)
 	}
-	result += fmt.Sprintf("Total weight: %d", inv.GetTotalWeight())
+	result += fmt.Sprintf("Total weight: %.1f\n", inv.GetTotalWeight())
+	result += fmt.Sprintf("Gold: %d", inv.gold)
 	return result
 }
+
+// LogState logs the items and the total weight for debugging, so String can
+// stay free of side effects
+func (inv *Inventory) LogState() {
+	slog.Debug("Inventory", "inventory", inv.String())
+}
@@ -0,0 +1,193 @@
+package inventory
+
+import (
+	"dnd-helper/src/condition"
+	"log/slog"
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestMain(m *testing.M) {
+	slog.SetDefault(slog.New(slog.DiscardHandler))
+	os.Exit(m.Run())
+}
+
+const (
+	broken   = condition.Condition("Broken")
+	pristine = condition.Condition("Pristine")
+)
+
+// swords returns an inventory with 2 broken swords followed by 3 pristine ones
+func swords(t *testing.T) *Inventory {
+	t.Helper()
+	inv := NewInventory()
+	for _, stack := range []struct {
+		cond     condition.Condition
+		quantity int
+	}{{broken, 2}, {pristine, 3}} {
+		item, err := NewItem("Sword", stack.quantity, nil, stack.cond, "", RarityCommon, 10, 0, SlotMainHand)
+		if err != nil {
+			t.Fatal(err)
+		}
+		inv.AddItem(item)
+	}
+	return inv
+}
+
+// quantities returns the quantity of each stack by condition
+func quantities(inv *Inventory) map[condition.Condition]int {
+	got := map[condition.Condition]int{}
+	for _, item := range inv.Items {
+		got[item.GetCondition()] = item.GetQuantity()
+	}
+	return got
+}
+
+func TestRemoveItemWithCondition(t *testing.T) {
+	tests := []struct {
+		name     string
+		cond     condition.Condition
+		quantity int
+		want     []StackRemoval
+		left     map[condition.Condition]int
+	}{
+		{"pristine only", pristine, 1,
+			[]StackRemoval{{Name: "Sword", Condition: pristine, Removed: 1, Remaining: 2}},
+			map[condition.Condition]int{broken: 2, pristine: 2}},
+		{"partial depletion of the broken stack", broken, 1,
+			[]StackRemoval{{Name: "Sword", Condition: broken, Removed: 1, Remaining: 1}},
+			map[condition.Condition]int{broken: 1, pristine: 3}},
+		{"whole stack", broken, 2,
+			[]StackRemoval{{Name: "Sword", Condition: broken, Removed: 2, Remaining: 0}},
+			map[condition.Condition]int{pristine: 3}},
+		{"any condition spans stacks", "", 3,
+			[]StackRemoval{
+				{Name: "Sword", Condition: broken, Removed: 2, Remaining: 0},
+				{Name: "Sword", Condition: pristine, Removed: 1, Remaining: 2},
+			},
+			map[condition.Condition]int{pristine: 2}},
+		{"any condition within the first stack", "", 1,
+			[]StackRemoval{{Name: "Sword", Condition: broken, Removed: 1, Remaining: 1}},
+			map[condition.Condition]int{broken: 1, pristine: 3}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			inv := swords(t)
+			got, err := inv.RemoveItemWithCondition("Sword", tt.cond, tt.quantity)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("removals %+v, want %+v", got, tt.want)
+			}
+			if left := quantities(inv); !reflect.DeepEqual(left, tt.left) {
+				t.Errorf("stacks left %v, want %v", left, tt.left)
+			}
+		})
+	}
+}
+
+func TestRemoveItemWithConditionFails(t *testing.T) {
+	tests := []struct {
+		name     string
+		item     string
+		cond     condition.Condition
+		quantity int
+	}{
+		{"unknown item", "Axe", "", 1},
+		{"no stack in the condition", "Sword", condition.Condition("Rusty"), 1},
+		{"more than the stack holds", "Sword", broken, 3},
+		{"more than all stacks hold", "Sword", "", 6},
+		{"zero quantity", "Sword", pristine, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			inv := swords(t)
+			before := quantities(inv)
+			if removals, err := inv.RemoveItemWithCondition(tt.item, tt.cond, tt.quantity); err == nil {
+				t.Errorf("removal succeeded with %+v", removals)
+			}
+			if left := quantities(inv); !reflect.DeepEqual(left, before) {
+				t.Errorf("a failed removal changed the stacks from %v to %v", before, left)
+			}
+		})
+	}
+}
+
+func TestRemoveItemDrainsOldestStackFirst(t *testing.T) {
+	inv := swords(t)
+	if err := inv.RemoveItem("Sword", 4); err != nil {
+		t.Fatal(err)
+	}
+	if left := quantities(inv); !reflect.DeepEqual(left, map[condition.Condition]int{pristine: 1}) {
+		t.Errorf("stacks left %v, want 1 pristine sword", left)
+	}
+}
+
+func TestItemWeights(t *testing.T) {
+	tests := []struct {
+		name string
+		make func() (Item, error)
+		want float64
+	}{
+		{"new item", func() (Item, error) {
+			return NewItem("Pebble", 1, nil, pristine, "", RarityCommon, 0, 0, SlotNone)
+		}, 0},
+		{"template without a weight", func() (Item, error) {
+			return ItemTemplate{Name: "Pebble"}.New(1)
+		}, DefaultItemWeight},
+		{"weightless template", func() (Item, error) {
+			return ItemTemplate{Name: "Feather", Weight: Weight(0)}.New(1)
+		}, 0},
+		{"heavy template", func() (Item, error) {
+			return ItemTemplate{Name: "Anvil", Weight: Weight(50)}.New(1)
+		}, 50},
+		{"legacy JSON without a weight", func() (Item, error) {
+			var item Item
+			err := item.UnmarshalJSON([]byte(`{"name":"Pebble","quantity":1,"condition":"Pristine"}`))
+			return item, err
+		}, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			item, err := tt.make()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if item.GetWeight() != tt.want {
+				t.Errorf("weight %g, want %g", item.GetWeight(), tt.want)
+			}
+		})
+	}
+	if _, err := (ItemTemplate{Name: "Balloon", Weight: Weight(-1)}).New(1); err == nil {
+		t.Error("a template with a negative weight created an item")
+	}
+}
+
+func TestNewItemValidatesArmorAndValue(t *testing.T) {
+	for _, bonus := range []int{0, MaxArmorBonus} {
+		if _, err := NewItem("Shield", 1, nil, pristine, "", RarityCommon, 0, bonus, SlotOffHand); err != nil {
+			t.Errorf("armor bonus %d: %v", bonus, err)
+		}
+	}
+	for _, bonus := range []int{-1, MaxArmorBonus + 1} {
+		if _, err := NewItem("Shield", 1, nil, pristine, "", RarityCommon, 0, bonus, SlotOffHand); err == nil {
+			t.Errorf("NewItem accepted armor bonus %d", bonus)
+		}
+	}
+	if _, err := NewItem("Shield", 1, nil, pristine, "", RarityCommon, -1, 0, SlotOffHand); err == nil {
+		t.Error("NewItem accepted a negative value")
+	}
+
+	item, err := NewItem("Shield", 1, nil, pristine, "", RarityCommon, 10, 1, SlotOffHand)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := item.SetValue(-5); err == nil {
+		t.Error("SetValue accepted a negative value")
+	}
+	if item.GetValue() != 10 {
+		t.Errorf("value %d after a rejected SetValue, want 10", item.GetValue())
+	}
+}
@@ -0,0 +1,145 @@
+package inventory
+
+import (
+	"dnd-helper/src/abilities"
+	"dnd-helper/src/condition"
+	"encoding/json"
+	"fmt"
+)
+
+// itemJSON is the JSON form of an item. Item abilities are bonuses outside
+// the point-buy budget, so they are plain values rather than a full abilities
+// document. Weight is the weight of a single unit, DefaultItemWeight if
+// omitted, Rarity DefaultItemRarity if omitted and Slot DefaultItemSlot if
+// omitted. ID is kept when present
+// and assigned otherwise
+type itemJSON struct {
+	ID          string                  `json:"id"`
+	Name        string                  `json:"name"`
+	Quantity    int                     `json:"quantity"`
+	Condition   string                  `json:"condition"`
+	Description string                  `json:"description"`
+	Weight      *float64                `json:"weight,omitempty"`
+	Rarity      string                  `json:"rarity"`
+	Value       int                     `json:"value"`
+	ArmorBonus  int                     `json:"armorBonus"`
+	Slot        string                  `json:"slot"`
+	Consumable  bool                    `json:"consumable"`
+	Effect      *ItemEffect             `json:"effect,omitempty"`
+	Abilities   abilities.AbilityValues `json:"abilities,omitempty"`
+}
+
+// inventoryJSON is the JSON form of an inventory
+type inventoryJSON struct {
+	Items []Item `json:"items"`
+	Gold  int    `json:"gold"`
+}
+
+// MarshalJSON encodes the item as its JSON document
+func (i Item) MarshalJSON() ([]byte, error) {
+	return json.Marshal(i.document())
+}
+
+// ContentJSON encodes the item like MarshalJSON with an empty ID, so items
+// that only differ in identity encode alike, e.g. for content hashes
+func (i Item) ContentJSON() ([]byte, error) {
+	doc := i.document()
+	doc.ID = ""
+	return json.Marshal(doc)
+}
+
+// document returns the JSON document of the item
+func (i Item) document() itemJSON {
+	weight := i.weight
+	doc := itemJSON{
+		ID:          i.id,
+		Name:        i.Name,
+		Quantity:    i.quantity,
+		Condition:   i.condition.String(),
+		Description: i.description,
+		Weight:      &weight,
+		Rarity:      i.rarity,
+		Value:       i.value,
+		ArmorBonus:  i.armorBonus,
+		Slot:        i.slot,
+		Consumable:  i.consumable,
+		Effect:      i.effect,
+	}
+	if i.abilities != nil {
+		doc.Abilities = i.abilities.OrderedAbilities()
+	}
+	return doc
+}
+
+// UnmarshalJSON rebuilds an item through NewItem, so decoded items pass the
+// same validation as created ones
+func (i *Item) UnmarshalJSON(b []byte) error {
+	var doc itemJSON
+	if err := json.Unmarshal(b, &doc); err != nil {
+		return err
+	}
+
+	var itemAbilities *abilities.Abilities
+	if doc.Abilities != nil {
+		values := doc.Abilities.Map()
+		abs := abilities.NewItemAbilities(values["strength"], values["luck"],
+			values["charisma"], values["agility"],
+			values["perception"], values["intelligence"])
+		itemAbilities = &abs
+	}
+	if doc.Rarity == "" {
+		doc.Rarity = DefaultItemRarity
+	}
+	if doc.Slot == "" {
+		doc.Slot = DefaultItemSlot
+	}
+	item, err := NewItem(doc.Name, doc.Quantity, itemAbilities, condition.NewCondition(doc.Condition), doc.Description, doc.Rarity, doc.Value, doc.ArmorBonus, doc.Slot)
+	if err != nil {
+		return fmt.Errorf("item %s: %w", doc.Name, err)
+	}
+	if doc.Weight != nil {
+		if err := item.SetWeight(*doc.Weight); err != nil {
+			return fmt.Errorf("item %s: %w", doc.Name, err)
+		}
+	}
+	item.SetConsumable(doc.Consumable)
+	if err := item.SetEffect(doc.Effect); err != nil {
+		return fmt.Errorf("item %s: %w", doc.Name, err)
+	}
+
+	if doc.ID != "" {
+		item.id = doc.ID
+	}
+
+	*i = item
+	return nil
+}
+
+// MarshalJSON encodes the inventory as an items array and its gold
+func (inv Inventory) MarshalJSON() ([]byte, error) {
+	doc := inventoryJSON{Items: inv.Items, Gold: inv.gold}
+	if doc.Items == nil {
+		doc.Items = []Item{}
+	}
+	return json.Marshal(doc)
+}
+
+// UnmarshalJSON rebuilds the inventory from its items array and gold. Items
+// go through AddItem, so duplicate entries are stacked, and negative gold is
+// rejected
+func (inv *Inventory) UnmarshalJSON(b []byte) error {
+	var doc inventoryJSON
+	if err := json.Unmarshal(b, &doc); err != nil {
+		return err
+	}
+
+	decoded := NewInventory()
+	for _, item := range doc.Items {
+		decoded.AddItem(item)
+	}
+	if err := decoded.AddGold(doc.Gold); err != nil {
+		return err
+	}
+	*inv = *decoded
+	return nil
+}
@@ -0,0 +1,84 @@
+package inventory
+
+import (
+	"fmt"
+	"log/slog"
+
+	"dnd-helper/src/uuid"
+)
+
+// takeParts returns copies of the first quantity units of name as they
+// would be taken, stack by stack in inventory order, each part with its
+// stack's condition and a new ID. Nothing is changed
+func (inv *Inventory) takeParts(name string, quantity int) ([]Item, error) {
+	if quantity <= 0 {
+		return nil, fmt.Errorf("quantity %d to take must be positive", quantity)
+	}
+	var parts []Item
+	found, remaining := false, quantity
+	for _, item := range inv.Items {
+		if item.Name != name {
+			continue
+		}
+		found = true
+		if remaining == 0 || item.quantity == 0 {
+			continue
+		}
+		part := item.clone()
+		part.id = uuid.New()
+		part.quantity = min(item.quantity, remaining)
+		remaining -= part.quantity
+		parts = append(parts, part)
+	}
+	if !found {
+		return nil, fmt.Errorf("item %s not found in inventory", name)
+	}
+	if remaining > 0 {
+		return nil, fmt.Errorf("insufficient quantity: have %d, need %d", quantity-remaining, quantity)
+	}
+	return parts, nil
+}
+
+// UnitsWeight returns the weight of the first quantity units of name, the
+// units Transfer would move, or an error if there are not that many
+func (inv *Inventory) UnitsWeight(name string, quantity int) (float64, error) {
+	parts, err := inv.takeParts(name, quantity)
+	if err != nil {
+		return 0, err
+	}
+	weight := 0.0
+	for _, part := range parts {
+		weight += part.GetTotalWeight()
+	}
+	return weight, nil
+}
+
+// Transfer moves quantity units of name from one inventory to another,
+// taking them stack by stack like RemoveItem. Each part keeps the condition
+// of its stack, and the remainder of a partly taken stack stays behind
+// unchanged. Everything is checked before either inventory changes, and a
+// failure leaves both as they were. Capacity limits belong to the owners
+// of the inventories, so callers check them first, e.g. with UnitsWeight
+func Transfer(from, to *Inventory, name string, quantity int) error {
+	if from == nil || to == nil {
+		return fmt.Errorf("cannot transfer %s without both inventories", name)
+	}
+	if from == to {
+		return fmt.Errorf("cannot transfer %s to the same inventory", name)
+	}
+	parts, err := from.takeParts(name, quantity)
+	if err != nil {
+		return err
+	}
+
+	before := from.Clone()
+	if err := from.RemoveItem(name, quantity); err != nil {
+		*from = *before
+		return err
+	}
+	for _, part := range parts {
+		to.AddItem(part)
+	}
+	slog.Debug("Item transferred", "item", name, "quantity", quantity)
+	return nil
+}
@@ -0,0 +1,225 @@
+package party
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"sync"
+)
+
+var (
+	// ErrNotFound is returned when no party has the requested name
+	ErrNotFound = errors.New("party not found")
+	// ErrExists is returned when creating a party whose name is taken
+	ErrExists = errors.New("party already exists")
+	// ErrAlreadyInParty is returned when a character joins a second party
+	ErrAlreadyInParty = errors.New("character already belongs to a party")
+)
+
+// Party is a named group of characters, referenced by ID
+type Party struct {
+	Name    string
+	members []string
+}
+
+// New creates an empty party
+func New(name string) (*Party, error) {
+	if name == "" {
+		return nil, fmt.Errorf("party name cannot be empty")
+	}
+	return &Party{Name: name}, nil
+}
+
+// AddMember adds a character ID to the party. Adding a member twice fails
+func (p *Party) AddMember(id string) error {
+	if id == "" {
+		return fmt.Errorf("member id cannot be empty")
+	}
+	if p.Has(id) {
+		return fmt.Errorf("%w: %s is already in %s", ErrAlreadyInParty, id, p.Name)
+	}
+	p.members = append(p.members, id)
+	slog.Info("Character joined party", "character", id, "party", p.Name)
+	return nil
+}
+
+// RemoveMember removes a character ID from the party
+func (p *Party) RemoveMember(id string) error {
+	for i, member := range p.members {
+		if member == id {
+			p.members = append(p.members[:i:i], p.members[i+1:]...)
+			slog.Info("Character left party", "character", id, "party", p.Name)
+			return nil
+		}
+	}
+	return fmt.Errorf("%s is not a member of %s", id, p.Name)
+}
+
+// Has checks if a character ID is a member
+func (p *Party) Has(id string) bool {
+	for _, member := range p.members {
+		if member == id {
+			return true
+		}
+	}
+	return false
+}
+
+// Members returns the member IDs in the order they joined
+func (p *Party) Members() []string {
+	return append([]string{}, p.members...)
+}
+
+// clone returns a copy of the party that shares no members with the original
+func (p *Party) clone() *Party {
+	return &Party{Name: p.Name, members: p.Members()}
+}
+
+// MarshalJSON encodes the party with its member IDs
+func (p *Party) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Name    string   `json:"name"`
+		Members []string `json:"members"`
+	}{p.Name, p.Members()})
+}
+
+// Registry holds the parties and makes sure a character belongs to at most
+// one of them. It is safe for concurrent use and hands out copies
+type Registry struct {
+	mu      sync.RWMutex
+	parties map[string]*Party
+}
+
+// NewRegistry creates an empty registry
+func NewRegistry() *Registry {
+	return &Registry{parties: map[string]*Party{}}
+}
+
+// Create registers a new party with the given members. Members already in
+// another party make the whole call fail with ErrAlreadyInParty
+func (r *Registry) Create(name string, members ...string) (*Party, error) {
+	p, err := New(name)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.parties[name]; ok {
+		return nil, fmt.Errorf("%w: %s", ErrExists, name)
+	}
+	for _, id := range members {
+		if other, ok := r.partyOf(id); ok {
+			return nil, fmt.Errorf("%w: %s is in %s", ErrAlreadyInParty, id, other)
+		}
+		if err := p.AddMember(id); err != nil {
+			return nil, err
+		}
+	}
+	r.parties[name] = p
+	slog.Info("Party created", "party", name, "members", len(members))
+	return p.clone(), nil
+}
+
+// Get returns a copy of the party with the given name
+func (r *Registry) Get(name string) (*Party, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	p, ok := r.parties[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, name)
+	}
+	return p.clone(), nil
+}
+
+// List returns copies of every party sorted by name
+func (r *Registry) List() []*Party {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	list := make([]*Party, 0, len(r.parties))
+	for _, p := range r.parties {
+		list = append(list, p.clone())
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list
+}
+
+// Delete removes a party, its members become free to join another one
+func (r *Registry) Delete(name string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.parties[name]; !ok {
+		return fmt.Errorf("%w: %s", ErrNotFound, name)
+	}
+	delete(r.parties, name)
+	slog.Info("Party deleted", "party", name)
+	return nil
+}
+
+// AddMember adds a character to a party. A character in any party,
+// including this one, fails with ErrAlreadyInParty
+func (r *Registry) AddMember(name string, id string) (*Party, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	p, ok := r.parties[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, name)
+	}
+	if other, ok := r.partyOf(id); ok {
+		return nil, fmt.Errorf("%w: %s is in %s", ErrAlreadyInParty, id, other)
+	}
+	if err := p.AddMember(id); err != nil {
+		return nil, err
+	}
+	return p.clone(), nil
+}
+
+// RemoveMember removes a character from a party
+func (r *Registry) RemoveMember(name string, id string) (*Party, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	p, ok := r.parties[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, name)
+	}
+	if err := p.RemoveMember(id); err != nil {
+		return nil, err
+	}
+	return p.clone(), nil
+}
+
+// PartyOf returns the name of the party a character belongs to
+func (r *Registry) PartyOf(id string) (string, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.partyOf(id)
+}
+
+// partyOf is PartyOf for callers holding the lock
+func (r *Registry) partyOf(id string) (string, bool) {
+	for name, p := range r.parties {
+		if p.Has(id) {
+			return name, true
+		}
+	}
+	return "", false
+}
+
+// RemoveFromAll removes a character from every party, e.g. after it was
+// deleted, and returns the names of the parties it left
+func (r *Registry) RemoveFromAll(id string) []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	left := []string{}
+	for name, p := range r.parties {
+		if p.Has(id) {
+			// Has was checked, so RemoveMember cannot fail
+			_ = p.RemoveMember(id)
+			left = append(left, name)
+		}
+	}
+	sort.Strings(left)
+	return left
+}
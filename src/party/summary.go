@@ -0,0 +1,69 @@
+package party
+
+import (
+	"fmt"
+	"math/rand"
+
+	"dnd-helper/src/character"
+	"dnd-helper/src/rules"
+)
+
+// Summary aggregates the members of a party
+type Summary struct {
+	Name               string  `json:"name"`
+	Members            int     `json:"members"`
+	AverageLevel       float64 `json:"averageLevel"`
+	AveragePowerRating float64 `json:"averagePowerRating"`
+	TotalWeight        float64 `json:"totalWeight"`
+}
+
+// Summarize aggregates the loaded members of p. Power ratings use the
+// effective abilities, so equipped items count
+func Summarize(p *Party, members []character.Character) Summary {
+	summary := Summary{Name: p.Name, Members: len(members)}
+	if len(members) == 0 {
+		return summary
+	}
+	levels, power := 0, 0
+	for i := range members {
+		levels += members[i].GetLevel()
+		effective := members[i].GetEffectiveAbilities()
+		power += effective.PowerRating()
+		inventory := members[i].GetInventory()
+		summary.TotalWeight += inventory.GetTotalWeight()
+	}
+	summary.AverageLevel = float64(levels) / float64(len(members))
+	summary.AveragePowerRating = float64(power) / float64(len(members))
+	return summary
+}
+
+// MemberCheck is the skill check of a single party member
+type MemberCheck struct {
+	ID     string                 `json:"id"`
+	Name   string                 `json:"name"`
+	Result rules.SkillCheckResult `json:"result"`
+}
+
+// CheckResult is the outcome of a party check
+type CheckResult struct {
+	Checks []MemberCheck `json:"checks"`
+	// Passed lists the names of the members who succeeded
+	Passed []string `json:"passed"`
+}
+
+// PartyCheck rolls the same skill check for every member against dc, see
+// character.Character.SkillCheck
+func PartyCheck(members []character.Character, ruleset *rules.Ruleset, ability string, dc int, rng *rand.Rand) (CheckResult, error) {
+	result := CheckResult{Checks: []MemberCheck{}, Passed: []string{}}
+	for i := range members {
+		check, err := members[i].SkillCheck(ruleset, ability, dc, rng)
+		if err != nil {
+			return CheckResult{}, fmt.Errorf("%s: %w", members[i].GetName(), err)
+		}
+		result.Checks = append(result.Checks, MemberCheck{ID: members[i].GetID(), Name: members[i].GetName(), Result: check})
+		if check.Success {
+			result.Passed = append(result.Passed, members[i].GetName())
+		}
+	}
+	return result, nil
+}
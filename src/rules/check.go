@@ -0,0 +1,68 @@
+package rules
+
+import (
+	"fmt"
+	"math/rand"
+
+	"dnd-helper/src/abilities"
+	"dnd-helper/src/dice"
+)
+
+// d20 is the die every check is rolled with
+var d20 = dice.Expression{Count: 1, Sides: 20}
+
+// SkillCheckResult is the breakdown of a single ability check
+type SkillCheckResult struct {
+	Ability     string         `json:"ability"`
+	DC          int            `json:"dc"`
+	Natural     int            `json:"natural"`
+	Modifier    int            `json:"modifier"`
+	Proficiency int            `json:"proficiency,omitempty"`
+	Total       int            `json:"total"`
+	Success     bool           `json:"success"`
+	Critical    bool           `json:"critical"`
+	CritRange   CritRange      `json:"critRange"`
+	Fumble      *FumbleOutcome `json:"fumble,omitempty"`
+}
+
+// SkillCheck rolls a d20, adds the ability's modifier and compares the
+// total against dc. A natural roll within the crit range always succeeds and
+// a natural 1 always fails, rolling on the fumble table
+func (r *Ruleset) SkillCheck(abs *abilities.Abilities, ability string, dc int, rng *rand.Rand) (SkillCheckResult, error) {
+	return r.SkillCheckWithProficiency(abs, ability, 0, dc, rng)
+}
+
+// SkillCheckWithProficiency is SkillCheck with a proficiency bonus added to
+// the total
+func (r *Ruleset) SkillCheckWithProficiency(abs *abilities.Abilities, ability string, proficiency int, dc int, rng *rand.Rand) (SkillCheckResult, error) {
+	modifier, err := abs.Modifier(ability)
+	if err != nil {
+		return SkillCheckResult{}, err
+	}
+	if dc < 1 {
+		return SkillCheckResult{}, fmt.Errorf("difficulty class must be positive, got %d", dc)
+	}
+
+	natural, _ := d20.Roll(rng)
+	result := SkillCheckResult{
+		Ability:     ability,
+		DC:          dc,
+		Natural:     natural,
+		Modifier:    modifier,
+		Proficiency: proficiency,
+		Total:       natural + modifier + proficiency,
+		CritRange:   r.CritRangeFor(abs),
+	}
+	switch {
+	case result.CritRange.IsCrit(natural):
+		result.Success, result.Critical = true, true
+	case natural == FumbleRoll:
+		fumble, err := r.RollFumble(rng)
+		if err == nil {
+			result.Fumble = &fumble
+		}
+	default:
+		result.Success = result.Total >= dc
+	}
+	return result, nil
+}
@@ -0,0 +1,154 @@
+package rules
+
+import (
+	"fmt"
+	"log/slog"
+	"math/rand"
+
+	"dnd-helper/src/abilities"
+)
+
+const (
+	// DefaultCritThreshold is the lowest natural d20 roll that crits without modifiers
+	DefaultCritThreshold = 20
+	// FumbleRoll is the natural d20 roll that triggers the fumble table
+	FumbleRoll = 1
+)
+
+// CritModifier widens the crit range when an ability is high enough,
+// e.g. luck 9–10 crits on 19–20
+type CritModifier struct {
+	Ability   string `json:"ability"`
+	MinScore  int    `json:"minScore"`
+	Threshold int    `json:"threshold"`
+}
+
+// CritRange describes which crit range applied to a roll
+type CritRange struct {
+	Threshold int           `json:"threshold"`
+	Modifier  *CritModifier `json:"modifier,omitempty"`
+}
+
+// FumbleOutcome is a weighted entry of a fumble table
+type FumbleOutcome struct {
+	Name        string `json:"name"`
+	Weight      int    `json:"weight"`
+	Description string `json:"description"`
+}
+
+// Fumble outcome names the game knows how to apply
+const (
+	FumbleDropWeapon     = "drop weapon"
+	FumbleHitAlly        = "hit ally"
+	FumbleLoseNextAction = "lose next action"
+)
+
+// Ruleset holds the crit and fumble rules of a campaign
+type Ruleset struct {
+	CritModifiers []CritModifier  `json:"critModifiers"`
+	FumbleTable   []FumbleOutcome `json:"fumbleTable"`
+}
+
+// DefaultRuleset expands the crit range with high luck and uses the
+// standard fumble table
+func DefaultRuleset() *Ruleset {
+	return &Ruleset{
+		CritModifiers: []CritModifier{
+			{Ability: "luck", MinScore: 9, Threshold: 19},
+		},
+		FumbleTable: []FumbleOutcome{
+			{Name: FumbleDropWeapon, Weight: 2, Description: "The weapon slips and falls to the ground"},
+			{Name: FumbleHitAlly, Weight: 1, Description: "The blow lands on the nearest ally"},
+			{Name: FumbleLoseNextAction, Weight: 3, Description: "Off balance, the next action is lost"},
+		},
+	}
+}
+
+// NewRuleset validates crit modifiers and fumble outcomes like
+// AddCritModifier and AddFumbleOutcome and returns a ruleset holding them
+func NewRuleset(mods []CritModifier, fumbles []FumbleOutcome) (*Ruleset, error) {
+	r := &Ruleset{CritModifiers: []CritModifier{}, FumbleTable: []FumbleOutcome{}}
+	for _, mod := range mods {
+		if err := r.AddCritModifier(mod); err != nil {
+			return nil, err
+		}
+	}
+	for _, outcome := range fumbles {
+		if err := r.AddFumbleOutcome(outcome); err != nil {
+			return nil, err
+		}
+	}
+	return r, nil
+}
+
+// Clone returns a copy of the ruleset that shares no storage with the
+// original
+func (r *Ruleset) Clone() *Ruleset {
+	return &Ruleset{
+		CritModifiers: append([]CritModifier{}, r.CritModifiers...),
+		FumbleTable:   append([]FumbleOutcome{}, r.FumbleTable...),
+	}
+}
+
+// AddCritModifier validates and registers a crit range modifier
+func (r *Ruleset) AddCritModifier(mod CritModifier) error {
+	if mod.MinScore < abilities.MinAbilityValue || mod.MinScore > abilities.MaxAbilityValue {
+		return fmt.Errorf("crit modifier min score %d must be in range [%d, %d]",
+			mod.MinScore, abilities.MinAbilityValue, abilities.MaxAbilityValue)
+	}
+	if mod.Threshold < 2 || mod.Threshold > DefaultCritThreshold {
+		return fmt.Errorf("crit threshold %d must be in range [2, %d]", mod.Threshold, DefaultCritThreshold)
+	}
+	r.CritModifiers = append(r.CritModifiers, mod)
+	return nil
+}
+
+// AddFumbleOutcome validates and registers a fumble table entry
+func (r *Ruleset) AddFumbleOutcome(outcome FumbleOutcome) error {
+	if outcome.Name == "" {
+		return fmt.Errorf("fumble outcome name cannot be empty")
+	}
+	if outcome.Weight <= 0 {
+		return fmt.Errorf("fumble outcome %s weight must be positive", outcome.Name)
+	}
+	r.FumbleTable = append(r.FumbleTable, outcome)
+	return nil
+}
+
+// CritRangeFor returns the widest crit range the abilities qualify for
+func (r *Ruleset) CritRangeFor(abs *abilities.Abilities) CritRange {
+	result := CritRange{Threshold: DefaultCritThreshold}
+	all := abs.GetAllAbilities()
+	for i, mod := range r.CritModifiers {
+		if score, ok := all[mod.Ability]; ok && score >= mod.MinScore && mod.Threshold < result.Threshold {
+			result = CritRange{Threshold: mod.Threshold, Modifier: &r.CritModifiers[i]}
+		}
+	}
+	return result
+}
+
+// IsCrit checks if a natural d20 roll is within the crit range
+func (cr CritRange) IsCrit(natural int) bool {
+	return natural >= cr.Threshold
+}
+
+// RollFumble picks a weighted outcome from the fumble table
+func (r *Ruleset) RollFumble(rng *rand.Rand) (FumbleOutcome, error) {
+	total := 0
+	for _, outcome := range r.FumbleTable {
+		total += outcome.Weight
+	}
+	if total <= 0 {
+		return FumbleOutcome{}, fmt.Errorf("fumble table is empty")
+	}
+
+	pick := rng.Intn(total)
+	for _, outcome := range r.FumbleTable {
+		if pick < outcome.Weight {
+			slog.Debug("Fumble", "outcome", outcome.Name)
+			return outcome, nil
+		}
+		pick -= outcome.Weight
+	}
+	return FumbleOutcome{}, fmt.Errorf("fumble table is empty")
+}
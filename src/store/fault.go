@@ -0,0 +1,107 @@
+package store
+
+import (
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"dnd-helper/src/character"
+)
+
+var (
+	// ErrInjected is returned by FaultStore for the writes it fails
+	ErrInjected = errors.New("injected write failure")
+	// ErrPartialWrite is wrapped by errors of writes that failed after part
+	// of them was applied, so the stored state may already have changed
+	ErrPartialWrite = errors.New("partial write")
+)
+
+// FaultConfig tells FaultStore which failures to inject. The zero value
+// injects nothing
+type FaultConfig struct {
+	// FailEvery fails every Nth write, 0 never fails
+	FailEvery int
+	// Partial applies a failed write before reporting it, with an error
+	// wrapping ErrPartialWrite, like a disk that fills up mid-write
+	Partial bool
+	// Latency delays every call, reads included
+	Latency time.Duration
+}
+
+// Enabled reports whether the config injects any failure
+func (c FaultConfig) Enabled() bool {
+	return c.FailEvery > 0 || c.Latency > 0
+}
+
+// FaultStore is a CharacterStore and TemplateStore that injects failures
+// into the stores it wraps, to test how callers cope with a failing disk.
+// Writes are Save, Delete and SaveTemplate, counted together
+type FaultStore struct {
+	characters CharacterStore
+	templates  TemplateStore
+	config     FaultConfig
+	writes     atomic.Int64
+}
+
+// NewFaultStore wraps characters and templates
+func NewFaultStore(characters CharacterStore, templates TemplateStore, config FaultConfig) (*FaultStore, error) {
+	if config.FailEvery < 0 || config.Latency < 0 {
+		return nil, fmt.Errorf("fault config cannot be negative")
+	}
+	return &FaultStore{characters: characters, templates: templates, config: config}, nil
+}
+
+// write runs apply unless the write is one to fail. Partial failures run
+// it anyway
+func (s *FaultStore) write(op string, apply func() error) error {
+	time.Sleep(s.config.Latency)
+	n := s.writes.Add(1)
+	if s.config.FailEvery == 0 || n%int64(s.config.FailEvery) != 0 {
+		return apply()
+	}
+	if !s.config.Partial {
+		return fmt.Errorf("%s: write %d: %w", op, n, ErrInjected)
+	}
+	if err := apply(); err != nil {
+		return err
+	}
+	return fmt.Errorf("%s: write %d: %w: %w", op, n, ErrPartialWrite, ErrInjected)
+}
+
+func (s *FaultStore) Save(c character.Character) error {
+	return s.write("save "+c.GetID(), func() error { return s.characters.Save(c) })
+}
+
+func (s *FaultStore) Get(id string) (character.Character, error) {
+	time.Sleep(s.config.Latency)
+	return s.characters.Get(id)
+}
+
+func (s *FaultStore) List() ([]character.Character, error) {
+	time.Sleep(s.config.Latency)
+	return s.characters.List()
+}
+
+func (s *FaultStore) Delete(id string) error {
+	return s.write("delete "+id, func() error { return s.characters.Delete(id) })
+}
+
+func (s *FaultStore) Count() (int, error) {
+	time.Sleep(s.config.Latency)
+	return s.characters.Count()
+}
+
+func (s *FaultStore) SaveTemplate(t Template) error {
+	return s.write("save template "+t.Name, func() error { return s.templates.SaveTemplate(t) })
+}
+
+func (s *FaultStore) GetTemplate(name string) (Template, error) {
+	time.Sleep(s.config.Latency)
+	return s.templates.GetTemplate(name)
+}
+
+func (s *FaultStore) ListTemplates() ([]Template, error) {
+	time.Sleep(s.config.Latency)
+	return s.templates.ListTemplates()
+}
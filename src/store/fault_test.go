@@ -0,0 +1,93 @@
+package store
+
+import (
+	"dnd-helper/src/character"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestFaultStoreFailsEveryNthWrite(t *testing.T) {
+	for _, partial := range []bool{false, true} {
+		memory := NewMemoryStore()
+		s, err := NewFaultStore(memory, memory, FaultConfig{FailEvery: 2, Partial: partial})
+		if err != nil {
+			t.Fatal(err)
+		}
+		ann := character.NewDefaultCharacter("human", "Ann", "warrior")
+		bo := character.NewDefaultCharacter("elf", "Bo", "mage")
+		if err := s.Save(*ann); err != nil {
+			t.Fatalf("partial %v: first write failed: %v", partial, err)
+		}
+
+		err = s.Save(*bo)
+		if !errors.Is(err, ErrInjected) || errors.Is(err, ErrPartialWrite) != partial {
+			t.Fatalf("partial %v: second write error %v", partial, err)
+		}
+		if _, getErr := memory.Get(bo.GetID()); (getErr == nil) != partial {
+			t.Errorf("partial %v: failed write stored %v, want %v", partial, getErr == nil, partial)
+		}
+
+		if err := s.SaveTemplate(Template{Name: "Guard"}); err != nil {
+			t.Errorf("partial %v: third write failed: %v", partial, err)
+		}
+		if err := s.Delete(ann.GetID()); !errors.Is(err, ErrInjected) {
+			t.Errorf("partial %v: fourth write error %v, want ErrInjected", partial, err)
+		}
+	}
+}
+
+func TestFaultStoreLatency(t *testing.T) {
+	memory := NewMemoryStore()
+	s, err := NewFaultStore(memory, memory, FaultConfig{Latency: 20 * time.Millisecond})
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := time.Now()
+	if _, err := s.Count(); err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed < 20*time.Millisecond {
+		t.Errorf("call took %v, want at least the latency", elapsed)
+	}
+	if _, err := NewFaultStore(memory, memory, FaultConfig{FailEvery: -1}); err == nil {
+		t.Error("accepted a negative fail-every")
+	}
+}
+
+func TestMonitorReportsPersistentFailure(t *testing.T) {
+	memory := NewMemoryStore()
+	faulty, err := NewFaultStore(memory, memory, FaultConfig{FailEvery: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := NewMonitor(faulty, faulty)
+	ann := character.NewDefaultCharacter("human", "Ann", "warrior")
+	for i := range FailureThreshold {
+		if err := m.Ready(); err != nil {
+			t.Fatalf("not ready after %d failures: %v", i, err)
+		}
+		if err := m.Save(*ann); err == nil {
+			t.Fatal("injected failure did not fail the save")
+		}
+	}
+	if err := m.Ready(); err == nil {
+		t.Fatalf("ready after %d failed writes in a row", FailureThreshold)
+	}
+
+	// One success clears the failures of its source only
+	m.Record(SourceTemplates, errors.New("disk full"))
+	m.Record(SourceCharacters, nil)
+	if err := m.Ready(); err != nil {
+		t.Errorf("not ready after a successful write: %v", err)
+	}
+
+	// Deleting a missing character is not a store failure
+	m = NewMonitor(memory, memory)
+	for range FailureThreshold {
+		m.Delete("missing")
+	}
+	if err := m.Ready(); err != nil {
+		t.Errorf("not ready after deleting missing characters: %v", err)
+	}
+}
@@ -0,0 +1,112 @@
+package store
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"dnd-helper/src/character"
+)
+
+// FailureThreshold is how many writes in a row must fail before a Monitor
+// reports persistence as failing. A single failure may be a blip
+const FailureThreshold = 3
+
+// Sources of the writes a Monitor tracks
+const (
+	SourceCharacters = "characters"
+	SourceTemplates  = "templates"
+)
+
+// Monitor is a CharacterStore and TemplateStore that tracks the writes to
+// the stores it wraps, so readiness checks can tell a blip from persistence
+// that keeps failing. Other writes, e.g. of a file, are reported with Record
+type Monitor struct {
+	characters CharacterStore
+	templates  TemplateStore
+
+	mu       sync.Mutex
+	failures map[string]int   // writes in a row that failed, per source
+	lastErr  map[string]error // latest failure per source
+}
+
+// NewMonitor wraps characters and templates
+func NewMonitor(characters CharacterStore, templates TemplateStore) *Monitor {
+	return &Monitor{characters: characters, templates: templates, failures: map[string]int{}, lastErr: map[string]error{}}
+}
+
+// Record reports the outcome of a write to source. A success clears the
+// failures of the source
+func (m *Monitor) Record(source string, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if err == nil {
+		delete(m.failures, source)
+		delete(m.lastErr, source)
+		return
+	}
+	m.failures[source]++
+	m.lastErr[source] = err
+}
+
+// Ready returns an error naming every source whose last FailureThreshold
+// writes failed, nil if there is none
+func (m *Monitor) Ready() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var failing []string
+	for source, failures := range m.failures {
+		if failures >= FailureThreshold {
+			failing = append(failing, fmt.Sprintf("%s: %d writes in a row failed, last: %v", source, failures, m.lastErr[source]))
+		}
+	}
+	if len(failing) == 0 {
+		return nil
+	}
+	sort.Strings(failing)
+	return fmt.Errorf("persistence failing: %s", strings.Join(failing, "; "))
+}
+
+func (m *Monitor) Save(c character.Character) error {
+	err := m.characters.Save(c)
+	m.Record(SourceCharacters, err)
+	return err
+}
+
+func (m *Monitor) Get(id string) (character.Character, error) {
+	return m.characters.Get(id)
+}
+
+func (m *Monitor) List() ([]character.Character, error) {
+	return m.characters.List()
+}
+
+// Delete records only failures other than ErrNotFound, which says nothing
+// about the health of the store
+func (m *Monitor) Delete(id string) error {
+	err := m.characters.Delete(id)
+	if !errors.Is(err, ErrNotFound) {
+		m.Record(SourceCharacters, err)
+	}
+	return err
+}
+
+func (m *Monitor) Count() (int, error) {
+	return m.characters.Count()
+}
+
+func (m *Monitor) SaveTemplate(t Template) error {
+	err := m.templates.SaveTemplate(t)
+	m.Record(SourceTemplates, err)
+	return err
+}
+
+func (m *Monitor) GetTemplate(name string) (Template, error) {
+	return m.templates.GetTemplate(name)
+}
+
+func (m *Monitor) ListTemplates() ([]Template, error) {
+	return m.templates.ListTemplates()
+}
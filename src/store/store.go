@@ -0,0 +1,101 @@
+package store
+
+import (
+	"dnd-helper/src/character"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// ErrNotFound is returned when no character has the requested ID
+var ErrNotFound = errors.New("character not found")
+
+// CharacterStore persists characters by ID. Implementations must be safe for
+// concurrent use and must not share state with the characters passed in or
+// handed out, so callers change a character by getting it, changing the
+// copy and saving it back
+type CharacterStore interface {
+	// Save inserts the character or replaces the one with the same ID
+	Save(c character.Character) error
+	// Get returns the character with the ID, or an error wrapping ErrNotFound
+	Get(id string) (character.Character, error)
+	// List returns every character in insertion order
+	List() ([]character.Character, error)
+	// Delete removes the character with the ID, or returns an error
+	// wrapping ErrNotFound
+	Delete(id string) error
+	// Count returns the number of characters without loading them
+	Count() (int, error)
+}
+
+// MemoryStore is a CharacterStore and TemplateStore that keeps characters
+// and templates in memory
+type MemoryStore struct {
+	mu         sync.RWMutex
+	order      []string
+	characters map[string]character.Character
+	templates  map[string]Template
+}
+
+// NewMemoryStore creates an empty in-memory store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{characters: map[string]character.Character{}, templates: map[string]Template{}}
+}
+
+func (s *MemoryStore) Save(c character.Character) error {
+	id := c.GetID()
+	if id == "" {
+		return fmt.Errorf("cannot save character %s without an ID", c.GetName())
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, exists := s.characters[id]; !exists {
+		s.order = append(s.order, id)
+	}
+	s.characters[id] = *c.Clone()
+	return nil
+}
+
+func (s *MemoryStore) Get(id string) (character.Character, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	c, ok := s.characters[id]
+	if !ok {
+		return character.Character{}, fmt.Errorf("%w: %s", ErrNotFound, id)
+	}
+	return *c.Clone(), nil
+}
+
+func (s *MemoryStore) List() ([]character.Character, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	list := make([]character.Character, 0, len(s.order))
+	for _, id := range s.order {
+		c := s.characters[id]
+		list = append(list, *c.Clone())
+	}
+	return list, nil
+}
+
+func (s *MemoryStore) Delete(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.characters[id]; !ok {
+		return fmt.Errorf("%w: %s", ErrNotFound, id)
+	}
+	delete(s.characters, id)
+	for i, existing := range s.order {
+		if existing == id {
+			s.order = append(s.order[:i], s.order[i+1:]...)
+			break
+		}
+	}
+	return nil
+}
+
+func (s *MemoryStore) Count() (int, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return len(s.characters), nil
+}
@@ -0,0 +1,87 @@
+package store
+
+import (
+	"dnd-helper/src/character"
+	"errors"
+	"log/slog"
+	"os"
+	"testing"
+)
+
+func TestMain(m *testing.M) {
+	slog.SetDefault(slog.New(slog.DiscardHandler))
+	os.Exit(m.Run())
+}
+
+// mutate changes c in every way that could leak into a shared copy
+func mutate(t *testing.T, c *character.Character) {
+	t.Helper()
+	if err := c.SetName("Changed"); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Inventory().RemoveItem("Rations", 1); err != nil {
+		t.Fatal(err)
+	}
+	c.Inventory().Items[0].SetDescription("changed")
+	if err := c.AbilitiesRef().Drain("strength", 1); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.TakeDamage(5); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Equip("Longsword"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestMemoryStoreIsolation(t *testing.T) {
+	s := NewMemoryStore()
+	c := character.NewDefaultCharacter("human", "Ann", "warrior")
+	if err := s.Save(*c); err != nil {
+		t.Fatal(err)
+	}
+	want := c.Hash()
+
+	mutate(t, c)
+	if c.Hash() == want {
+		t.Fatal("mutate did not change the character")
+	}
+	stored, err := s.Get(c.GetID())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stored.Hash() != want {
+		t.Error("changes made after Save reached the stored character")
+	}
+
+	mutate(t, &stored)
+	again, err := s.Get(c.GetID())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again.Hash() != want {
+		t.Error("changes made after Get reached the stored character")
+	}
+
+	list, err := s.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	mutate(t, &list[0])
+	if again, _ := s.Get(c.GetID()); again.Hash() != want {
+		t.Error("changes made after List reached the stored character")
+	}
+}
+
+func TestMemoryStoreNotFound(t *testing.T) {
+	s := NewMemoryStore()
+	if _, err := s.Get("missing"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get: got %v, want ErrNotFound", err)
+	}
+	if err := s.Delete("missing"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Delete: got %v, want ErrNotFound", err)
+	}
+	if err := s.Save(character.Character{}); err == nil {
+		t.Error("Save accepted a character without an ID")
+	}
+}
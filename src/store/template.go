@@ -0,0 +1,68 @@
+package store
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// ErrTemplateNotFound is returned when no template has the requested name
+var ErrTemplateNotFound = errors.New("template not found")
+
+// Template is a named character definition characters can be created from.
+// The definition is kept as the JSON document it was saved with, so it is
+// validated again under the current rules every time it is used
+type Template struct {
+	Name       string          `json:"name"`
+	Definition json.RawMessage `json:"character"`
+}
+
+// TemplateStore persists templates by name. Like CharacterStore,
+// implementations must be safe for concurrent use and must not share state
+// with the templates passed in or handed out
+type TemplateStore interface {
+	// SaveTemplate inserts the template or replaces the one with the same name
+	SaveTemplate(t Template) error
+	// GetTemplate returns the template with the name, or an error wrapping
+	// ErrTemplateNotFound
+	GetTemplate(name string) (Template, error)
+	// ListTemplates returns every template sorted by name
+	ListTemplates() ([]Template, error)
+}
+
+// clone returns a copy of the template that shares no definition bytes
+func (t Template) clone() Template {
+	return Template{Name: t.Name, Definition: append(json.RawMessage{}, t.Definition...)}
+}
+
+func (s *MemoryStore) SaveTemplate(t Template) error {
+	if t.Name == "" {
+		return fmt.Errorf("template name cannot be empty")
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.templates[t.Name] = t.clone()
+	return nil
+}
+
+func (s *MemoryStore) GetTemplate(name string) (Template, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	t, ok := s.templates[name]
+	if !ok {
+		return Template{}, fmt.Errorf("%w: %s", ErrTemplateNotFound, name)
+	}
+	return t.clone(), nil
+}
+
+func (s *MemoryStore) ListTemplates() ([]Template, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	list := make([]Template, 0, len(s.templates))
+	for _, t := range s.templates {
+		list = append(list, t.clone())
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list, nil
+}
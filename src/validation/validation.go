@@ -0,0 +1,74 @@
+package validation
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// FieldError is a validation failure of a single field
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+func (e FieldError) Error() string {
+	if e.Field == "" {
+		return e.Message
+	}
+	return fmt.Sprintf("%s: %s", e.Field, e.Message)
+}
+
+// ValidationErrors collects every problem found by a validator, so callers
+// can report them all at once instead of one per round trip
+type ValidationErrors []FieldError
+
+func (errs ValidationErrors) Error() string {
+	messages := make([]string, len(errs))
+	for i, e := range errs {
+		messages[i] = e.Error()
+	}
+	return strings.Join(messages, "; ")
+}
+
+// Add records a problem of field
+func (errs *ValidationErrors) Add(field string, format string, args ...any) {
+	*errs = append(*errs, FieldError{Field: field, Message: fmt.Sprintf(format, args...)})
+}
+
+// Merge records err under prefix. The fields of ValidationErrors are nested
+// as prefix.field, any other error is recorded as a problem of prefix itself
+func (errs *ValidationErrors) Merge(prefix string, err error) {
+	if err == nil {
+		return
+	}
+	var nested ValidationErrors
+	if !errors.As(err, &nested) {
+		*errs = append(*errs, FieldError{Field: prefix, Message: err.Error()})
+		return
+	}
+	for _, e := range nested {
+		*errs = append(*errs, FieldError{Field: Join(prefix, e.Field), Message: e.Message})
+	}
+}
+
+// Err returns the collected problems as an error, or nil if there are none.
+// Validators must return Err rather than the slice, so that no problems
+// compares equal to nil
+func (errs ValidationErrors) Err() error {
+	if len(errs) == 0 {
+		return nil
+	}
+	return errs
+}
+
+// Join builds a field path, skipping empty parts
+func Join(prefix, field string) string {
+	switch {
+	case prefix == "":
+		return field
+	case field == "":
+		return prefix
+	}
+	return prefix + "." + field
+}
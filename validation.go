@@ -0,0 +1,436 @@
+package main
+
+import (
+	"bytes"
+	abts "dnd-helper/src/abilities"
+	char "dnd-helper/src/character"
+	cond "dnd-helper/src/condition"
+	inv "dnd-helper/src/inventory"
+	"dnd-helper/src/store"
+	"dnd-helper/src/validation"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+)
+
+// AbilitiesDTO is the JSON form of the six standard abilities. The string
+// "preset" may be sent instead to use the class preset
+type AbilitiesDTO struct {
+	Strength     int  `json:"strength"`
+	Luck         int  `json:"luck"`
+	Charisma     int  `json:"charisma"`
+	Agility      int  `json:"agility"`
+	Perception   int  `json:"perception"`
+	Intelligence int  `json:"intelligence"`
+	Preset       bool `json:"-"`
+}
+
+// UnmarshalJSON accepts either the six abilities or the string "preset".
+// Unknown ability names are rejected, since a misspelled ability would
+// otherwise silently count as 0
+func (dto *AbilitiesDTO) UnmarshalJSON(data []byte) error {
+	var preset string
+	if err := json.Unmarshal(data, &preset); err == nil {
+		if preset != "preset" {
+			return fmt.Errorf("abilities must be an object or \"preset\", got %q", preset)
+		}
+		*dto = AbilitiesDTO{Preset: true}
+		return nil
+	}
+
+	type plain AbilitiesDTO
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	return decoder.Decode((*plain)(dto))
+}
+
+// ClassesDTO is the JSON form of the classes of a character: either a single
+// class name or a list of {class, level} objects, primary class first
+type ClassesDTO []char.ClassLevel
+
+// UnmarshalJSON accepts a class name, which is a single class at the
+// starting level, or a list of classes with their levels
+func (dto *ClassesDTO) UnmarshalJSON(data []byte) error {
+	var class string
+	if err := json.Unmarshal(data, &class); err == nil {
+		*dto = ClassesDTO{{Class: class, Level: char.StartingLevel}}
+		return nil
+	}
+
+	var classes []char.ClassLevel
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&classes); err != nil {
+		return fmt.Errorf("class must be a string or a list of {class, level} objects: %w", err)
+	}
+	*dto = classes
+	return nil
+}
+
+// Primary returns the primary class, empty if none was given
+func (dto ClassesDTO) Primary() string {
+	if len(dto) == 0 {
+		return ""
+	}
+	return dto[0].Class
+}
+
+// ItemDTO is the JSON form of an inventory item in requests. An entry with
+// Catalog creates Quantity units of that inv.DefaultCatalog template and
+// takes no other fields
+type ItemDTO struct {
+	Catalog     string        `json:"catalog,omitempty"`
+	Name        string        `json:"name"`
+	Quantity    int           `json:"quantity"`
+	Condition   string        `json:"condition"`
+	Description string        `json:"description"`
+	Abilities   *AbilitiesDTO `json:"abilities,omitempty"`
+	// Weight is the weight of a single unit, inv.DefaultItemWeight if omitted
+	Weight *float64 `json:"weight,omitempty"`
+	// Rarity is one of inv.Rarities, inv.DefaultItemRarity if omitted
+	Rarity string `json:"rarity,omitempty"`
+	// Value is the gold value of a single unit
+	Value int `json:"value,omitempty"`
+	// ArmorBonus is the armor class bonus while equipped, 0 to inv.MaxArmorBonus
+	ArmorBonus int `json:"armorBonus,omitempty"`
+	// Slot is where the item is equipped, one of inv.ItemSlots,
+	// inv.DefaultItemSlot if omitted
+	Slot string `json:"slot,omitempty"`
+	// Consumable items are used up one unit at a time, applying Effect
+	Consumable bool            `json:"consumable,omitempty"`
+	Effect     *inv.ItemEffect `json:"effect,omitempty"`
+}
+
+// CreateCharacterRequest matches the character structure
+type CreateCharacterRequest struct {
+	Race string `json:"race"`
+	Name string `json:"name"`
+	// Class is the class name or the list of classes with their levels.
+	// Presets and class kits come from the primary (first) class
+	Class     ClassesDTO `json:"class"`
+	Inventory struct {
+		Items []ItemDTO `json:"items"`
+	} `json:"inventory"`
+	Abilities AbilitiesDTO `json:"abilities"`
+	// CustomAbilities registers homebrew abilities for this character's ruleset
+	CustomAbilities []struct {
+		Name        string `json:"name"`
+		Description string `json:"description"`
+		Default     int    `json:"default"`
+		Value       int    `json:"value"`
+	} `json:"customAbilities,omitempty"`
+	// ExtraPoints are granted into the points pool on top of the standard budget
+	ExtraPoints int    `json:"extraPoints,omitempty"`
+	Condition   string `json:"condition"`
+	// Gold is the starting gold of the character
+	Gold int `json:"gold"`
+	// UseClassKit adds the class starting kit and gold on top of the
+	// explicit items and gold
+	UseClassKit bool `json:"useClassKit,omitempty"`
+	// Alignment is one of char.Alignments, matched case-insensitively
+	Alignment string `json:"alignment,omitempty"`
+	// Biography is free text of at most char.MaxBiographyBytes
+	Biography string `json:"biography,omitempty"`
+	// Tags are lowercased and deduplicated, at most char.MaxTags
+	Tags []string `json:"tags,omitempty"`
+}
+
+// characterDraft holds the validated parts of a character request
+type characterDraft struct {
+	req       CreateCharacterRequest
+	abilities abts.Abilities
+	items     []inv.Item
+	gold      int                         // requested gold plus the class kit gold
+	alignment string                      // canonical spelling of the alignment
+	tags      []string                    // normalized tags
+	skipped   validation.ValidationErrors // invalid items, left out of items
+}
+
+// build creates the character of a validated draft
+func (draft characterDraft) build() *char.Character {
+	req := draft.req
+
+	// Create inventory and add items
+	inventory := inv.NewInventory()
+	for _, item := range draft.items {
+		inventory.AddItem(item)
+	}
+
+	// Create condition and character
+	condition := cond.NewCondition(req.Condition)
+	character := char.NewCharacter(req.Race, req.Name, req.Class.Primary(), draft.abilities, *inventory, condition)
+	// The classes were validated with the request. The character starts at
+	// level 1 of the primary class, so every other level is added on top
+	for i, class := range req.Class {
+		levels := class.Level
+		if i == 0 {
+			levels -= char.StartingLevel
+		}
+		if levels > 0 {
+			character.AddClass(class.Class, levels)
+		}
+	}
+	if len(req.Class) > 1 {
+		character.RestoreMana(character.GetMaxManaPoints())
+	}
+	character.AddGold(draft.gold)
+	// The flavor fields were validated with the request
+	character.SetAlignment(draft.alignment)
+	character.SetBiography(req.Biography)
+	character.SetTags(draft.tags)
+	return character
+}
+
+// validateCharacterRequest checks every part of a character request before
+// anything is created and returns all problems at once. Invalid items are
+// reported in draft.skipped rather than in problems, so the caller decides
+// whether they are fatal. Every path that turns requests into characters
+// must go through here so their validation never drifts
+func validateCharacterRequest(path string, req CreateCharacterRequest) (characterDraft, validation.ValidationErrors) {
+	draft := characterDraft{req: req}
+	var problems validation.ValidationErrors
+
+	descriptors := []struct{ field, value string }{
+		{"name", req.Name},
+		{"race", req.Race},
+		{"class", req.Class.Primary()},
+	}
+	for _, d := range descriptors {
+		if d.value == "" {
+			problems.Add(path+"."+d.field, "%s cannot be empty", d.field)
+		}
+	}
+	seenClasses := map[string]bool{}
+	for i, class := range req.Class {
+		classPath := fmt.Sprintf("%s.class[%d]", path, i)
+		if class.Class == "" {
+			if i > 0 {
+				problems.Add(classPath+".class", "class cannot be empty")
+			}
+			continue
+		}
+		if class.Level < char.StartingLevel {
+			problems.Add(classPath+".level", "level %d must be at least %d", class.Level, char.StartingLevel)
+		}
+		if seenClasses[strings.ToLower(class.Class)] {
+			problems.Add(classPath+".class", "class %s is listed twice", class.Class)
+		}
+		seenClasses[strings.ToLower(class.Class)] = true
+	}
+	if req.Name != "" {
+		if err := char.ValidateName(req.Name); err != nil {
+			problems.Merge(path+".name", err)
+		}
+	}
+	if req.Condition != "" {
+		if _, ok := cond.DefaultRegistry.Lookup(req.Condition); !ok {
+			problems.Add(path+".condition", "unknown condition %s", req.Condition)
+		}
+	}
+
+	if req.Gold < 0 {
+		problems.Add(path+".gold", "gold %d cannot be negative", req.Gold)
+	}
+
+	if alignment, err := char.NormalizeAlignment(req.Alignment); err != nil {
+		problems.Merge(path+".alignment", err)
+	} else {
+		draft.alignment = alignment
+	}
+	if err := char.ValidateBiography(req.Biography); err != nil {
+		problems.Merge(path+".biography", err)
+	}
+	if tags, err := char.NormalizeTags(req.Tags); err != nil {
+		problems.Merge(path+".tags", err)
+	} else {
+		draft.tags = tags
+	}
+
+	var abilities abts.Abilities
+	var err error
+	if req.Abilities.Preset {
+		// Classes without a preset get the flat default spread
+		preset, ok := abts.PresetFor(req.Class.Primary())
+		if !ok {
+			preset = abts.NewDefaultAbilities()
+		}
+		abilities = preset
+		if len(req.CustomAbilities) > 0 {
+			problems.Add(path+".customAbilities", "custom abilities cannot be combined with preset abilities")
+		}
+	} else {
+		scores := map[string]int{
+			"strength":     req.Abilities.Strength,
+			"luck":         req.Abilities.Luck,
+			"charisma":     req.Abilities.Charisma,
+			"agility":      req.Abilities.Agility,
+			"perception":   req.Abilities.Perception,
+			"intelligence": req.Abilities.Intelligence,
+		}
+
+		// Register custom abilities into a per-character ruleset
+		var registry *abts.AbilityRegistry
+		if len(req.CustomAbilities) > 0 {
+			registry = abts.NewAbilityRegistry()
+			for i, custom := range req.CustomAbilities {
+				def := abts.AbilityDefinition{Name: custom.Name, Description: custom.Description, Default: custom.Default}
+				if err := registry.Register(def); err != nil {
+					problems.Merge(fmt.Sprintf("%s.customAbilities[%d]", path, i), err)
+					continue
+				}
+				scores[custom.Name] = custom.Value
+			}
+		}
+
+		abilities, err = abts.NewAbilitiesWithRegistry(registry, scores)
+		if err != nil {
+			problems.Merge(path+".abilities", err)
+		}
+	}
+	if err == nil {
+		if err := char.ApplyRaceLimits(&abilities, req.Race); err != nil {
+			problems.Merge(path+".abilities", err)
+		} else if err := abilities.GrantPoints(req.ExtraPoints); err != nil {
+			problems.Merge(path+".extraPoints", err)
+		}
+	}
+	draft.abilities = abilities
+
+	for i, itemDTO := range req.Inventory.Items {
+		itemPath := fmt.Sprintf("%s.inventory.items[%d]", path, i)
+		item, itemProblems := validateItemDTO(itemPath, itemDTO)
+		if len(itemProblems) > 0 {
+			draft.skipped = append(draft.skipped, itemProblems...)
+			continue
+		}
+		draft.items = append(draft.items, item)
+	}
+
+	draft.gold = req.Gold
+	if req.UseClassKit {
+		kit, gold := char.ClassKit(req.Class.Primary())
+		draft.items = append(draft.items, kit...)
+		draft.gold += gold
+	}
+
+	// The starting load must fit the submitted strength
+	weight := 0.0
+	for _, item := range draft.items {
+		weight += item.GetTotalWeight()
+	}
+	if capacity := float64(char.CarryCapacityFor(draft.abilities.GetStrength())); weight > capacity {
+		problems.Add(path+".inventory", "starting items weigh %.1f, %.1f over the carry capacity of %.0f",
+			weight, weight-capacity, capacity)
+	}
+
+	if len(problems) > 0 {
+		slog.Info("Character request failed validation", "character", req.Name, "path", path, "problems", len(problems), "error", problems)
+	}
+	return draft, problems
+}
+
+// validateTemplate validates the definition of a template as a character
+// request. A non-empty name replaces the name of the definition, which
+// falls back to the template name. Invalid items are problems, since a
+// template has to create its full inventory
+func validateTemplate(template store.Template, name string) (characterDraft, validation.ValidationErrors) {
+	var problems validation.ValidationErrors
+	var req CreateCharacterRequest
+	decoder := json.NewDecoder(bytes.NewReader(template.Definition))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&req); err != nil {
+		problems.Add("character", "%v", err)
+		return characterDraft{}, problems
+	}
+	if name != "" {
+		req.Name = name
+	}
+	if req.Name == "" {
+		req.Name = template.Name
+	}
+	draft, problems := validateCharacterRequest("character", req)
+	return draft, append(problems, draft.skipped...)
+}
+
+// validateItemDTO builds an item from its request form, returning every
+// problem of the item at once
+func validateItemDTO(path string, itemDTO ItemDTO) (inv.Item, validation.ValidationErrors) {
+	var problems validation.ValidationErrors
+	if itemDTO.Catalog != "" {
+		rest := itemDTO
+		rest.Catalog, rest.Quantity = "", 0
+		if rest != (ItemDTO{}) {
+			problems.Add(path, "catalog item %s takes only a quantity", itemDTO.Catalog)
+			return inv.Item{}, problems
+		}
+		item, err := inv.DefaultCatalog.Create(itemDTO.Catalog, itemDTO.Quantity)
+		var unknown *inv.UnknownCatalogItemError
+		if errors.As(err, &unknown) {
+			problems.Merge(path+".catalog", err)
+		} else {
+			problems.Merge(path, err)
+		}
+		if len(problems) > 0 {
+			return inv.Item{}, problems
+		}
+		return item, nil
+	}
+	var itemAbilities *abts.Abilities
+	if itemDTO.Abilities != nil {
+		if itemDTO.Abilities.Preset {
+			problems.Add(path+".abilities", "item %s: items have no ability presets", itemDTO.Name)
+			return inv.Item{}, problems
+		}
+		itemAbs := abts.NewItemAbilities(
+			itemDTO.Abilities.Strength,
+			itemDTO.Abilities.Luck,
+			itemDTO.Abilities.Charisma,
+			itemDTO.Abilities.Agility,
+			itemDTO.Abilities.Perception,
+			itemDTO.Abilities.Intelligence,
+		)
+		itemAbilities = &itemAbs
+	}
+
+	if itemDTO.Condition != "" {
+		if _, ok := cond.DefaultRegistry.Lookup(itemDTO.Condition); !ok {
+			problems.Add(path+".condition", "item %s: unknown condition %s", itemDTO.Name, itemDTO.Condition)
+		}
+	}
+
+	rarity := itemDTO.Rarity
+	if rarity == "" {
+		rarity = inv.DefaultItemRarity
+	}
+	slot := itemDTO.Slot
+	if slot == "" {
+		slot = inv.DefaultItemSlot
+	}
+	item, err := inv.NewItem(
+		itemDTO.Name,
+		itemDTO.Quantity,
+		itemAbilities,
+		cond.NewCondition(itemDTO.Condition),
+		itemDTO.Description,
+		rarity,
+		itemDTO.Value,
+		itemDTO.ArmorBonus,
+		slot,
+	)
+	problems.Merge(path, err)
+	if itemDTO.Weight != nil {
+		if err := item.SetWeight(*itemDTO.Weight); err != nil {
+			problems.Add(path+".weight", "item %s: %v", itemDTO.Name, err)
+		}
+	}
+	item.SetConsumable(itemDTO.Consumable)
+	if err := item.SetEffect(itemDTO.Effect); err != nil {
+		problems.Add(path+".effect", "item %s: %v", itemDTO.Name, err)
+	}
+	if len(problems) > 0 {
+		return inv.Item{}, problems
+	}
+	return item, nil
+}